@@ -0,0 +1,10 @@
+package controllers
+
+import "github.com/MayankSaxena03/Restaurant-Management-System/repository"
+
+var repos *repository.Repositories
+
+// SetRepositories sets the storage every handler reads from and writes to
+func SetRepositories(repositories *repository.Repositories) {
+	repos = repositories
+}
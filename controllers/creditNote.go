@@ -0,0 +1,375 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetCreditNotes(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	var invoiceID primitive.ObjectID
+	if id := queryParams.Get("invoiceID"); id != "" {
+		invoiceID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Invalid invoice ID")
+			return
+		}
+	}
+
+	status := queryParams.Get("status")
+	if status != "" && status != models.CreditNoteStatusPending && status != models.CreditNoteStatusApproved && status != models.CreditNoteStatusRejected {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid status")
+		return
+	}
+
+	creditNotes, err := repos.CreditNotes.List(r.Context(), invoiceID, status, int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(creditNotes)
+}
+
+func GetCreditNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	creditNoteID, _ := primitive.ObjectIDFromHex(id)
+	creditNote, err := repos.CreditNotes.Get(r.Context(), creditNoteID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Credit note not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(creditNote)
+}
+
+func CreateCreditNote(w http.ResponseWriter, r *http.Request) {
+	var creditNote models.CreditNote
+	err := json.NewDecoder(r.Body).Decode(&creditNote)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(creditNote)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body : " + err.Error())
+		return
+	}
+
+	invoice, err := repos.Invoices.Get(r.Context(), creditNote.InvoiceID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Invoice not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if len(invoice.Lines) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invoice has no lines to credit")
+		return
+	}
+
+	previous, err := repos.CreditNotes.ListByInvoice(r.Context(), invoice.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	message, status := creditInvoice(&creditNote, invoice, previous)
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	if creditNote.RefundMethod != "" && creditNote.Total > invoice.AmountPaid {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Cannot refund more than was paid, leave out the refund method to credit the bill instead")
+		return
+	}
+
+	// Numbers are handed out when the note is raised, so rejected notes
+	// keep theirs and the sequence has no gaps
+	number, err := repos.Counters.Next(r.Context(), "creditNote")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	creditNote.ID = primitive.NilObjectID
+	creditNote.Number = fmt.Sprintf("CN/%06d", number)
+	creditNote.InvoiceNumber = invoice.Number
+	creditNote.Status = models.CreditNoteStatusPending
+	// userId is set by the authentication middleware
+	creditNote.RequestedBy, _ = primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	creditNote.ReviewedBy = primitive.NilObjectID
+	creditNote.ReviewedOn = time.Time{}
+	creditNote.CreatedOn = time.Now()
+	creditNote.UpdatedOn = time.Now()
+	err = repos.CreditNotes.Create(r.Context(), &creditNote)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(creditNote)
+}
+
+func ApproveCreditNote(w http.ResponseWriter, r *http.Request) {
+	reviewCreditNote(w, r, models.CreditNoteStatusApproved)
+}
+
+func RejectCreditNote(w http.ResponseWriter, r *http.Request) {
+	reviewCreditNote(w, r, models.CreditNoteStatusRejected)
+}
+
+func reviewCreditNote(w http.ResponseWriter, r *http.Request, status string) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	creditNoteID, _ := primitive.ObjectIDFromHex(id)
+	creditNote, err := repos.CreditNotes.Get(r.Context(), creditNoteID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Credit note not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if creditNote.Status != models.CreditNoteStatusPending {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Credit note is already " + creditNote.Status)
+		return
+	}
+
+	invoice, err := repos.Invoices.Get(r.Context(), creditNote.InvoiceID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// Payments may have changed since the refund was asked for
+	if status == models.CreditNoteStatusApproved && creditNote.RefundMethod != "" && creditNote.Total > invoice.AmountPaid {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Cannot refund more than was paid on the invoice")
+		return
+	}
+
+	review := models.CreditNote{Status: status, ReviewedOn: time.Now(), UpdatedOn: time.Now()}
+	// userId is set by the authentication middleware
+	review.ReviewedBy, _ = primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	creditNote, err = repos.CreditNotes.Review(r.Context(), creditNoteID, review)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Credit note was reviewed by someone else")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	if status == models.CreditNoteStatusApproved {
+		err = settleInvoice(r.Context(), invoice)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(creditNote)
+}
+
+// creditInvoice works out the lines and totals of a credit note from the
+// invoice. Without lines it takes back whatever earlier notes left. Pending
+// and approved notes both hold on to their quantities. It returns a message
+// and status when the credit note cannot be raised.
+func creditInvoice(creditNote *models.CreditNote, invoice models.Invoice, previous []models.CreditNote) (string, int) {
+	credited := map[primitive.ObjectID]int{}
+	creditedNet := 0.0
+	creditedTotal := 0.0
+	for _, note := range previous {
+		if note.Status == models.CreditNoteStatusRejected {
+			continue
+		}
+		for _, line := range note.Lines {
+			credited[line.OrderItemID] += line.Quantity
+		}
+		creditedNet = helpers.ToFixed(creditedNet+note.SubTotal, 2)
+		creditedTotal = helpers.ToFixed(creditedTotal+note.Total, 2)
+	}
+
+	requested := creditNote.Lines
+	if len(requested) == 0 {
+		for _, line := range invoice.Lines {
+			if left := line.Quantity - credited[line.OrderItemID]; left > 0 {
+				requested = append(requested, models.CreditNoteLine{OrderItemID: line.OrderItemID, Quantity: left})
+			}
+		}
+		if len(requested) == 0 {
+			return "Invoice is already fully credited", http.StatusConflict
+		}
+	}
+
+	invoiceLines := map[primitive.ObjectID]models.InvoiceLine{}
+	for _, line := range invoice.Lines {
+		invoiceLines[line.OrderItemID] = line
+	}
+
+	creditNote.Lines = []models.CreditNoteLine{}
+	creditNote.SubTotal = 0
+	taxes := []models.InvoiceTax{}
+	taxTotal := 0.0
+	for _, request := range requested {
+		line, ok := invoiceLines[request.OrderItemID]
+		if !ok {
+			return "Order item " + request.OrderItemID.Hex() + " is not on the invoice", http.StatusBadRequest
+		}
+		before := credited[line.OrderItemID]
+		if left := line.Quantity - before; request.Quantity > left {
+			return fmt.Sprintf("Only %d of %s left to credit", left, line.FoodName), http.StatusBadRequest
+		}
+		credited[line.OrderItemID] += request.Quantity
+
+		creditLine := models.CreditNoteLine{
+			OrderItemID: line.OrderItemID,
+			FoodName:    line.FoodName,
+			UnitPrice:   line.UnitPrice,
+			Quantity:    request.Quantity,
+			Amount:      creditShare(helpers.ToFixed(line.Amount-line.Discount, 2), before, request.Quantity, line.Quantity),
+			NetAmount:   creditShare(line.NetAmount, before, request.Quantity, line.Quantity),
+		}
+		for _, tax := range line.Taxes {
+			amount := creditShare(tax.Amount, before, request.Quantity, line.Quantity)
+			creditLine.Taxes = append(creditLine.Taxes, models.InvoiceTax{Name: tax.Name, Rate: tax.Rate, Amount: amount})
+			taxTotal += amount
+		}
+		taxes = append(taxes, creditLine.Taxes...)
+		creditNote.SubTotal = helpers.ToFixed(creditNote.SubTotal+creditLine.NetAmount, 2)
+		creditNote.Lines = append(creditNote.Lines, creditLine)
+	}
+	creditNote.Taxes = helpers.SumTaxes(taxes)
+	taxTotal = helpers.ToFixed(taxTotal, 2)
+
+	// The service charge goes back in proportion to the taxable amount
+	if invoice.SubTotal > 0 {
+		before := helpers.ToFixed(invoice.ServiceCharge*creditedNet/invoice.SubTotal, 2)
+		after := helpers.ToFixed(invoice.ServiceCharge*(creditedNet+creditNote.SubTotal)/invoice.SubTotal, 2)
+		creditNote.ServiceCharge = helpers.ToFixed(after-before, 2)
+	}
+	creditNote.RoundingAdjustment = 0
+	creditNote.Total = helpers.ToFixed(creditNote.SubTotal+taxTotal+creditNote.ServiceCharge, 2)
+
+	// The note that takes back the last of the invoice makes the credits add
+	// up to its grand total
+	for _, line := range invoice.Lines {
+		if credited[line.OrderItemID] < line.Quantity {
+			return "", 0
+		}
+	}
+	total := helpers.ToFixed(invoice.GrandTotal-creditedTotal, 2)
+	creditNote.RoundingAdjustment = helpers.ToFixed(total-creditNote.Total, 2)
+	creditNote.Total = total
+	return "", 0
+}
+
+// creditShare is what crediting quantity more of a line takes back from
+// amount when credited of lineQuantity were taken back before. Working from
+// running totals keeps the credits of a line adding up to exactly its amount.
+func creditShare(amount float64, credited int, quantity int, lineQuantity int) float64 {
+	before := helpers.ToFixed(amount*float64(credited)/float64(lineQuantity), 2)
+	after := helpers.ToFixed(amount*float64(credited+quantity)/float64(lineQuantity), 2)
+	return helpers.ToFixed(after-before, 2)
+}
@@ -2,24 +2,19 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/MayankSaxena03/Restaurant-Management-System/database"
 	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var foodCollection = database.OpenCollection(database.Client, "food")
-var menuCollection = database.OpenCollection(database.Client, "menu")
-
 func GetFoods(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
 	l := queryParams.Get("limit")
@@ -32,20 +27,13 @@ func GetFoods(w http.ResponseWriter, r *http.Request) {
 	if err != nil || skip < 0 {
 		skip = 0
 	}
-	var foods []models.Food
-	cursor, err := foodCollection.Find(r.Context(), bson.M{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	foods, err := repos.Foods.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if err = cursor.All(r.Context(), &foods); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
-	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -64,15 +52,11 @@ func GetFood(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get the food from the database
-	var food models.Food
 	foodID, _ := primitive.ObjectIDFromHex(id)
-	query := bson.M{
-		"_id": foodID,
-	}
-	err := foodCollection.FindOne(r.Context(), query).Decode(&food)
+	food, err := repos.Foods.Get(r.Context(), foodID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Food not found")
 			return
@@ -109,18 +93,36 @@ func CreateFood(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//Check if menu with ID exists
-	query := bson.M{
-		"_id": food.MenuID,
+	if message := helpers.CheckModifierGroups(food.ModifierGroups); message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
 	}
-	count, err := menuCollection.CountDocuments(r.Context(), query)
+
+	message, err := checkTaxCategory(r.Context(), food.TaxCategory)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if count == 0 {
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	//Check if menu with ID exists
+	menuExists, err := repos.Menus.Exists(r.Context(), food.MenuID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if !menuExists {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode("Menu with ID does not exist")
@@ -135,7 +137,7 @@ func CreateFood(w http.ResponseWriter, r *http.Request) {
 	food.UpdatedOn = time.Now()
 
 	// Insert the food into the database
-	result, err := foodCollection.InsertOne(r.Context(), food)
+	err = repos.Foods.Create(r.Context(), &food)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -146,7 +148,7 @@ func CreateFood(w http.ResponseWriter, r *http.Request) {
 	// Return the food ID
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode("Inserted at ID : " + result.InsertedID.(primitive.ObjectID).Hex())
+	json.NewEncoder(w).Encode("Inserted at ID : " + food.ID.Hex())
 }
 
 func UpdateFood(w http.ResponseWriter, r *http.Request) {
@@ -179,18 +181,36 @@ func UpdateFood(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//Check if menu with ID exists
-	query := bson.M{
-		"_id": food.MenuID,
+	if message := helpers.CheckModifierGroups(food.ModifierGroups); message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	message, err := checkTaxCategory(r.Context(), food.TaxCategory)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
 	}
-	count, err := menuCollection.CountDocuments(r.Context(), query)
+
+	//Check if menu with ID exists
+	menuExists, err := repos.Menus.Exists(r.Context(), food.MenuID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if count == 0 {
+	if !menuExists {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode("Menu with ID does not exist")
@@ -203,17 +223,15 @@ func UpdateFood(w http.ResponseWriter, r *http.Request) {
 	//Set Time Fields
 	food.UpdatedOn = time.Now()
 
+	// Availability is only changed through /foods/{id}/availability
+	food.SoldOut = false
+	food.RemainingPortions = nil
+
 	// Update the food in the database
 	foodID, _ := primitive.ObjectIDFromHex(id)
-	query = bson.M{
-		"_id": foodID,
-	}
-	update := bson.M{
-		"$set": food,
-	}
-	result, err := foodCollection.UpdateOne(r.Context(), query, update)
+	err = repos.Foods.Update(r.Context(), foodID, food)
 	if err != nil {
-		if result.MatchedCount == 0 {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Food not found")
@@ -230,3 +248,65 @@ func UpdateFood(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode("Updated at ID : " + id)
 }
+
+// FoodAvailability 86es a food with SoldOut or brings it back.
+// RemainingPortions counts down as the food is ordered, leaving it out stops
+// counting.
+type FoodAvailability struct {
+	SoldOut           bool `json:"soldOut"`
+	RemainingPortions *int `json:"remainingPortions,omitempty" validate:"omitempty,min=0"`
+}
+
+func SetFoodAvailability(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Food ID")
+		return
+	}
+
+	var availability FoodAvailability
+	err := json.NewDecoder(r.Body).Decode(&availability)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(availability)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	foodID, _ := primitive.ObjectIDFromHex(id)
+	err = repos.Foods.SetAvailability(r.Context(), foodID, availability.SoldOut, availability.RemainingPortions)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Food not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	message := "Food is available"
+	if availability.SoldOut {
+		message = "Food is sold out"
+	} else if availability.RemainingPortions != nil {
+		message = fmt.Sprintf("Food is available, %d portions left", *availability.RemainingPortions)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(message)
+}
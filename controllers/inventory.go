@@ -0,0 +1,664 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StockAdjustment corrects the stock of an ingredient after a count, e.g.
+// for waste. A negative quantity takes stock out.
+type StockAdjustment struct {
+	Quantity float64 `json:"quantity" validate:"required"`
+	Note     string  `json:"note" validate:"max=200"`
+}
+
+// ReorderSuggestion is how much of an ingredient low on stock to buy, from
+// the supplier it was last bought from, at its last cost price
+type ReorderSuggestion struct {
+	IngredientID      primitive.ObjectID `json:"ingredientID"`
+	IngredientName    string             `json:"ingredientName"`
+	Unit              string             `json:"unit"`
+	Stock             float64            `json:"stock"`
+	LowStockThreshold float64            `json:"lowStockThreshold"`
+	OnOrder           float64            `json:"onOrder"`
+	DailyUse          float64            `json:"dailyUse"`
+	Quantity          float64            `json:"quantity"`
+	SupplierID        primitive.ObjectID `json:"supplierID,omitempty"`
+	EstimatedCost     float64            `json:"estimatedCost"`
+}
+
+func GetIngredients(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	ingredients, err := repos.Ingredients.List(r.Context(), int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ingredients)
+}
+
+func GetIngredient(w http.ResponseWriter, r *http.Request) {
+	ingredient, ok := inventoryIngredient(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+func CreateIngredient(w http.ResponseWriter, r *http.Request) {
+	var ingredient models.Ingredient
+	err := json.NewDecoder(r.Body).Decode(&ingredient)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(ingredient)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	// The opening stock is booked as a movement like any other change
+	stock := ingredient.Stock
+	ingredient.ID = primitive.NilObjectID
+	ingredient.Stock = 0
+	ingredient.CreatedOn = time.Now()
+	ingredient.UpdatedOn = time.Now()
+	err = repos.Ingredients.Create(r.Context(), &ingredient)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	if stock > 0 {
+		userID, _ := primitive.ObjectIDFromHex(r.Header.Get("userId"))
+		err = moveStock(r.Context(), []models.StockMovement{{
+			IngredientID: ingredient.ID,
+			Quantity:     stock,
+			Reason:       models.StockMovementAdjustment,
+			Note:         "Opening stock",
+			CreatedBy:    userID,
+		}})
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode("Inserted at ID : " + ingredient.ID.Hex())
+}
+
+func UpdateIngredient(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	var ingredient models.Ingredient
+	err := json.NewDecoder(r.Body).Decode(&ingredient)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(ingredient)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	ingredientID, _ := primitive.ObjectIDFromHex(id)
+	ingredient.UpdatedOn = time.Now()
+	err = repos.Ingredients.Update(r.Context(), ingredientID, ingredient)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Ingredient not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Updated at ID : " + id)
+}
+
+func GetStockMovements(w http.ResponseWriter, r *http.Request) {
+	ingredient, ok := inventoryIngredient(w, r)
+	if !ok {
+		return
+	}
+
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	movements, err := repos.StockMovements.ListByIngredient(r.Context(), ingredient.ID, int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(movements)
+}
+
+func AdjustStock(w http.ResponseWriter, r *http.Request) {
+	ingredient, ok := inventoryIngredient(w, r)
+	if !ok {
+		return
+	}
+
+	var adjustment StockAdjustment
+	err := json.NewDecoder(r.Body).Decode(&adjustment)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(adjustment)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body : " + err.Error())
+		return
+	}
+
+	// userId is set by the authentication middleware
+	userID, _ := primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	movement := models.StockMovement{
+		IngredientID: ingredient.ID,
+		Quantity:     adjustment.Quantity,
+		Reason:       models.StockMovementAdjustment,
+		Note:         adjustment.Note,
+		CreatedBy:    userID,
+	}
+	err = moveStock(r.Context(), []models.StockMovement{movement})
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	ingredient, err = repos.Ingredients.Get(r.Context(), ingredient.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+func GetLowStock(w http.ResponseWriter, r *http.Request) {
+	ingredients, err := repos.Ingredients.ListLowStock(r.Context())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if ingredients == nil {
+		ingredients = []models.Ingredient{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ingredients)
+}
+
+// GetReorderSuggestions lists what to buy of the ingredients low on stock.
+// Average daily use is worked out from the sales of the last days days
+// (30 by default) and enough is suggested to last cover days (7 by default)
+// on top of the low stock threshold, less what is already on order.
+func GetReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	days, err := strconv.Atoi(queryParams.Get("days"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	cover, err := strconv.Atoi(queryParams.Get("cover"))
+	if err != nil || cover <= 0 {
+		cover = 7
+	}
+
+	ingredients, err := repos.Ingredients.ListLowStock(r.Context())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	now := time.Now()
+	consumption, err := repos.StockMovements.Consumption(r.Context(), now.AddDate(0, 0, -days), now)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	purchaseOrders, err := repos.PurchaseOrders.ListOpen(r.Context())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	onOrder := map[primitive.ObjectID]float64{}
+	for _, purchaseOrder := range purchaseOrders {
+		for _, line := range purchaseOrder.Lines {
+			onOrder[line.IngredientID] += line.Quantity - line.Received
+		}
+	}
+
+	suggestions := []ReorderSuggestion{}
+	for _, ingredient := range ingredients {
+		dailyUse := helpers.ToFixed(consumption[ingredient.ID]/float64(days), 3)
+		quantity := helpers.ReorderQuantity(ingredient, dailyUse, onOrder[ingredient.ID], cover)
+		if quantity <= 0 {
+			continue
+		}
+		suggestions = append(suggestions, ReorderSuggestion{
+			IngredientID:      ingredient.ID,
+			IngredientName:    ingredient.Name,
+			Unit:              ingredient.Unit,
+			Stock:             ingredient.Stock,
+			LowStockThreshold: ingredient.LowStockThreshold,
+			OnOrder:           helpers.ToFixed(onOrder[ingredient.ID], 3),
+			DailyUse:          dailyUse,
+			Quantity:          quantity,
+			SupplierID:        ingredient.SupplierID,
+			EstimatedCost:     helpers.ToFixed(quantity*ingredient.CostPrice, 2),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(suggestions)
+}
+
+func GetRecipes(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	recipes, err := repos.Recipes.List(r.Context(), int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(recipes)
+}
+
+func GetRecipe(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	recipeID, _ := primitive.ObjectIDFromHex(id)
+	recipe, err := repos.Recipes.Get(r.Context(), recipeID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Recipe not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(recipe)
+}
+
+func CreateRecipe(w http.ResponseWriter, r *http.Request) {
+	var recipe models.Recipe
+	err := json.NewDecoder(r.Body).Decode(&recipe)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(recipe)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	foodExists, err := repos.Foods.Exists(r.Context(), recipe.FoodID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if !foodExists {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Food not found")
+		return
+	}
+
+	message, err := checkRecipe(r.Context(), recipe)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	recipe.ID = primitive.NilObjectID
+	recipe.CreatedOn = time.Now()
+	recipe.UpdatedOn = time.Now()
+	err = repos.Recipes.Create(r.Context(), &recipe)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Food already has a recipe")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode("Inserted at ID : " + recipe.ID.Hex())
+}
+
+func UpdateRecipe(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	var recipe models.Recipe
+	err := json.NewDecoder(r.Body).Decode(&recipe)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	// A recipe stays with its food, only the ingredients change
+	validate := validator.New()
+	err = validate.StructExcept(recipe, "FoodID")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	message, err := checkRecipe(r.Context(), recipe)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	recipeID, _ := primitive.ObjectIDFromHex(id)
+	err = repos.Recipes.Update(r.Context(), recipeID, recipe)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Recipe not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Updated at ID : " + id)
+}
+
+func inventoryIngredient(w http.ResponseWriter, r *http.Request) (models.Ingredient, bool) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return models.Ingredient{}, false
+	}
+
+	ingredientID, _ := primitive.ObjectIDFromHex(id)
+	ingredient, err := repos.Ingredients.Get(r.Context(), ingredientID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Ingredient not found")
+			return models.Ingredient{}, false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return models.Ingredient{}, false
+	}
+	return ingredient, true
+}
+
+// checkRecipe makes sure every ingredient of the recipe exists and is listed
+// once, returning what is wrong otherwise
+func checkRecipe(ctx context.Context, recipe models.Recipe) (string, error) {
+	listed := map[primitive.ObjectID]bool{}
+	for _, recipeIngredient := range recipe.Ingredients {
+		if listed[recipeIngredient.IngredientID] {
+			return "Ingredient " + recipeIngredient.IngredientID.Hex() + " is listed twice", nil
+		}
+		listed[recipeIngredient.IngredientID] = true
+
+		_, err := repos.Ingredients.Get(ctx, recipeIngredient.IngredientID)
+		if err == repository.ErrNotFound {
+			return "Ingredient " + recipeIngredient.IngredientID.Hex() + " not found", nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// depleteStock takes what the order items use up by their food's recipe out
+// of stock. Foods without a recipe use up nothing.
+func depleteStock(ctx context.Context, orderItems []models.OrderItem) error {
+	foodIDs := []primitive.ObjectID{}
+	for _, orderItem := range orderItems {
+		foodIDs = append(foodIDs, orderItem.FoodID)
+	}
+	recipes, err := repos.Recipes.ListByFoods(ctx, foodIDs)
+	if err != nil {
+		return err
+	}
+	recipesByFood := map[primitive.ObjectID]models.Recipe{}
+	for _, recipe := range recipes {
+		recipesByFood[recipe.FoodID] = recipe
+	}
+
+	movements := []models.StockMovement{}
+	for _, orderItem := range orderItems {
+		recipe, ok := recipesByFood[orderItem.FoodID]
+		if !ok {
+			continue
+		}
+		for _, recipeIngredient := range recipe.Ingredients {
+			movements = append(movements, models.StockMovement{
+				IngredientID: recipeIngredient.IngredientID,
+				Quantity:     -recipeIngredient.Quantity * float64(orderItem.Quantity),
+				Reason:       models.StockMovementSale,
+				OrderID:      orderItem.OrderID,
+				OrderItemID:  orderItem.ID,
+			})
+		}
+	}
+	return moveStock(ctx, movements)
+}
+
+// restoreStock puts back what the order's items took out of stock
+func restoreStock(ctx context.Context, orderID primitive.ObjectID) error {
+	sold, err := repos.StockMovements.ListByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	movements := []models.StockMovement{}
+	for _, movement := range sold {
+		if movement.Reason != models.StockMovementSale {
+			continue
+		}
+		movements = append(movements, models.StockMovement{
+			IngredientID: movement.IngredientID,
+			Quantity:     -movement.Quantity,
+			Reason:       models.StockMovementVoid,
+			OrderID:      movement.OrderID,
+			OrderItemID:  movement.OrderItemID,
+		})
+	}
+	return moveStock(ctx, movements)
+}
+
+// moveStock applies the movements to the stock of their ingredients and
+// records them
+func moveStock(ctx context.Context, movements []models.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+	for i := range movements {
+		movements[i].Quantity = helpers.ToFixed(movements[i].Quantity, 3)
+		movements[i].CreatedOn = time.Now()
+		err := repos.Ingredients.AdjustStock(ctx, movements[i].IngredientID, movements[i].Quantity)
+		if err != nil && err != repository.ErrNotFound {
+			return err
+		}
+	}
+	return repos.StockMovements.CreateMany(ctx, movements)
+}
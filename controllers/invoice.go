@@ -1,34 +1,44 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/MayankSaxena03/Restaurant-Management-System/database"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type InvoiceViewFormat struct {
-	ID             string      `json:"_id,omitempty" bson:"_id,omitempty"`
-	OrderID        string      `json:"orderID,omitempty" bson:"orderID,omitempty"`
-	OrderDetails   interface{} `json:"orderDetails,omitempty" bson:"orderDetails,omitempty"`
-	TableID        string      `json:"tableNumber,omitempty" bson:"tableNumber,omitempty"`
-	PaymentMethod  string      `json:"paymentMethod,omitempty" bson:"paymentMethod,omitempty"`
-	PaymentStatus  string      `json:"paymentStatus,omitempty" bson:"paymentStatus,omitempty"`
-	PaymentDue     interface{} `json:"paymentDue,omitempty" bson:"paymentDue,omitempty"`
-	PaymentDueDate time.Time   `json:"paymentDueDate,omitempty" bson:"paymentDueDate,omitempty"`
+	ID                 string                    `json:"_id,omitempty" bson:"_id,omitempty"`
+	Number             string                    `json:"number,omitempty" bson:"number,omitempty"`
+	OrderID            string                    `json:"orderID,omitempty" bson:"orderID,omitempty"`
+	OrderDetails       interface{}               `json:"orderDetails,omitempty" bson:"orderDetails,omitempty"`
+	TableID            string                    `json:"tableNumber,omitempty" bson:"tableNumber,omitempty"`
+	Lines              []models.InvoiceLine      `json:"lines,omitempty" bson:"lines,omitempty"`
+	Discount           float64                   `json:"discount,omitempty" bson:"discount,omitempty"`
+	Promotions         []models.InvoicePromotion `json:"promotions,omitempty" bson:"promotions,omitempty"`
+	SubTotal           float64                   `json:"subTotal,omitempty" bson:"subTotal,omitempty"`
+	Taxes              []models.InvoiceTax       `json:"taxes,omitempty" bson:"taxes,omitempty"`
+	ServiceCharge      float64                   `json:"serviceCharge,omitempty" bson:"serviceCharge,omitempty"`
+	RoundingAdjustment float64                   `json:"roundingAdjustment,omitempty" bson:"roundingAdjustment,omitempty"`
+	SplitGroupID       string                    `json:"splitGroupID,omitempty" bson:"splitGroupID,omitempty"`
+	SplitPart          int                       `json:"splitPart,omitempty" bson:"splitPart,omitempty"`
+	SplitParts         int                       `json:"splitParts,omitempty" bson:"splitParts,omitempty"`
+	PaymentMethod      string                    `json:"paymentMethod,omitempty" bson:"paymentMethod,omitempty"`
+	PaymentStatus      string                    `json:"paymentStatus,omitempty" bson:"paymentStatus,omitempty"`
+	AmountPaid         float64                   `json:"amountPaid,omitempty" bson:"amountPaid,omitempty"`
+	Balance            float64                   `json:"balance,omitempty" bson:"balance,omitempty"`
+	PaymentDue         interface{}               `json:"paymentDue,omitempty" bson:"paymentDue,omitempty"`
+	PaymentDueDate     time.Time                 `json:"paymentDueDate,omitempty" bson:"paymentDueDate,omitempty"`
 }
 
-var invoiceCollection = database.OpenCollection(database.Client, "invoice")
-
 func GetInvoices(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
 	l := queryParams.Get("limit")
@@ -42,20 +52,13 @@ func GetInvoices(w http.ResponseWriter, r *http.Request) {
 		skip = 0
 	}
 
-	var invoices []models.Invoice
-	cursor, err := invoiceCollection.Find(r.Context(), bson.M{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	invoices, err := repos.Invoices.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if err = cursor.All(r.Context(), &invoices); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
-	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -73,10 +76,9 @@ func GetInvoice(w http.ResponseWriter, r *http.Request) {
 	}
 
 	invoiceID, _ := primitive.ObjectIDFromHex(id)
-	var invoice models.Invoice
-	err := invoiceCollection.FindOne(r.Context(), bson.M{"_id": invoiceID}).Decode(&invoice)
+	invoice, err := repos.Invoices.Get(r.Context(), invoiceID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Invoice not found")
@@ -88,22 +90,70 @@ func GetInvoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var invoiceView InvoiceViewFormat
-	allOrderItems, err := ItemsByOrder(r.Context(), invoice.OrderID)
+	writeInvoiceView(w, r, invoice)
+}
+
+func GetInvoiceByNumber(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	invoice, err := repos.Invoices.GetByNumber(r.Context(), vars["number"])
 	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Invoice not found")
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
+
+	writeInvoiceView(w, r, invoice)
+}
+
+func writeInvoiceView(w http.ResponseWriter, r *http.Request, invoice models.Invoice) {
+	var invoiceView InvoiceViewFormat
+	invoiceView.Number = invoice.Number
 	invoiceView.OrderID = invoice.OrderID.Hex()
 	invoiceView.PaymentDueDate = invoice.PaymentDueDate
 	invoiceView.PaymentMethod = invoice.PaymentMethod
 	invoiceView.ID = invoice.ID.Hex()
 	invoiceView.PaymentStatus = invoice.PaymentStatus
-	invoiceView.PaymentDue = allOrderItems[0]["paymentDue"]
-	invoiceView.TableID = allOrderItems[0]["tableID"].(string)
-	invoiceView.OrderDetails = allOrderItems[0]["orderItems"]
+	invoiceView.AmountPaid = invoice.AmountPaid
+	invoiceView.Balance = invoice.Balance
+
+	if len(invoice.Lines) > 0 {
+		// Issued invoices are rendered from their own snapshot
+		invoiceView.TableID = strconv.Itoa(invoice.TableNumber)
+		invoiceView.Lines = invoice.Lines
+		invoiceView.Discount = invoice.Discount
+		invoiceView.Promotions = invoice.Promotions
+		invoiceView.SubTotal = invoice.SubTotal
+		invoiceView.Taxes = invoice.Taxes
+		invoiceView.ServiceCharge = invoice.ServiceCharge
+		invoiceView.RoundingAdjustment = invoice.RoundingAdjustment
+		invoiceView.PaymentDue = invoice.GrandTotal
+		if !invoice.SplitGroupID.IsZero() {
+			invoiceView.SplitGroupID = invoice.SplitGroupID.Hex()
+			invoiceView.SplitPart = invoice.SplitPart
+			invoiceView.SplitParts = invoice.SplitParts
+		}
+	} else {
+		// Invoices created before snapshots existed are computed from the order
+		allOrderItems, err := ItemsByOrder(r.Context(), invoice.OrderID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		if len(allOrderItems) > 0 {
+			invoiceView.PaymentDue = allOrderItems[0].PaymentDue
+			invoiceView.TableID = strconv.Itoa(allOrderItems[0].TableNumber)
+			invoiceView.OrderDetails = allOrderItems[0].OrderItems
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -129,32 +179,120 @@ func CreateInvoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := orderCollection.CountDocuments(r.Context(), bson.M{"_id": invoice.OrderID})
+	order, err := repos.Orders.Get(r.Context(), invoice.OrderID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	message, err := checkBillable(r.Context(), order)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	err = buildInvoiceSnapshot(r.Context(), &invoice, order)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if count == 0 {
+	if len(invoice.Lines) == 0 {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode("Order not found")
+		json.NewEncoder(w).Encode("Order has no items")
 		return
 	}
 
+	message, err = checkInvoiceTaxes(r.Context(), invoice)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	err = repos.Orders.MarkBilled(r.Context(), order.ID)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Order has already been billed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = redeemCoupons(r.Context(), invoice)
+	if err != nil {
+		repos.Orders.UnmarkBilled(r.Context(), order.ID)
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("A coupon on this order has been used up")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	openInvoice(&invoice)
 	invoice.CreatedOn = time.Now()
 	invoice.UpdatedOn = time.Now()
 	invoice.PaymentDueDate = invoice.PaymentDueDate.AddDate(0, 0, 1)
-	result, err := invoiceCollection.InsertOne(r.Context(), invoice)
+	issued := []models.Invoice{invoice}
+	err = issueInvoices(r.Context(), issued)
 	if err != nil {
+		unredeemCoupons(r.Context(), invoice)
+		repos.Orders.UnmarkBilled(r.Context(), order.ID)
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Another invoice already has this number")
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
+	invoice = issued[0]
 
-	invoice.ID = result.InsertedID.(primitive.ObjectID)
+	err = setTableStatus(r.Context(), order.TableID, models.TableStatusAwaitingBill)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -180,71 +318,191 @@ func UpdateInvoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !primitive.IsValidObjectID(updatedInvoice.OrderID.Hex()) {
+	if updatedInvoice.PaymentMethod != "" || updatedInvoice.PaymentStatus != "" {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode("Invalid Order ID")
+		json.NewEncoder(w).Encode("Payment method and status follow the payments recorded on the invoice")
+		return
+	}
+
+	if updatedInvoice.PaymentDueDate.IsZero() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Payment due date is required")
 		return
 	}
 
-	count, err := orderCollection.CountDocuments(r.Context(), bson.M{"_id": updatedInvoice.OrderID})
+	// Only the due date can change, the order and amounts of an issued invoice are fixed
+	invoiceID, _ := primitive.ObjectIDFromHex(id)
+	err = repos.Invoices.UpdateDueDate(r.Context(), invoiceID, updatedInvoice.PaymentDueDate)
 	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Invoice not found")
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if count == 0 {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode("Order not found")
-		return
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Invoice updated successfully")
+}
+
+// buildInvoiceSnapshot copies the order's items, the promotions they get and
+// the amounts due into the invoice
+func buildInvoiceSnapshot(ctx context.Context, invoice *models.Invoice, order models.Order) error {
+	orderItems, err := repos.OrderItems.ListByOrder(ctx, order.ID)
+	if err != nil {
+		return err
 	}
 
-	if updatedInvoice.PaymentMethod == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode("Payment method is required")
-		return
+	foodIDs := []primitive.ObjectID{}
+	for _, orderItem := range orderItems {
+		foodIDs = append(foodIDs, orderItem.FoodID)
+	}
+	foods, err := repos.Foods.GetMany(ctx, foodIDs)
+	if err != nil {
+		return err
+	}
+	foodsByID := map[primitive.ObjectID]models.Food{}
+	for _, food := range foods {
+		foodsByID[food.ID] = food
 	}
 
-	if updatedInvoice.PaymentStatus == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode("Payment status is required")
-		return
+	taxRates, err := taxRatesByCategory(ctx)
+	if err != nil {
+		return err
 	}
 
-	if updatedInvoice.PaymentDueDate.IsZero() {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode("Payment due date is required")
-		return
+	table, err := repos.Tables.Get(ctx, order.TableID)
+	if err != nil && err != repository.ErrNotFound {
+		return err
 	}
 
-	invoiceID, _ := primitive.ObjectIDFromHex(id)
-	query := bson.M{
-		"_id": invoiceID,
+	invoice.TableNumber = table.Number
+	invoice.Lines = []models.InvoiceLine{}
+	for _, orderItem := range orderItems {
+		food := foodsByID[orderItem.FoodID]
+		line := models.InvoiceLine{
+			OrderItemID: orderItem.ID,
+			FoodID:      orderItem.FoodID,
+			FoodName:    food.Name,
+			Modifiers:   orderItem.Modifiers,
+			UnitPrice:   helpers.OrderItemUnitPrice(orderItem),
+			Quantity:    orderItem.Quantity,
+			Amount:      helpers.ToFixed(helpers.OrderItemUnitPrice(orderItem)*float64(orderItem.Quantity), 2),
+			TaxCategory: food.TaxCategory,
+		}
+		if line.TaxCategory == "" {
+			line.TaxCategory = models.TaxCategoryDefault
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	err = applyPromotions(ctx, invoice, orderItems, order)
+	if err != nil {
+		return err
 	}
-	update := bson.M{
-		"$set": bson.M{
-			"paymentMethod":  updatedInvoice.PaymentMethod,
-			"paymentStatus":  updatedInvoice.PaymentStatus,
-			"paymentDueDate": updatedInvoice.PaymentDueDate,
-			"orderID":        updatedInvoice.OrderID,
-			"updatedOn":      time.Now(),
-		},
+
+	// Tax is charged on what the line costs after its discount
+	for i, line := range invoice.Lines {
+		taxRate := taxRates[line.TaxCategory]
+		invoice.Lines[i].Inclusive = taxRate.Inclusive
+		invoice.Lines[i].NetAmount, invoice.Lines[i].Taxes = helpers.LineTax(helpers.ToFixed(line.Amount-line.Discount, 2), taxRate)
 	}
 
-	_, err = invoiceCollection.UpdateOne(r.Context(), query, update)
+	taxTotal := sumInvoiceLines(invoice)
+	invoice.ServiceCharge = helpers.Percentage(invoice.SubTotal, helpers.EnvRate("SERVICE_CHARGE_RATE"))
+	invoice.GrandTotal = helpers.ToFixed(invoice.SubTotal+taxTotal+invoice.ServiceCharge, 2)
+
+	return nil
+}
+
+// checkBillable returns why the order cannot be billed, if it cannot:
+// cancelled and voided orders were never sold, and an order is billed once,
+// either in one invoice or split into several
+func checkBillable(ctx context.Context, order models.Order) (string, error) {
+	status := orderStatusOf(order)
+	if status == models.OrderStatusCancelled || status == models.OrderStatusVoided {
+		return "A " + status + " order cannot be billed", nil
+	}
+	billed, err := repos.Invoices.ExistsByOrder(ctx, order.ID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
+		return "", err
+	}
+	if billed {
+		return "Order has already been billed", nil
 	}
+	return "", nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode("Invoice updated successfully")
+// sumInvoiceLines sets the subtotal and the tax totals of the invoice from its
+// lines and returns the total tax
+func sumInvoiceLines(invoice *models.Invoice) float64 {
+	invoice.SubTotal = 0
+	taxes := []models.InvoiceTax{}
+	taxTotal := 0.0
+	for _, line := range invoice.Lines {
+		invoice.SubTotal = helpers.ToFixed(invoice.SubTotal+line.NetAmount, 2)
+		for _, tax := range line.Taxes {
+			taxTotal += tax.Amount
+		}
+		taxes = append(taxes, line.Taxes...)
+	}
+	invoice.Taxes = helpers.SumTaxes(taxes)
+	return helpers.ToFixed(taxTotal, 2)
+}
+
+// issueInvoices numbers the invoices, all created at the same time, from the
+// counter of their outlet and period and stores them. Numbers are only used
+// up by invoices that are stored, so the sequence has no gaps.
+func issueInvoices(ctx context.Context, invoices []models.Invoice) error {
+	numbering := helpers.InvoiceNumberingFromEnv()
+	createdOn := invoices[0].CreatedOn
+	number := func(sequence int64) string {
+		return numbering.Format(createdOn, sequence)
+	}
+	return repos.Invoices.CreateNumbered(ctx, numbering.Counter(createdOn), number, invoices)
+}
+
+// openInvoice starts a new invoice with nothing paid against it
+func openInvoice(invoice *models.Invoice) {
+	invoice.PaymentMethod = ""
+	invoice.PaymentStatus = models.PaymentStatusUnpaid
+	invoice.AmountPaid = 0
+	invoice.Balance = invoice.GrandTotal
+}
+
+func freeInvoiceTable(ctx context.Context, invoiceID primitive.ObjectID) error {
+	invoice, err := repos.Invoices.Get(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	// A split bill is settled once every part is paid
+	if !invoice.SplitGroupID.IsZero() {
+		parts, err := repos.Invoices.ListBySplitGroup(ctx, invoice.SplitGroupID)
+		if err != nil {
+			return err
+		}
+		for _, part := range parts {
+			if !helpers.IsInvoiceSettled(part.PaymentStatus) {
+				return nil
+			}
+		}
+	}
+
+	order, err := repos.Orders.Get(ctx, invoice.OrderID)
+	if err == repository.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return setTableStatus(ctx, order.TableID, models.TableStatusFree)
 }
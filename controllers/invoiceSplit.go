@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SplitEvenly   = "even"
+	SplitByItem   = "items"
+	SplitByAmount = "amounts"
+)
+
+// InvoiceSplit asks for the bill of an order to be split into several
+// invoices. Parts is the number of equal parts for "even", Items lists the
+// order items of each part for "items" and Amounts is what each part pays
+// for "amounts".
+type InvoiceSplit struct {
+	OrderID        primitive.ObjectID     `json:"orderID" validate:"required"`
+	Mode           string                 `json:"mode" validate:"required,oneof=even items amounts"`
+	Parts          int                    `json:"parts,omitempty"`
+	Items          [][]primitive.ObjectID `json:"items,omitempty"`
+	Amounts        []float64              `json:"amounts,omitempty"`
+	PaymentDueDate time.Time              `json:"paymentDueDate,omitempty"`
+}
+
+func SplitInvoice(w http.ResponseWriter, r *http.Request) {
+	var split InvoiceSplit
+	err := json.NewDecoder(r.Body).Decode(&split)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(split)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body : " + err.Error())
+		return
+	}
+
+	order, err := repos.Orders.Get(r.Context(), split.OrderID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// The whole bill is worked out once and then divided, so the parts
+	// always add up to what a single invoice would have charged
+	var bill models.Invoice
+	err = buildInvoiceSnapshot(r.Context(), &bill, order)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if len(bill.Lines) == 0 || bill.GrandTotal <= 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Order has no items")
+		return
+	}
+
+	message, err := checkInvoiceTaxes(r.Context(), bill)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	var parts []models.Invoice
+	switch split.Mode {
+	case SplitEvenly:
+		parts, message = splitEvenly(bill, split.Parts)
+	case SplitByItem:
+		parts, message = splitByItem(bill, split.Items)
+	case SplitByAmount:
+		parts, message = splitByAmount(bill, split.Amounts)
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	shareDiscount(bill, parts)
+
+	message, err = checkBillable(r.Context(), order)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	err = repos.Orders.MarkBilled(r.Context(), order.ID)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Order has already been billed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = redeemCoupons(r.Context(), bill)
+	if err != nil {
+		repos.Orders.UnmarkBilled(r.Context(), order.ID)
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("A coupon on this order has been used up")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	splitGroupID := primitive.NewObjectID()
+	now := time.Now()
+	for i := range parts {
+		parts[i].OrderID = order.ID
+		parts[i].TableNumber = bill.TableNumber
+		parts[i].SplitGroupID = splitGroupID
+		parts[i].SplitPart = i + 1
+		parts[i].SplitParts = len(parts)
+		openInvoice(&parts[i])
+		parts[i].PaymentDueDate = split.PaymentDueDate.AddDate(0, 0, 1)
+		parts[i].CreatedOn = now
+		parts[i].UpdatedOn = now
+	}
+	// The parts are issued together, so if one cannot be stored none are
+	err = issueInvoices(r.Context(), parts)
+	if err != nil {
+		unredeemCoupons(r.Context(), bill)
+		repos.Orders.UnmarkBilled(r.Context(), order.ID)
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Another invoice already has this number")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = setTableStatus(r.Context(), order.TableID, models.TableStatusAwaitingBill)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(parts)
+}
+
+func GetSplitInvoices(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	splitGroupID, _ := primitive.ObjectIDFromHex(id)
+	parts, err := repos.Invoices.ListBySplitGroup(r.Context(), splitGroupID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if len(parts) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode("Split bill not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(parts)
+}
+
+func splitEvenly(bill models.Invoice, count int) ([]models.Invoice, string) {
+	if count < 2 {
+		return nil, "An even split needs at least 2 parts"
+	}
+	equal := make([]float64, count)
+	for i := range equal {
+		equal[i] = 1
+	}
+	return divideBill(bill, helpers.SplitAmount(bill.GrandTotal, equal)), ""
+}
+
+func splitByAmount(bill models.Invoice, amounts []float64) ([]models.Invoice, string) {
+	if len(amounts) < 2 {
+		return nil, "A split needs at least 2 amounts"
+	}
+	totals := []float64{}
+	sum := 0.0
+	for _, amount := range amounts {
+		amount = helpers.ToFixed(amount, 2)
+		if amount <= 0 {
+			return nil, "Amounts must be greater than 0"
+		}
+		totals = append(totals, amount)
+		sum = helpers.ToFixed(sum+amount, 2)
+	}
+	if sum != bill.GrandTotal {
+		return nil, fmt.Sprintf("Amounts add up to %.2f but the bill is %.2f", sum, bill.GrandTotal)
+	}
+	return divideBill(bill, totals), ""
+}
+
+func splitByItem(bill models.Invoice, items [][]primitive.ObjectID) ([]models.Invoice, string) {
+	if len(items) < 2 {
+		return nil, "A split needs at least 2 parts"
+	}
+
+	lines := map[primitive.ObjectID]models.InvoiceLine{}
+	for _, line := range bill.Lines {
+		lines[line.OrderItemID] = line
+	}
+
+	parts := make([]models.Invoice, len(items))
+	subTotals := make([]float64, len(items))
+	for i, orderItemIDs := range items {
+		if len(orderItemIDs) == 0 {
+			return nil, "Every part needs at least one order item"
+		}
+		for _, orderItemID := range orderItemIDs {
+			line, ok := lines[orderItemID]
+			if !ok {
+				return nil, "Order item " + orderItemID.Hex() + " is not on the bill or is in more than one part"
+			}
+			delete(lines, orderItemID)
+			parts[i].Lines = append(parts[i].Lines, line)
+		}
+		sumInvoiceLines(&parts[i])
+		subTotals[i] = parts[i].SubTotal
+	}
+	if len(lines) > 0 {
+		return nil, "Every order item must be in a part"
+	}
+
+	// Service charge is shared by what each part ordered, the rest is what
+	// the part's own lines cost
+	serviceCharges := helpers.SplitAmount(bill.ServiceCharge, subTotals)
+	totals := make([]float64, len(parts))
+	sum := 0.0
+	for i := range parts {
+		taxTotal := sumInvoiceLines(&parts[i])
+		totals[i] = helpers.ToFixed(parts[i].SubTotal+taxTotal+serviceCharges[i], 2)
+		sum = helpers.ToFixed(sum+totals[i], 2)
+	}
+	totals[len(totals)-1] = helpers.ToFixed(totals[len(totals)-1]+bill.GrandTotal-sum, 2)
+
+	for i := range parts {
+		finishSplitPart(&parts[i], serviceCharges[i], totals[i])
+	}
+	return parts, ""
+}
+
+// divideBill gives each part a share of every line, tax and the service
+// charge in proportion to what the part pays
+func divideBill(bill models.Invoice, totals []float64) []models.Invoice {
+	parts := make([]models.Invoice, len(totals))
+	for _, line := range bill.Lines {
+		amounts := helpers.SplitAmount(line.Amount, totals)
+		discounts := helpers.SplitAmount(line.Discount, totals)
+		netAmounts := helpers.SplitAmount(line.NetAmount, totals)
+		taxes := make([][]models.InvoiceTax, len(totals))
+		for _, tax := range line.Taxes {
+			for i, amount := range helpers.SplitAmount(tax.Amount, totals) {
+				taxes[i] = append(taxes[i], models.InvoiceTax{Name: tax.Name, Rate: tax.Rate, Amount: amount})
+			}
+		}
+
+		for i := range parts {
+			share := line
+			share.Amount = amounts[i]
+			share.Discount = discounts[i]
+			share.NetAmount = netAmounts[i]
+			share.Taxes = taxes[i]
+			share.Share = helpers.ToFixed(totals[i]/bill.GrandTotal, 4)
+			parts[i].Lines = append(parts[i].Lines, share)
+		}
+	}
+
+	serviceCharges := helpers.SplitAmount(bill.ServiceCharge, totals)
+	for i := range parts {
+		finishSplitPart(&parts[i], serviceCharges[i], totals[i])
+	}
+	return parts
+}
+
+// shareDiscount gives each part the discount on its own lines and a share of
+// every promotion of the bill in proportion to it
+func shareDiscount(bill models.Invoice, parts []models.Invoice) {
+	discounts := make([]float64, len(parts))
+	for i := range parts {
+		parts[i].Discount = 0
+		for _, line := range parts[i].Lines {
+			parts[i].Discount = helpers.ToFixed(parts[i].Discount+line.Discount, 2)
+		}
+		discounts[i] = parts[i].Discount
+	}
+	for _, promotion := range bill.Promotions {
+		for i, amount := range helpers.SplitAmount(promotion.Amount, discounts) {
+			if amount == 0 {
+				continue
+			}
+			share := promotion
+			share.Amount = amount
+			parts[i].Promotions = append(parts[i].Promotions, share)
+		}
+	}
+}
+
+// finishSplitPart totals the part's lines and books the cents that rounding
+// the shares moved as a rounding adjustment, so the part charges exactly total
+func finishSplitPart(part *models.Invoice, serviceCharge float64, total float64) {
+	taxTotal := sumInvoiceLines(part)
+	part.ServiceCharge = serviceCharge
+	part.RoundingAdjustment = helpers.ToFixed(total-(part.SubTotal+taxTotal+serviceCharge), 2)
+	part.GrandTotal = total
+}
@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// kitchenFeed carries every new or changed ticket to the open kitchen streams
+var kitchenFeed = helpers.NewBroker[models.KitchenTicket]()
+
+// Statuses a ticket can be bumped from
+var ticketBumps = map[string][]string{
+	models.TicketStatusInProgress: {models.TicketStatusQueued},
+	models.TicketStatusReady:      {models.TicketStatusQueued, models.TicketStatusInProgress},
+}
+
+func GetKitchenTickets(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	station := queryParams.Get("station")
+
+	// The board shows what still has to be cooked unless asked otherwise
+	statuses := []string{models.TicketStatusQueued, models.TicketStatusInProgress}
+	if s := queryParams.Get("status"); s != "" {
+		statuses = strings.Split(s, ",")
+		for _, status := range statuses {
+			if status != models.TicketStatusQueued && status != models.TicketStatusInProgress &&
+				status != models.TicketStatusReady && status != models.TicketStatusCancelled {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode("Invalid status")
+				return
+			}
+		}
+	}
+
+	tickets, err := repos.KitchenTickets.List(r.Context(), station, statuses)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	err = attachOrderNotes(r.Context(), tickets)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tickets)
+}
+
+func StartKitchenTicket(w http.ResponseWriter, r *http.Request) {
+	bumpKitchenTicket(w, r, models.TicketStatusInProgress)
+}
+
+func MarkKitchenTicketReady(w http.ResponseWriter, r *http.Request) {
+	bumpKitchenTicket(w, r, models.TicketStatusReady)
+}
+
+// StreamKitchenTickets sends every new or changed ticket as a Server-Sent
+// Event until the client goes away. A station query param limits the stream
+// to that station.
+func StreamKitchenTickets(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Streaming is not supported")
+		return
+	}
+
+	station := r.URL.Query().Get("station")
+	tickets := kitchenFeed.Subscribe()
+	defer kitchenFeed.Unsubscribe(tickets)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Comments keep proxies from closing a quiet stream
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case ticket := <-tickets:
+			if station != "" && ticket.Station != station {
+				continue
+			}
+			data, err := json.Marshal(ticket)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: ticket\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func bumpKitchenTicket(w http.ResponseWriter, r *http.Request, status string) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	ticketID, _ := primitive.ObjectIDFromHex(id)
+	ticket, err := repos.KitchenTickets.Get(r.Context(), ticketID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Ticket not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	allowed := false
+	for _, from := range ticketBumps[status] {
+		if ticket.Status == from {
+			allowed = true
+		}
+	}
+	if !allowed {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Cannot move ticket from " + ticket.Status + " to " + status)
+		return
+	}
+
+	update := models.KitchenTicket{Status: status, UpdatedOn: time.Now()}
+	if status == models.TicketStatusInProgress {
+		update.StartedOn = update.UpdatedOn
+	} else {
+		update.ReadyOn = update.UpdatedOn
+	}
+	ticket, err = repos.KitchenTickets.UpdateStatus(r.Context(), ticketID, []string{ticket.Status}, update)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Ticket was changed by someone else, please retry")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	tickets := []models.KitchenTicket{ticket}
+	err = attachOrderNotes(r.Context(), tickets)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	ticket = tickets[0]
+	kitchenFeed.Publish(ticket)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ticket)
+}
+
+// createKitchenTickets routes each order item to the station that cooks its food
+func createKitchenTickets(ctx context.Context, order models.Order, orderItems []models.OrderItem) error {
+	foodIDs := []primitive.ObjectID{}
+	for _, orderItem := range orderItems {
+		foodIDs = append(foodIDs, orderItem.FoodID)
+	}
+	foods, err := repos.Foods.GetMany(ctx, foodIDs)
+	if err != nil {
+		return err
+	}
+	foodsByID := map[primitive.ObjectID]models.Food{}
+	for _, food := range foods {
+		foodsByID[food.ID] = food
+	}
+
+	table, err := repos.Tables.Get(ctx, order.TableID)
+	if err != nil && err != repository.ErrNotFound {
+		return err
+	}
+
+	tickets := []models.KitchenTicket{}
+	for _, orderItem := range orderItems {
+		food := foodsByID[orderItem.FoodID]
+		ticket := models.KitchenTicket{
+			OrderID:     order.ID,
+			OrderItemID: orderItem.ID,
+			TableNumber: table.Number,
+			FoodID:      orderItem.FoodID,
+			FoodName:    food.Name,
+			Quantity:    orderItem.Quantity,
+			Modifiers:   helpers.ModifierNames(orderItem.Modifiers),
+			Station:     food.Station,
+			Status:      models.TicketStatusQueued,
+			Note:        orderItem.Note,
+			CreatedOn:   time.Now(),
+			UpdatedOn:   time.Now(),
+		}
+		if ticket.Station == "" {
+			ticket.Station = models.KitchenStationDefault
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	err = repos.KitchenTickets.CreateMany(ctx, tickets)
+	if err != nil {
+		return err
+	}
+	err = attachOrderNotes(ctx, tickets)
+	if err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		kitchenFeed.Publish(ticket)
+	}
+	return nil
+}
+
+// updateKitchenTickets brings the tickets of a changed order item that are
+// not cooked yet in line with it. Once its ticket is cooked, what the change
+// adds is sent to the kitchen on a new ticket.
+func updateKitchenTickets(ctx context.Context, order models.Order, current models.OrderItem, orderItem models.OrderItem) error {
+	food, err := repos.Foods.Get(ctx, orderItem.FoodID)
+	if err != nil {
+		return err
+	}
+	update := models.KitchenTicket{
+		FoodID:    orderItem.FoodID,
+		FoodName:  food.Name,
+		Station:   food.Station,
+		Quantity:  orderItem.Quantity,
+		Modifiers: helpers.ModifierNames(orderItem.Modifiers),
+		Note:      orderItem.Note,
+	}
+	if update.Station == "" {
+		update.Station = models.KitchenStationDefault
+	}
+	tickets, err := repos.KitchenTickets.UpdateByOrderItem(ctx, orderItem.ID, update)
+	if err != nil {
+		return err
+	}
+
+	if len(tickets) == 0 {
+		added := orderItem
+		if orderItem.FoodID == current.FoodID {
+			added.Quantity = orderItem.Quantity - current.Quantity
+		}
+		if added.Quantity <= 0 {
+			return nil
+		}
+		return createKitchenTickets(ctx, order, []models.OrderItem{added})
+	}
+
+	err = attachOrderNotes(ctx, tickets)
+	if err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		kitchenFeed.Publish(ticket)
+	}
+	return nil
+}
+
+// cancelKitchenTickets takes the order's unfinished tickets off the boards
+func cancelKitchenTickets(ctx context.Context, orderID primitive.ObjectID) error {
+	tickets, err := repos.KitchenTickets.CancelByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		kitchenFeed.Publish(ticket)
+	}
+	return nil
+}
+
+// attachOrderNotes fills in the notes of each ticket's order
+func attachOrderNotes(ctx context.Context, tickets []models.KitchenTicket) error {
+	orderIDs := []primitive.ObjectID{}
+	for _, ticket := range tickets {
+		orderIDs = append(orderIDs, ticket.OrderID)
+	}
+	notes, err := notesByOrder(ctx, orderIDs)
+	if err != nil {
+		return err
+	}
+	for i := range tickets {
+		tickets[i].OrderNotes = []string{}
+		for _, note := range notes[tickets[i].OrderID] {
+			tickets[i].OrderNotes = append(tickets[i].OrderNotes, note.Text)
+		}
+	}
+	return nil
+}
+
+// republishKitchenTickets pushes the order's open tickets to the kitchen
+// streams again after its notes changed. Screens catch up on their next
+// load if this fails, so errors are ignored.
+func republishKitchenTickets(ctx context.Context, orderID primitive.ObjectID) {
+	tickets, err := repos.KitchenTickets.ListByOrder(ctx, orderID)
+	if err != nil || attachOrderNotes(ctx, tickets) != nil {
+		return
+	}
+	for _, ticket := range tickets {
+		if ticket.Status == models.TicketStatusQueued || ticket.Status == models.TicketStatusInProgress {
+			kitchenFeed.Publish(ticket)
+		}
+	}
+}
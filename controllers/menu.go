@@ -1,18 +1,18 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func GetMenus(w http.ResponseWriter, r *http.Request) {
@@ -27,8 +27,7 @@ func GetMenus(w http.ResponseWriter, r *http.Request) {
 	if err != nil || skip < 0 {
 		skip = 0
 	}
-	query := bson.M{}
-	cursor, err := menuCollection.Find(r.Context(), query, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	menus, err := repos.Menus.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -36,17 +35,71 @@ func GetMenus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var menus []models.Menu
-	if err = cursor.All(r.Context(), &menus); err != nil {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(menus)
+}
+
+// ActiveMenu is a menu being served along with the foods that can be ordered
+// from it
+type ActiveMenu struct {
+	models.Menu
+	Foods []models.Food `json:"foods"`
+}
+
+func GetActiveMenus(w http.ResponseWriter, r *http.Request) {
+	at := time.Now()
+	if a := r.URL.Query().Get("at"); a != "" {
+		var err error
+		at, err = time.Parse(time.RFC3339, a)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Invalid time, expected a format like 2006-01-02T15:04:05+05:30")
+			return
+		}
+	}
+
+	menus, err := repos.Menus.List(r.Context(), 0, 0)
+	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
 
+	activeMenus := []ActiveMenu{}
+	menuIDs := []primitive.ObjectID{}
+	for _, menu := range menus {
+		if helpers.IsMenuActive(menu, at) {
+			activeMenus = append(activeMenus, ActiveMenu{Menu: menu, Foods: []models.Food{}})
+			menuIDs = append(menuIDs, menu.ID)
+		}
+	}
+
+	if len(menuIDs) > 0 {
+		foods, err := repos.Foods.ListByMenus(r.Context(), menuIDs)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		for _, food := range foods {
+			if !helpers.IsFoodAvailable(food) {
+				continue
+			}
+			for i := range activeMenus {
+				if activeMenus[i].ID == food.MenuID {
+					activeMenus[i].Foods = append(activeMenus[i].Foods, food)
+				}
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(menus)
+	json.NewEncoder(w).Encode(activeMenus)
 }
 
 func GetMenu(w http.ResponseWriter, r *http.Request) {
@@ -61,15 +114,11 @@ func GetMenu(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get the menu from the database
-	var menu models.Menu
 	menuID, _ := primitive.ObjectIDFromHex(id)
-	query := bson.M{
-		"_id": menuID,
-	}
-	err := menuCollection.FindOne(r.Context(), query).Decode(&menu)
+	menu, err := repos.Menus.Get(r.Context(), menuID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Menu not found")
 			return
@@ -105,11 +154,18 @@ func CreateMenu(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if message := checkMenu(menu); message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
 	menu.CreatedOn = time.Now()
 	menu.UpdatedOn = time.Now()
 
 	// Insert the menu into the database
-	result, err := menuCollection.InsertOne(r.Context(), menu)
+	err = repos.Menus.Create(r.Context(), &menu)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -120,7 +176,7 @@ func CreateMenu(w http.ResponseWriter, r *http.Request) {
 	// Return the menu ID
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode("Inserted menu with ID: " + result.InsertedID.(primitive.ObjectID).Hex())
+	json.NewEncoder(w).Encode("Inserted menu with ID: " + menu.ID.Hex())
 }
 
 func UpdateMenu(w http.ResponseWriter, r *http.Request) {
@@ -152,22 +208,17 @@ func UpdateMenu(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if message := checkMenu(menu); message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
 	menuID, _ := primitive.ObjectIDFromHex(id)
-	query := bson.M{
-		"_id": menuID,
-	}
-	update := bson.M{
-		"$set": bson.M{
-			"name":      menu.Name,
-			"category":  menu.Category,
-			"startDate": menu.StartDate,
-			"endDate":   menu.EndDate,
-			"updatedOn": time.Now(),
-		},
-	}
-	result, err := menuCollection.UpdateOne(r.Context(), query, update)
+	err = repos.Menus.Update(r.Context(), menuID, menu)
 	if err != nil {
-		if result.MatchedCount == 0 {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Menu with given ID not found")
@@ -184,3 +235,62 @@ func UpdateMenu(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode("Updated menu with ID: " + id)
 }
+
+// checkMenu checks the dates and serving hours of the menu, returning what
+// is wrong with them
+func checkMenu(menu models.Menu) string {
+	if !menu.StartDate.IsZero() && !menu.EndDate.IsZero() && menu.EndDate.Before(menu.StartDate) {
+		return "End date is before the start date"
+	}
+	for _, window := range menu.ServingHours {
+		if !helpers.ValidDailyWindow(window) {
+			return "Serving hours need distinct from and to times like 07:00"
+		}
+	}
+	return ""
+}
+
+// checkOrderable makes sure every food of the order items exists and is on
+// a menu being served at the given time. Foods without a menu can always be
+// ordered. It returns the foods by ID, or what cannot be ordered and why.
+func checkOrderable(ctx context.Context, orderItems []models.OrderItem, at time.Time) (map[primitive.ObjectID]models.Food, string, error) {
+	foodIDs := []primitive.ObjectID{}
+	for _, orderItem := range orderItems {
+		foodIDs = append(foodIDs, orderItem.FoodID)
+	}
+	foods, err := repos.Foods.GetMany(ctx, foodIDs)
+	if err != nil {
+		return nil, "", err
+	}
+	foodsByID := map[primitive.ObjectID]models.Food{}
+	for _, food := range foods {
+		foodsByID[food.ID] = food
+	}
+
+	menus := map[primitive.ObjectID]models.Menu{}
+	for _, orderItem := range orderItems {
+		food, ok := foodsByID[orderItem.FoodID]
+		if !ok {
+			return nil, "Food " + orderItem.FoodID.Hex() + " not found", nil
+		}
+		if food.MenuID.IsZero() {
+			continue
+		}
+
+		menu, ok := menus[food.MenuID]
+		if !ok {
+			menu, err = repos.Menus.Get(ctx, food.MenuID)
+			if err != nil && err != repository.ErrNotFound {
+				return nil, "", err
+			}
+			if err == repository.ErrNotFound {
+				return nil, food.Name + " is not on any menu", nil
+			}
+			menus[food.MenuID] = menu
+		}
+		if !helpers.IsMenuActive(menu, at) {
+			return nil, food.Name + " cannot be ordered now, the " + menu.Name + " menu is not being served", nil
+		}
+	}
+	return foodsByID, "", nil
+}
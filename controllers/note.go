@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetNotes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	orderID, _ := primitive.ObjectIDFromHex(id)
+	orderExists, err := repos.Orders.Exists(r.Context(), orderID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if !orderExists {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode("Order not found")
+		return
+	}
+
+	notes, err := repos.Notes.ListByOrders(r.Context(), []primitive.ObjectID{orderID})
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if notes == nil {
+		notes = []models.Note{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(notes)
+}
+
+func GetNote(w http.ResponseWriter, r *http.Request) {
+	note, ok := orderNote(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(note)
+}
+
+func CreateNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	var note models.Note
+	err := json.NewDecoder(r.Body).Decode(&note)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(note)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	orderID, _ := primitive.ObjectIDFromHex(id)
+	orderExists, err := repos.Orders.Exists(r.Context(), orderID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if !orderExists {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode("Order not found")
+		return
+	}
+
+	// userId and username are set by the authentication middleware
+	note.ID = primitive.NilObjectID
+	note.OrderID = orderID
+	note.AuthorID, _ = primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	note.AuthorName = r.Header.Get("username")
+	note.CreatedOn = time.Now()
+	note.UpdatedOn = time.Now()
+	err = repos.Notes.Create(r.Context(), &note)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	republishKitchenTickets(r.Context(), orderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(note)
+}
+
+func UpdateNote(w http.ResponseWriter, r *http.Request) {
+	note, ok := orderNote(w, r)
+	if !ok {
+		return
+	}
+	if !canChangeNote(r, note) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode("Only the author or a manager can change this note")
+		return
+	}
+
+	var updatedNote models.Note
+	err := json.NewDecoder(r.Body).Decode(&updatedNote)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(updatedNote)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	updatedNote.UpdatedOn = time.Now()
+	err = repos.Notes.Update(r.Context(), note.ID, updatedNote)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	republishKitchenTickets(r.Context(), note.OrderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Note updated successfully")
+}
+
+func DeleteNote(w http.ResponseWriter, r *http.Request) {
+	note, ok := orderNote(w, r)
+	if !ok {
+		return
+	}
+	if !canChangeNote(r, note) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode("Only the author or a manager can change this note")
+		return
+	}
+
+	err := repos.Notes.Delete(r.Context(), note.ID)
+	if err != nil && err != repository.ErrNotFound {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	republishKitchenTickets(r.Context(), note.OrderID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Note deleted successfully")
+}
+
+// orderNote loads the note named in the path, writing the error response if
+// it does not exist or belongs to another order
+func orderNote(w http.ResponseWriter, r *http.Request) (models.Note, bool) {
+	vars := mux.Vars(r)
+	orderID, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return models.Note{}, false
+	}
+	noteID, err := primitive.ObjectIDFromHex(vars["noteId"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Note ID")
+		return models.Note{}, false
+	}
+
+	note, err := repos.Notes.Get(r.Context(), noteID)
+	if err == repository.ErrNotFound || (err == nil && note.OrderID != orderID) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode("Note not found")
+		return models.Note{}, false
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return models.Note{}, false
+	}
+	return note, true
+}
+
+func canChangeNote(r *http.Request, note models.Note) bool {
+	return note.AuthorID.Hex() == r.Header.Get("userId") || helpers.HasPermission(r.Header.Get("role"), helpers.PermissionVoidOrders)
+}
+
+// notesByOrder groups the notes of the given orders by order
+func notesByOrder(ctx context.Context, orderIDs []primitive.ObjectID) (map[primitive.ObjectID][]models.Note, error) {
+	notes, err := repos.Notes.ListByOrders(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	grouped := map[primitive.ObjectID][]models.Note{}
+	for _, note := range notes {
+		grouped[note.OrderID] = append(grouped[note.OrderID], note)
+	}
+	return grouped, nil
+}
@@ -7,19 +7,14 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/MayankSaxena03/Restaurant-Management-System/database"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var orderCollection = database.OpenCollection(database.Client, "order")
-var tableCollection = database.OpenCollection(database.Client, "table")
-
 func GetOrders(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
 	l := queryParams.Get("limit")
@@ -33,20 +28,13 @@ func GetOrders(w http.ResponseWriter, r *http.Request) {
 		skip = 0
 	}
 
-	var orders []models.Order
-	cursor, err := orderCollection.Find(r.Context(), bson.M{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	orders, err := repos.Orders.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if err = cursor.All(r.Context(), &orders); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
-	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -64,13 +52,9 @@ func GetOrder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	orderID, _ := primitive.ObjectIDFromHex(id)
-	var order models.Order
-	query := bson.M{
-		"_id": orderID,
-	}
-	err := orderCollection.FindOne(r.Context(), query).Decode(&order)
+	order, err := repos.Orders.Get(r.Context(), orderID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Order not found")
@@ -106,27 +90,33 @@ func CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := bson.M{
-		"_id": newOrder.TableID,
-	}
-
-	count, err := tableCollection.CountDocuments(r.Context(), query)
+	tableExists, err := repos.Tables.Exists(r.Context(), newOrder.TableID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if count == 0 {
+	if !tableExists {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode("Table not found")
 		return
 	}
 
+	newOrder.Status = models.OrderStatusPlaced
+	newOrder.StatusHistory = []models.OrderStatusChange{orderStatusChange(r, "", models.OrderStatusPlaced)}
 	newOrder.CreatedOn = time.Now()
 	newOrder.UpdatedOn = time.Now()
-	result, err := orderCollection.InsertOne(r.Context(), newOrder)
+	err = repos.Orders.Create(r.Context(), &newOrder)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = setTableStatus(r.Context(), newOrder.TableID, models.TableStatusSeated)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -136,7 +126,7 @@ func CreateOrder(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode("Inserted ID: " + result.InsertedID.(primitive.ObjectID).Hex())
+	json.NewEncoder(w).Encode("Inserted ID: " + newOrder.ID.Hex())
 }
 
 func UpdateOrder(w http.ResponseWriter, r *http.Request) {
@@ -169,68 +159,229 @@ func UpdateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tableId := UpdateOrder.TableID
-	query := bson.M{
-		"_id": tableId,
+	tableExists, err := repos.Tables.Exists(r.Context(), UpdateOrder.TableID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	if !tableExists {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode("Table not found")
+		return
 	}
-	count, err := tableCollection.CountDocuments(r.Context(), query)
+
+	order, err := repos.Orders.Get(r.Context(), orderID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode("Table not found")
+			json.NewEncoder(w).Encode("Order not found")
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
+		return
 	}
 
-	if count == 0 {
+	err = repos.Orders.Update(r.Context(), orderID, UpdateOrder)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode("Table not found")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
 
-	query = bson.M{
-		"_id": orderID,
+	// An open order moving tables frees the one it leaves, unless other
+	// orders are still at it, and seats the one it moves to
+	status := orderStatusOf(order)
+	open := status != models.OrderStatusClosed && status != models.OrderStatusCancelled && status != models.OrderStatusVoided
+	if open && UpdateOrder.TableID != order.TableID {
+		err = freeTableAfter(r.Context(), order.TableID, order.ID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		order.TableID = UpdateOrder.TableID
+		err = seatTableOf(r.Context(), order)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Order updated successfully")
+}
+
+func OrderItemOrderCreator(ctx context.Context, order models.Order) (primitive.ObjectID, error) {
+	if order.Status == "" {
+		order.Status = models.OrderStatusPlaced
+	}
+	order.CreatedOn = time.Now()
+	order.UpdatedOn = time.Now()
+
+	err := repos.Orders.Create(ctx, &order)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	// Opening an order seats the table
+	err = setTableStatus(ctx, order.TableID, models.TableStatusSeated)
+	if err != nil {
+		return primitive.NilObjectID, err
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"table_id":  UpdateOrder.TableID,
-			"updatedOn": time.Now(),
-		},
+	return order.ID, nil
+}
+
+func SendOrderToKitchen(w http.ResponseWriter, r *http.Request) {
+	transitionOrder(w, r, models.OrderStatusInKitchen)
+}
+
+func MarkOrderReady(w http.ResponseWriter, r *http.Request) {
+	transitionOrder(w, r, models.OrderStatusReady)
+}
+
+func ServeOrder(w http.ResponseWriter, r *http.Request) {
+	transitionOrder(w, r, models.OrderStatusServed)
+}
+
+func CloseOrder(w http.ResponseWriter, r *http.Request) {
+	transitionOrder(w, r, models.OrderStatusClosed)
+}
+
+func CancelOrder(w http.ResponseWriter, r *http.Request) {
+	transitionOrder(w, r, models.OrderStatusCancelled)
+}
+
+func VoidOrder(w http.ResponseWriter, r *http.Request) {
+	transitionOrder(w, r, models.OrderStatusVoided)
+}
+
+func transitionOrder(w http.ResponseWriter, r *http.Request, status string) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
 	}
 
-	result, err := orderCollection.UpdateOne(r.Context(), query, update)
+	orderID, _ := primitive.ObjectIDFromHex(id)
+	order, err := repos.Orders.Get(r.Context(), orderID)
 	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if result.MatchedCount == 0 {
+
+	if !helpers.CanTransitionOrder(order.Status, status) {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode("Order not found")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Cannot move order from " + orderStatusOf(order) + " to " + status)
 		return
 	}
 
+	err = repos.Orders.Transition(r.Context(), orderID, orderStatusChange(r, orderStatusOf(order), status))
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Order status was changed by someone else, please retry")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// The kitchen stops cooking for orders that will not be served, what
+	// they took out of stock and off the foods' portions is put back, and
+	// their table is freed
+	if status == models.OrderStatusCancelled || status == models.OrderStatusVoided {
+		err = cancelKitchenTickets(r.Context(), orderID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		err = restoreStock(r.Context(), orderID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		err = restorePortions(r.Context(), orderID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		err = freeTableAfter(r.Context(), order.TableID, order.ID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode("Order updated successfully")
+	json.NewEncoder(w).Encode("Order moved to " + status)
 }
 
-func OrderItemOrderCreator(ctx context.Context, order models.Order) (primitive.ObjectID, error) {
-	order.CreatedOn = time.Now()
-	order.UpdatedOn = time.Now()
+func orderStatusOf(order models.Order) string {
+	if order.Status == "" {
+		return models.OrderStatusPlaced
+	}
+	return order.Status
+}
 
-	result, err := orderCollection.InsertOne(context.Background(), order)
-	if err != nil {
-		return primitive.NilObjectID, err
+// canChangeItems reports whether the order's items can still be changed,
+// which they can until it is served
+func canChangeItems(order models.Order) bool {
+	switch orderStatusOf(order) {
+	case models.OrderStatusPlaced, models.OrderStatusInKitchen, models.OrderStatusReady:
+		return true
 	}
+	return false
+}
 
-	return result.InsertedID.(primitive.ObjectID), nil
+func orderStatusChange(r *http.Request, from string, to string) models.OrderStatusChange {
+	// userId is set by the authentication middleware
+	changedBy, _ := primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	return models.OrderStatusChange{
+		From:      from,
+		To:        to,
+		ChangedBy: changedBy,
+		ChangedOn: time.Now(),
+	}
 }
@@ -3,19 +3,17 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/MayankSaxena03/Restaurant-Management-System/database"
 	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type OrderItemPack struct {
@@ -23,8 +21,6 @@ type OrderItemPack struct {
 	OrderItems []models.OrderItem `json:"orderItems,omitempty" bson:"orderItems,omitempty"`
 }
 
-var orderItemCollection = database.OpenCollection(database.Client, "orderItem")
-
 func GetOrderItems(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
 	l := queryParams.Get("limit")
@@ -38,20 +34,13 @@ func GetOrderItems(w http.ResponseWriter, r *http.Request) {
 		skip = 0
 	}
 
-	var orderItems []models.OrderItem
-	cursor, err := orderItemCollection.Find(r.Context(), bson.M{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	orderItems, err := repos.OrderItems.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if err = cursor.All(r.Context(), &orderItems); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
-	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -68,13 +57,9 @@ func GetOrderItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var orderItem models.OrderItem
-	query := bson.M{
-		"_id": orderItemID,
-	}
-
-	if err = orderItemCollection.FindOne(r.Context(), query).Decode(&orderItem); err != nil {
-		if err == mongo.ErrNoDocuments {
+	orderItem, err := repos.OrderItems.Get(r.Context(), orderItemID)
+	if err != nil {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Order Item not found")
@@ -83,6 +68,7 @@ func GetOrderItem(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -124,27 +110,75 @@ func CreateOrderItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	order.CreatedOn = time.Now()
-	orderItemsToBeInserted := []interface{}{}
-	order.TableID = orderItemPack.TableID
-	orderId, err := OrderItemOrderCreator(r.Context(), order)
+	// The items are checked before the order is opened, so a rejected
+	// request leaves nothing behind. The order ID is filled in below.
+	validate := validator.New()
+	for _, orderItem := range orderItemPack.OrderItems {
+		if err := validate.StructExcept(orderItem, "OrderID"); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Invalid Order Item")
+			return
+		}
+	}
+
+	// Only foods on a menu being served can be ordered
+	foods, message, err := checkOrderable(r.Context(), orderItemPack.OrderItems, time.Now())
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
 
-	validate := validator.New()
-	for _, orderItem := range orderItemPack.OrderItems {
-		orderItem.OrderID = orderId
-		if err := validate.Struct(orderItem); err != nil {
+	// Items are priced from their food, whatever price the client sent
+	for i, orderItem := range orderItemPack.OrderItems {
+		orderItemPack.OrderItems[i].UnitPrice = foods[orderItem.FoodID].Price
+		orderItemPack.OrderItems[i].Modifiers, message = helpers.ResolveModifiers(foods[orderItem.FoodID], orderItem.Modifiers)
+		if message != "" {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode("Invalid Order Item")
+			json.NewEncoder(w).Encode(message)
 			return
 		}
+	}
 
+	taken, message, err := takePortions(r.Context(), orderItemPack.OrderItems)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	order.CreatedOn = time.Now()
+	orderItemsToBeInserted := []models.OrderItem{}
+	order.TableID = orderItemPack.TableID
+	order.Status = models.OrderStatusPlaced
+	order.StatusHistory = []models.OrderStatusChange{orderStatusChange(r, "", models.OrderStatusPlaced)}
+	orderId, err := OrderItemOrderCreator(r.Context(), order)
+	if err != nil {
+		returnPortions(r.Context(), taken)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	for _, orderItem := range orderItemPack.OrderItems {
+		orderItem.OrderID = orderId
 		orderItem.CreatedOn = time.Now()
 		orderItem.UpdatedOn = time.Now()
 		orderItem.UnitPrice = helpers.ToFixed(orderItem.UnitPrice, 2)
@@ -152,7 +186,36 @@ func CreateOrderItem(w http.ResponseWriter, r *http.Request) {
 		orderItemsToBeInserted = append(orderItemsToBeInserted, orderItem)
 	}
 
-	result, err := orderItemCollection.InsertMany(r.Context(), orderItemsToBeInserted)
+	insertedIDs, err := repos.OrderItems.CreateMany(r.Context(), orderItemsToBeInserted)
+	if err != nil {
+		returnPortions(r.Context(), taken)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	for i := range orderItemsToBeInserted {
+		orderItemsToBeInserted[i].ID = insertedIDs[i]
+	}
+
+	order.ID = orderId
+	err = createKitchenTickets(r.Context(), order, orderItemsToBeInserted)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = depleteStock(r.Context(), orderItemsToBeInserted)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = setTableStatus(r.Context(), order.TableID, models.TableStatusOrdering)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -162,7 +225,7 @@ func CreateOrderItem(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(result.InsertedIDs)
+	json.NewEncoder(w).Encode(insertedIDs)
 }
 
 func UpdateOrderItem(w http.ResponseWriter, r *http.Request) {
@@ -191,35 +254,134 @@ func UpdateOrderItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orderID := orderItem.OrderID
-	query := bson.M{
-		"_id": orderID,
+	order, err := repos.Orders.Get(r.Context(), orderItem.OrderID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if !canChangeItems(order) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Items of a " + orderStatusOf(order) + " order cannot be changed")
+		return
+	}
+
+	current, err := repos.OrderItems.Get(r.Context(), orderItemID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order Item not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
 	}
-	count, err := orderCollection.CountDocuments(r.Context(), query)
+
+	// Only foods on a menu being served can be ordered, more of them too
+	foods, message, err := checkOrderable(r.Context(), []models.OrderItem{orderItem}, time.Now())
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
-	if count == 0 {
+	if message != "" {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode("Order not found")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+	food := foods[orderItem.FoodID]
+	orderItem.Modifiers, message = helpers.ResolveModifiers(food, orderItem.Modifiers)
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
 		return
 	}
 
+	// The item keeps the prices it was ordered at, those of the modifiers it
+	// keeps included, unless it becomes another food, which is priced as it
+	// is now. Modifiers it picks on top are priced as they are now too.
+	orderItem.UnitPrice = current.UnitPrice
+	if orderItem.FoodID != current.FoodID {
+		orderItem.UnitPrice = food.Price
+	} else {
+		for i, modifier := range orderItem.Modifiers {
+			for _, ordered := range current.Modifiers {
+				if ordered.Group == modifier.Group && ordered.Option == modifier.Option {
+					orderItem.Modifiers[i].Price = ordered.Price
+				}
+			}
+		}
+	}
 	orderItem.UpdatedOn = time.Now()
-	orderItem.UnitPrice = helpers.ToFixed(orderItem.UnitPrice, 2)
 
-	query = bson.M{
-		"_id": orderItemID,
+	// The item takes the portions it orders on top of what it had, and gives
+	// back those it no longer orders. What it uses up by its food's recipe is
+	// corrected by the difference too, as sales of negative quantity for
+	// what it no longer orders, so voiding the order puts back what it uses
+	// in the end.
+	extra := []models.OrderItem{}
+	returned := map[primitive.ObjectID]int{}
+	used := []models.OrderItem{}
+	if orderItem.FoodID != current.FoodID {
+		extra = append(extra, orderItem)
+		returned[current.FoodID] = current.Quantity
+		used = append(used,
+			models.OrderItem{ID: orderItemID, OrderID: current.OrderID, FoodID: current.FoodID, Quantity: -current.Quantity},
+			models.OrderItem{ID: orderItemID, OrderID: current.OrderID, FoodID: orderItem.FoodID, Quantity: orderItem.Quantity},
+		)
+	} else if orderItem.Quantity > current.Quantity {
+		extra = append(extra, models.OrderItem{FoodID: orderItem.FoodID, Quantity: orderItem.Quantity - current.Quantity})
+		used = append(used, models.OrderItem{ID: orderItemID, OrderID: current.OrderID, FoodID: orderItem.FoodID, Quantity: orderItem.Quantity - current.Quantity})
+	} else if orderItem.Quantity < current.Quantity {
+		returned[current.FoodID] = current.Quantity - orderItem.Quantity
+		used = append(used, models.OrderItem{ID: orderItemID, OrderID: current.OrderID, FoodID: orderItem.FoodID, Quantity: orderItem.Quantity - current.Quantity})
+	}
+	taken, message, err := takePortions(r.Context(), extra)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(message)
+		return
 	}
-	update := bson.M{
-		"$set": orderItem,
+
+	err = repos.OrderItems.Update(r.Context(), orderItemID, orderItem)
+	if err != nil {
+		returnPortions(r.Context(), taken)
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order Item not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
 	}
 
-	result, err := orderItemCollection.UpdateOne(r.Context(), query, update)
+	returnPortions(r.Context(), returned)
+
+	err = depleteStock(r.Context(), used)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -227,10 +389,19 @@ func UpdateOrderItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if result.MatchedCount == 0 {
+	// The kitchen cooks the item as it is now stored
+	updated, err := repos.OrderItems.Get(r.Context(), orderItemID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	err = updateKitchenTickets(r.Context(), order, current, updated)
+	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode("Order Item not found")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
 
@@ -239,118 +410,98 @@ func UpdateOrderItem(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode("Order Item updated successfully")
 }
 
-func ItemsByOrder(ctx context.Context, orderID primitive.ObjectID) (OrderItems []primitive.M, err error) {
-	match := bson.M{
-		"$match": bson.M{
-			"orderID": orderID,
-		},
-	}
-	lookupFood := bson.M{
-		"$lookup": bson.M{
-			"from":         "food",
-			"localField":   "foodID",
-			"foreignField": "_id",
-			"as":           "food",
-		},
-	}
-	unwindFood := bson.M{
-		"path":                       "$food",
-		"preserveNullAndEmptyArrays": true,
-	}
-
-	lookupOrder := bson.M{
-		"$lookup": bson.M{
-			"from":         "order",
-			"localField":   "orderID",
-			"foreignField": "_id",
-			"as":           "order",
-		},
-	}
-
-	unwindOrder := bson.M{
-		"path":                       "$order",
-		"preserveNullAndEmptyArrays": true,
-	}
-
-	lookupTable := bson.M{
-		"$lookup": bson.M{
-			"from":         "table",
-			"localField":   "order.tableID",
-			"foreignField": "_id",
-			"as":           "table",
-		},
-	}
-
-	unwindTable := bson.M{
-		"path":                       "$table",
-		"preserveNullAndEmptyArrays": true,
-	}
-
-	project := bson.M{
-		"$project": bson.M{
-			"amount":      "food.price",
-			"foodID":      1,
-			"foodName":    "$food.name",
-			"foodImage":   "$food.image",
-			"tableNumber": "$table.number",
-			"tableID":     "$order.tableID",
-			"price":       "$food.price",
-			"orderID":     "$order._id",
-			"quantity":    1,
-		},
-	}
-
-	group := bson.M{
-		"$group": bson.M{
-			"_id": bson.M{
-				"orderID":     "$orderID",
-				"tableID":     "$tableID",
-				"tableNumber": "$tableNumber",
-			},
-			"paymentDue": bson.M{
-				"$sum": bson.M{
-					"$multiply": []interface{}{"$price", "$quantity"},
-				},
-			},
-			"totalCount": bson.M{
-				"$sum": 1,
-			},
-			"orderItems": bson.M{
-				"$push": "$$ROOT",
-			},
-		},
-	}
-
-	project2 := bson.M{
-		"$project": bson.M{
-			"_id":         1,
-			"paymentDue":  1,
-			"orderItems":  1,
-			"tableNumber": "$_id.tableNumber",
-			"totalCount":  1,
-		},
-	}
-
-	pipeline := []bson.M{
-		match,
-		lookupFood,
-		unwindFood,
-		lookupOrder,
-		unwindOrder,
-		lookupTable,
-		unwindTable,
-		project,
-		group,
-		project2,
-	}
-	cursor, err := orderItemCollection.Aggregate(ctx, pipeline)
+func ItemsByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItemsSummary, error) {
+	summaries, err := repos.OrderItems.SummaryByOrder(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = cursor.All(ctx, &OrderItems); err != nil {
+	notes, err := notesByOrder(ctx, []primitive.ObjectID{orderID})
+	if err != nil {
 		return nil, err
 	}
+	for i := range summaries {
+		summaries[i].Notes = notes[summaries[i].ID.OrderID]
+		if summaries[i].Notes == nil {
+			summaries[i].Notes = []models.Note{}
+		}
+	}
+	return summaries, nil
+}
+
+// takePortions takes the ordered quantities off the remaining portions of
+// the foods, all or nothing. It returns what was taken, or why the items
+// cannot be ordered when a food is sold out or has too few portions left.
+func takePortions(ctx context.Context, orderItems []models.OrderItem) (map[primitive.ObjectID]int, string, error) {
+	quantities := map[primitive.ObjectID]int{}
+	foodIDs := []primitive.ObjectID{}
+	for _, orderItem := range orderItems {
+		if _, ok := quantities[orderItem.FoodID]; !ok {
+			foodIDs = append(foodIDs, orderItem.FoodID)
+		}
+		quantities[orderItem.FoodID] += orderItem.Quantity
+	}
+	foods, err := repos.Foods.GetMany(ctx, foodIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	taken := map[primitive.ObjectID]int{}
+	for _, food := range foods {
+		if food.SoldOut {
+			returnPortions(ctx, taken)
+			return nil, food.Name + " is sold out", nil
+		}
+		if food.RemainingPortions == nil {
+			continue
+		}
+
+		err = repos.Foods.TakePortions(ctx, food.ID, quantities[food.ID])
+		if err == repository.ErrConflict {
+			// Someone may have ordered it, or changed its availability, since
+			// it was read
+			food, err = repos.Foods.Get(ctx, food.ID)
+			if err != nil {
+				returnPortions(ctx, taken)
+				return nil, "", err
+			}
+			if !food.SoldOut && food.RemainingPortions == nil {
+				// Its portions are no longer counted, so there are none to take
+				continue
+			}
+			returnPortions(ctx, taken)
+			if !helpers.IsFoodAvailable(food) {
+				return nil, food.Name + " is sold out", nil
+			}
+			return nil, fmt.Sprintf("Only %d %s left", *food.RemainingPortions, food.Name), nil
+		}
+		if err != nil {
+			returnPortions(ctx, taken)
+			return nil, "", err
+		}
+		taken[food.ID] = quantities[food.ID]
+	}
+	return taken, "", nil
+}
+
+// returnPortions gives back portions taken for items that were not ordered
+func returnPortions(ctx context.Context, taken map[primitive.ObjectID]int) {
+	for foodID, quantity := range taken {
+		repos.Foods.ReturnPortions(ctx, foodID, quantity)
+	}
+}
 
-	return OrderItems, nil
+// restorePortions gives back the portions the order's items took
+func restorePortions(ctx context.Context, orderID primitive.ObjectID) error {
+	orderItems, err := repos.OrderItems.ListByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, orderItem := range orderItems {
+		err = repos.Foods.ReturnPortions(ctx, orderItem.FoodID, orderItem.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetPayments(w http.ResponseWriter, r *http.Request) {
+	invoice, ok := paymentInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	payments, err := repos.Payments.ListByInvoice(r.Context(), invoice.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(payments)
+}
+
+func CreatePayment(w http.ResponseWriter, r *http.Request) {
+	invoice, ok := paymentInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	var payment models.Payment
+	err := json.NewDecoder(r.Body).Decode(&payment)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(payment)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body : " + err.Error())
+		return
+	}
+
+	if payment.Method == models.PaymentMethodVoucher && payment.Reference == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Voucher payments need the voucher code as reference")
+		return
+	}
+
+	balance := helpers.ToFixed(invoice.GrandTotal-invoice.Credited-invoice.AmountPaid, 2)
+	if helpers.IsInvoiceSettled(invoice.PaymentStatus) || balance <= 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Invoice is already paid")
+		return
+	}
+
+	// Only cash is handed back, anything else paid above the balance
+	// leaves the invoice overpaid
+	payment.Amount = helpers.ToFixed(payment.Amount, 2)
+	payment.ChangeGiven = 0
+	if payment.Method == models.PaymentMethodCash && payment.Amount > balance {
+		payment.ChangeGiven = helpers.ToFixed(payment.Amount-balance, 2)
+	}
+
+	payment.ID = primitive.NilObjectID
+	payment.InvoiceID = invoice.ID
+	// userId is set by the authentication middleware
+	payment.ReceivedBy, _ = primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	payment.CreatedOn = time.Now()
+	err = repos.Payments.Create(r.Context(), &payment)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = settleInvoice(r.Context(), invoice)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(payment)
+}
+
+func paymentInvoice(w http.ResponseWriter, r *http.Request) (models.Invoice, bool) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return models.Invoice{}, false
+	}
+
+	invoiceID, _ := primitive.ObjectIDFromHex(id)
+	invoice, err := repos.Invoices.Get(r.Context(), invoiceID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Invoice not found")
+			return models.Invoice{}, false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return models.Invoice{}, false
+	}
+	return invoice, true
+}
+
+// settleInvoice works the payment method, status and balance of the invoice
+// out from its payments and approved credit notes, and frees the table when
+// this settles the bill
+func settleInvoice(ctx context.Context, invoice models.Invoice) error {
+	wasSettled := helpers.IsInvoiceSettled(invoice.PaymentStatus)
+
+	payments, err := repos.Payments.ListByInvoice(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	creditNotes, err := repos.CreditNotes.ListByInvoice(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+
+	methods := []string{}
+	seen := map[string]bool{}
+	paid := 0.0
+	for _, payment := range payments {
+		paid = helpers.ToFixed(paid+payment.Amount-payment.ChangeGiven, 2)
+		if !seen[payment.Method] {
+			seen[payment.Method] = true
+			methods = append(methods, payment.Method)
+		}
+	}
+
+	// Credit notes lower what the invoice charges, the ones refunded also
+	// hand back what was paid
+	credited := 0.0
+	refunded := 0.0
+	for _, creditNote := range creditNotes {
+		if creditNote.Status != models.CreditNoteStatusApproved {
+			continue
+		}
+		credited = helpers.ToFixed(credited+creditNote.Total, 2)
+		if creditNote.RefundMethod != "" {
+			refunded = helpers.ToFixed(refunded+creditNote.Total, 2)
+		}
+	}
+
+	charged := helpers.ToFixed(invoice.GrandTotal-credited, 2)
+	invoice.PaymentMethod = strings.Join(methods, ", ")
+	invoice.AmountPaid = helpers.ToFixed(paid-refunded, 2)
+	invoice.Credited = credited
+	invoice.Refunded = refunded
+	invoice.Balance = helpers.ToFixed(charged-invoice.AmountPaid, 2)
+	invoice.PaymentStatus = helpers.InvoicePaymentStatus(charged, invoice.AmountPaid)
+	err = repos.Invoices.UpdateSettlement(ctx, invoice.ID, invoice)
+	if err != nil {
+		return err
+	}
+
+	if !wasSettled && helpers.IsInvoiceSettled(invoice.PaymentStatus) {
+		return freeInvoiceTable(ctx, invoice.ID)
+	}
+	return nil
+}
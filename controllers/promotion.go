@@ -0,0 +1,445 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderCoupon is a coupon code entered on an order
+type OrderCoupon struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func GetPromotions(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	promotions, err := repos.Promotions.List(r.Context(), int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(promotions)
+}
+
+func GetPromotion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	promotionID, _ := primitive.ObjectIDFromHex(id)
+	promotion, err := repos.Promotions.Get(r.Context(), promotionID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Promotion not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+func CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var promotion models.Promotion
+	err := json.NewDecoder(r.Body).Decode(&promotion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(promotion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	promotion.ID = primitive.NilObjectID
+	promotion.CouponCode = strings.ToUpper(promotion.CouponCode)
+	status, message := checkPromotion(r.Context(), primitive.NilObjectID, promotion)
+	if status != 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	promotion.Value = helpers.ToFixed(promotion.Value, 2)
+	promotion.MinOrderAmount = helpers.ToFixed(promotion.MinOrderAmount, 2)
+	promotion.UsedCount = 0
+	promotion.CreatedOn = time.Now()
+	promotion.UpdatedOn = time.Now()
+	err = repos.Promotions.Create(r.Context(), &promotion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode("Inserted at ID : " + promotion.ID.Hex())
+}
+
+func UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	var promotion models.Promotion
+	err := json.NewDecoder(r.Body).Decode(&promotion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(promotion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	promotionID, _ := primitive.ObjectIDFromHex(id)
+	promotion.CouponCode = strings.ToUpper(promotion.CouponCode)
+	status, message := checkPromotion(r.Context(), promotionID, promotion)
+	if status != 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	// How often a coupon was used is only counted by redeeming it
+	promotion.ID = primitive.NilObjectID
+	promotion.UsedCount = 0
+	promotion.CreatedOn = time.Time{}
+	promotion.Value = helpers.ToFixed(promotion.Value, 2)
+	promotion.MinOrderAmount = helpers.ToFixed(promotion.MinOrderAmount, 2)
+	promotion.UpdatedOn = time.Now()
+	err = repos.Promotions.Update(r.Context(), promotionID, promotion)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Promotion not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Updated at ID : " + id)
+}
+
+func ApplyCoupon(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	var coupon OrderCoupon
+	err := json.NewDecoder(r.Body).Decode(&coupon)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(coupon)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body : " + err.Error())
+		return
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(coupon.Code))
+	promotion, err := repos.Promotions.GetByCoupon(r.Context(), code)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Coupon not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	now := time.Now()
+	if !promotion.StartDate.IsZero() && promotion.StartDate.After(now) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Coupon " + code + " is not valid yet")
+		return
+	}
+	if !promotion.EndDate.IsZero() && promotion.EndDate.Before(now) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Coupon " + code + " has expired")
+		return
+	}
+	if promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Coupon " + code + " has been used up")
+		return
+	}
+
+	orderID, _ := primitive.ObjectIDFromHex(id)
+	err = repos.Orders.AddCoupon(r.Context(), orderID, code)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Coupon " + code + " applied")
+}
+
+func RemoveCoupon(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	code := strings.ToUpper(vars["code"])
+	orderID, _ := primitive.ObjectIDFromHex(id)
+	err := repos.Orders.RemoveCoupon(r.Context(), orderID, code)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Order not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Coupon " + code + " removed")
+}
+
+// checkPromotion checks what the validator cannot: the settings the
+// promotion's type needs, its dates and windows, its foods and that no other
+// promotion has its coupon code. It returns the status and message to reply
+// with, or 0 when the promotion is fine.
+func checkPromotion(ctx context.Context, id primitive.ObjectID, promotion models.Promotion) (int, string) {
+	switch promotion.Type {
+	case models.PromotionTypePercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return http.StatusBadRequest, "A percentage promotion needs a value above 0 and up to 100"
+		}
+	case models.PromotionTypeFlat:
+		if promotion.Value <= 0 {
+			return http.StatusBadRequest, "A flat promotion needs a value above 0"
+		}
+	case models.PromotionTypeBuyXGetY:
+		if promotion.BuyQuantity < 1 || promotion.FreeQuantity < 1 {
+			return http.StatusBadRequest, "A buy-x-get-y promotion needs a buy and a free quantity of at least 1"
+		}
+	case models.PromotionTypeCombo:
+		if len(promotion.FoodIDs) < 2 || promotion.Value <= 0 {
+			return http.StatusBadRequest, "A combo needs at least 2 foods and a price above 0"
+		}
+	}
+
+	if !promotion.StartDate.IsZero() && !promotion.EndDate.IsZero() && promotion.EndDate.Before(promotion.StartDate) {
+		return http.StatusBadRequest, "End date is before the start date"
+	}
+	for _, window := range promotion.HappyHours {
+		if !helpers.ValidDailyWindow(window) {
+			return http.StatusBadRequest, "Happy hours need distinct from and to times like 17:00"
+		}
+	}
+	if promotion.UsageLimit > 0 && promotion.CouponCode == "" {
+		return http.StatusBadRequest, "Only coupons can have a usage limit"
+	}
+
+	if len(promotion.FoodIDs) > 0 {
+		distinct := map[primitive.ObjectID]bool{}
+		for _, foodID := range promotion.FoodIDs {
+			distinct[foodID] = true
+		}
+		ids := []primitive.ObjectID{}
+		for foodID := range distinct {
+			ids = append(ids, foodID)
+		}
+		foods, err := repos.Foods.GetMany(ctx, ids)
+		if err != nil {
+			return http.StatusInternalServerError, "Something went wrong"
+		}
+		if len(foods) != len(ids) {
+			return http.StatusBadRequest, "Food not found"
+		}
+	}
+
+	if promotion.CouponCode != "" {
+		existing, err := repos.Promotions.GetByCoupon(ctx, promotion.CouponCode)
+		if err != nil && err != repository.ErrNotFound {
+			return http.StatusInternalServerError, "Something went wrong"
+		}
+		if err == nil && existing.ID != id {
+			return http.StatusConflict, "Coupon code " + promotion.CouponCode + " is already used by another promotion"
+		}
+	}
+	return 0, ""
+}
+
+// applyPromotions takes the promotions running now off the invoice's lines
+// and records which ones applied
+func applyPromotions(ctx context.Context, invoice *models.Invoice, orderItems []models.OrderItem, order models.Order) error {
+	promotions, err := runningPromotions(ctx, orderItems)
+	if err != nil {
+		return err
+	}
+
+	lines := []helpers.PromotionLine{}
+	for i, orderItem := range orderItems {
+		lines = append(lines, helpers.PromotionLine{
+			OrderItemID: orderItem.ID,
+			FoodID:      orderItem.FoodID,
+			FoodName:    invoice.Lines[i].FoodName,
+			UnitPrice:   invoice.Lines[i].UnitPrice,
+			Quantity:    orderItem.Quantity,
+			OrderedOn:   orderItem.CreatedOn,
+		})
+	}
+
+	invoice.Promotions = helpers.ApplyPromotions(lines, promotions, order.CouponCodes)
+	invoice.Discount = 0
+	for i := range invoice.Lines {
+		invoice.Lines[i].Discount = lines[i].Discount
+		invoice.Discount = helpers.ToFixed(invoice.Discount+lines[i].Discount, 2)
+	}
+	return nil
+}
+
+// runningPromotions lists the promotions running at any time from when the
+// first of the order items was ordered until now. Coupons are checked when
+// they are entered, so they apply as long as they run; the others only apply
+// to items ordered while they were running.
+func runningPromotions(ctx context.Context, orderItems []models.OrderItem) ([]models.Promotion, error) {
+	now := time.Now()
+	from := now
+	for _, orderItem := range orderItems {
+		if !orderItem.CreatedOn.IsZero() && orderItem.CreatedOn.Before(from) {
+			from = orderItem.CreatedOn
+		}
+	}
+	return repos.Promotions.ListActive(ctx, from, now)
+}
+
+// redeemCoupons counts one use of every coupon that took money off the
+// invoice, all or nothing, returning ErrConflict if one was used up in the
+// meantime
+func redeemCoupons(ctx context.Context, invoice models.Invoice) error {
+	redeemed := []primitive.ObjectID{}
+	for _, promotion := range invoice.Promotions {
+		if promotion.CouponCode == "" {
+			continue
+		}
+		err := repos.Promotions.Redeem(ctx, promotion.PromotionID)
+		if err != nil {
+			for _, id := range redeemed {
+				repos.Promotions.Unredeem(ctx, id)
+			}
+			return err
+		}
+		redeemed = append(redeemed, promotion.PromotionID)
+	}
+	return nil
+}
+
+// unredeemCoupons gives back the uses redeemCoupons counted for an invoice
+// that could not be issued
+func unredeemCoupons(ctx context.Context, invoice models.Invoice) {
+	for _, promotion := range invoice.Promotions {
+		if promotion.CouponCode == "" {
+			continue
+		}
+		repos.Promotions.Unredeem(ctx, promotion.PromotionID)
+	}
+}
@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	purchaseOrders, err := repos.PurchaseOrders.List(r.Context(), int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(purchaseOrders)
+}
+
+func GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrder, ok := purchaseOrderOf(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(purchaseOrder)
+}
+
+func CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var purchaseOrder models.PurchaseOrder
+	err := json.NewDecoder(r.Body).Decode(&purchaseOrder)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(purchaseOrder)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	supplierExists, err := repos.Suppliers.Exists(r.Context(), purchaseOrder.SupplierID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if !supplierExists {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Supplier not found")
+		return
+	}
+
+	// Every line copies its ingredient's name and unit, like invoices copy
+	// their foods
+	purchaseOrder.Total = 0
+	listed := map[primitive.ObjectID]bool{}
+	for i, line := range purchaseOrder.Lines {
+		if listed[line.IngredientID] {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Ingredient " + line.IngredientID.Hex() + " is listed twice")
+			return
+		}
+		listed[line.IngredientID] = true
+
+		ingredient, err := repos.Ingredients.Get(r.Context(), line.IngredientID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode("Ingredient " + line.IngredientID.Hex() + " not found")
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+
+		purchaseOrder.Lines[i].IngredientName = ingredient.Name
+		purchaseOrder.Lines[i].Unit = ingredient.Unit
+		purchaseOrder.Lines[i].UnitCost = helpers.ToFixed(line.UnitCost, 2)
+		purchaseOrder.Lines[i].Amount = helpers.ToFixed(purchaseOrder.Lines[i].UnitCost*line.Quantity, 2)
+		purchaseOrder.Lines[i].Received = 0
+		purchaseOrder.Total = helpers.ToFixed(purchaseOrder.Total+purchaseOrder.Lines[i].Amount, 2)
+	}
+
+	purchaseOrder.ID = primitive.NilObjectID
+	purchaseOrder.Status = models.PurchaseOrderStatusOrdered
+	purchaseOrder.Receipts = nil
+	// userId is set by the authentication middleware
+	purchaseOrder.CreatedBy, _ = primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	purchaseOrder.CreatedOn = time.Now()
+	purchaseOrder.UpdatedOn = time.Now()
+	err = repos.PurchaseOrders.Create(r.Context(), &purchaseOrder)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode("Inserted at ID : " + purchaseOrder.ID.Hex())
+}
+
+func ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrder, ok := purchaseOrderOf(w, r)
+	if !ok {
+		return
+	}
+
+	var receipt models.GoodsReceipt
+	err := json.NewDecoder(r.Body).Decode(&receipt)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(receipt)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body : " + err.Error())
+		return
+	}
+
+	if !isPurchaseOrderOpen(purchaseOrder) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Purchase order is already " + purchaseOrder.Status)
+		return
+	}
+
+	// userId is set by the authentication middleware
+	userID, _ := primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	movements := []models.StockMovement{}
+	for i, received := range receipt.Lines {
+		line := -1
+		for j := range purchaseOrder.Lines {
+			if purchaseOrder.Lines[j].IngredientID == received.IngredientID {
+				line = j
+				break
+			}
+		}
+		if line < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Ingredient " + received.IngredientID.Hex() + " is not on this purchase order")
+			return
+		}
+
+		ordered := purchaseOrder.Lines[line]
+		left := helpers.ToFixed(ordered.Quantity-ordered.Received, 3)
+		if received.Quantity > left {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Only " + strconv.FormatFloat(left, 'f', -1, 64) + " " + ordered.Unit + " of " + ordered.IngredientName + " left to receive")
+			return
+		}
+
+		if received.UnitCost == 0 {
+			receipt.Lines[i].UnitCost = ordered.UnitCost
+		}
+		receipt.Lines[i].UnitCost = helpers.ToFixed(receipt.Lines[i].UnitCost, 2)
+		purchaseOrder.Lines[line].Received = helpers.ToFixed(ordered.Received+received.Quantity, 3)
+		movements = append(movements, models.StockMovement{
+			IngredientID:    received.IngredientID,
+			Quantity:        received.Quantity,
+			Reason:          models.StockMovementPurchase,
+			PurchaseOrderID: purchaseOrder.ID,
+			UnitCost:        receipt.Lines[i].UnitCost,
+			CreatedBy:       userID,
+		})
+	}
+
+	purchaseOrder.Status = models.PurchaseOrderStatusReceived
+	for _, line := range purchaseOrder.Lines {
+		if line.Received < line.Quantity {
+			purchaseOrder.Status = models.PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+	receipt.ReceivedBy = userID
+	receipt.ReceivedOn = time.Now()
+	purchaseOrder.Receipts = append(purchaseOrder.Receipts, receipt)
+	updatedOn := purchaseOrder.UpdatedOn
+	purchaseOrder.UpdatedOn = time.Now()
+	err = repos.PurchaseOrders.Update(r.Context(), purchaseOrder.ID, updatedOn, purchaseOrder)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Purchase order was changed by someone else, please retry")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = moveStock(r.Context(), movements)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	for _, received := range receipt.Lines {
+		err = repos.Ingredients.SetCost(r.Context(), received.IngredientID, received.UnitCost, purchaseOrder.SupplierID)
+		if err != nil && err != repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(purchaseOrder)
+}
+
+func CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	purchaseOrder, ok := purchaseOrderOf(w, r)
+	if !ok {
+		return
+	}
+
+	if !isPurchaseOrderOpen(purchaseOrder) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Purchase order is already " + purchaseOrder.Status)
+		return
+	}
+
+	// What was received stays in stock, the rest is no longer expected
+	updatedOn := purchaseOrder.UpdatedOn
+	purchaseOrder.Status = models.PurchaseOrderStatusCancelled
+	purchaseOrder.UpdatedOn = time.Now()
+	err := repos.PurchaseOrders.Update(r.Context(), purchaseOrder.ID, updatedOn, purchaseOrder)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Purchase order was changed by someone else, please retry")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Purchase order cancelled successfully")
+}
+
+func purchaseOrderOf(w http.ResponseWriter, r *http.Request) (models.PurchaseOrder, bool) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return models.PurchaseOrder{}, false
+	}
+
+	purchaseOrderID, _ := primitive.ObjectIDFromHex(id)
+	purchaseOrder, err := repos.PurchaseOrders.Get(r.Context(), purchaseOrderID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Purchase order not found")
+			return models.PurchaseOrder{}, false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return models.PurchaseOrder{}, false
+	}
+	return purchaseOrder, true
+}
+
+func isPurchaseOrderOpen(purchaseOrder models.PurchaseOrder) bool {
+	return purchaseOrder.Status == models.PurchaseOrderStatusOrdered || purchaseOrder.Status == models.PurchaseOrderStatusPartiallyReceived
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetInvoiceReceipt prints the invoice as a PDF, as plain text or as ESC/POS
+// bytes for a receipt printer. Text and ESC/POS are laid out for 80mm paper
+// unless paper=58mm is asked for.
+func GetInvoiceReceipt(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	queryParams := r.URL.Query()
+	format := queryParams.Get("format")
+	if format == "" {
+		format = "pdf"
+	}
+	if format != "pdf" && format != "text" && format != "escpos" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid format, use pdf, text or escpos")
+		return
+	}
+
+	width := helpers.ReceiptWidth80mm
+	switch queryParams.Get("paper") {
+	case "", "80mm":
+	case "58mm":
+		width = helpers.ReceiptWidth58mm
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid paper, use 80mm or 58mm")
+		return
+	}
+
+	invoiceID, _ := primitive.ObjectIDFromHex(id)
+	invoice, err := repos.Invoices.Get(r.Context(), invoiceID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Invoice not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// Invoices created before snapshots existed are printed from the order
+	if len(invoice.Lines) == 0 {
+		order, err := repos.Orders.Get(r.Context(), invoice.OrderID)
+		if err == nil {
+			err = buildInvoiceSnapshot(r.Context(), &invoice, order)
+		}
+		if err != nil && err != repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+	}
+
+	payments, err := repos.Payments.ListByInvoice(r.Context(), invoice.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	receipt := helpers.Receipt{Header: helpers.ReceiptHeaderFromEnv(), Invoice: invoice, Payments: payments}
+	name := invoice.Number
+	if name == "" {
+		name = invoice.ID.Hex()
+	}
+	name = strings.ReplaceAll(name, "/", "-")
+
+	switch format {
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="`+name+`.pdf"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(receipt.PDF())
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(receipt.Text(width))
+	case "escpos":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.bin"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(receipt.ESCPOS(width))
+	}
+}
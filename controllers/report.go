@@ -0,0 +1,721 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DayClose asks for the day Date, like 2026-10-18, to be closed
+type DayClose struct {
+	Date string `json:"date" validate:"required"`
+}
+
+// DishMargin is what one portion of a food costs to make and earns at its
+// menu price, and what the portions sold over the report period brought in.
+// Revenue is after Discounts, what promotions took off the food on the
+// invoices issued in the period. Costed is false for foods without a recipe,
+// whose plate cost is unknown.
+type DishMargin struct {
+	FoodID        primitive.ObjectID `json:"foodID"`
+	FoodName      string             `json:"foodName"`
+	Price         float64            `json:"price"`
+	PlateCost     float64            `json:"plateCost"`
+	UnitMargin    float64            `json:"unitMargin"`
+	MarginPercent float64            `json:"marginPercent"`
+	Costed        bool               `json:"costed"`
+	Quantity      int                `json:"quantity"`
+	Discounts     float64            `json:"discounts"`
+	Revenue       float64            `json:"revenue"`
+	Cost          float64            `json:"cost"`
+	Margin        float64            `json:"margin"`
+}
+
+// MarginReport ranks the dishes by the gross margin they made from From
+// until To, most profitable first
+type MarginReport struct {
+	From          time.Time    `json:"from"`
+	To            time.Time    `json:"to"`
+	Discounts     float64      `json:"discounts"`
+	Revenue       float64      `json:"revenue"`
+	Cost          float64      `json:"cost"`
+	Margin        float64      `json:"margin"`
+	MarginPercent float64      `json:"marginPercent"`
+	Dishes        []DishMargin `json:"dishes"`
+}
+
+func GetMarginReport(w http.ResponseWriter, r *http.Request) {
+	from, to, message := reportPeriod(r)
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	foods, err := repos.Foods.List(r.Context(), 0, 0)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	foodIDs := []primitive.ObjectID{}
+	for _, food := range foods {
+		foodIDs = append(foodIDs, food.ID)
+	}
+
+	recipes, err := repos.Recipes.ListByFoods(r.Context(), foodIDs)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	recipeByFood := map[primitive.ObjectID]models.Recipe{}
+	for _, recipe := range recipes {
+		recipeByFood[recipe.FoodID] = recipe
+	}
+
+	ingredients, err := repos.Ingredients.List(r.Context(), 0, 0)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	ingredientByID := map[primitive.ObjectID]models.Ingredient{}
+	for _, ingredient := range ingredients {
+		ingredientByID[ingredient.ID] = ingredient
+	}
+
+	sales, err := repos.OrderItems.SalesByFood(r.Context(), from, to)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	salesByFood := map[primitive.ObjectID]models.FoodSales{}
+	for _, sale := range sales {
+		salesByFood[sale.FoodID] = sale
+	}
+
+	invoices, err := repos.Invoices.ListIssued(r.Context(), from, to)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	discountByFood := map[primitive.ObjectID]float64{}
+	for _, invoice := range invoices {
+		for _, line := range invoice.Lines {
+			discountByFood[line.FoodID] += line.Discount
+		}
+	}
+
+	report := MarginReport{From: from, To: to, Dishes: []DishMargin{}}
+	for _, food := range foods {
+		recipe, costed := recipeByFood[food.ID]
+		plateCost := helpers.PlateCost(recipe, ingredientByID)
+		sale := salesByFood[food.ID]
+		cost := helpers.ToFixed(plateCost*float64(sale.Quantity), 2)
+		discounts := helpers.ToFixed(discountByFood[food.ID], 2)
+		revenue := helpers.ToFixed(sale.Revenue-discounts, 2)
+
+		report.Dishes = append(report.Dishes, DishMargin{
+			FoodID:        food.ID,
+			FoodName:      food.Name,
+			Price:         food.Price,
+			PlateCost:     plateCost,
+			UnitMargin:    helpers.ToFixed(food.Price-plateCost, 2),
+			MarginPercent: helpers.MarginPercent(food.Price, plateCost),
+			Costed:        costed,
+			Quantity:      sale.Quantity,
+			Discounts:     discounts,
+			Revenue:       revenue,
+			Cost:          cost,
+			Margin:        helpers.ToFixed(revenue-cost, 2),
+		})
+		report.Discounts += discounts
+		report.Revenue += revenue
+		report.Cost += cost
+	}
+	report.Discounts = helpers.ToFixed(report.Discounts, 2)
+	report.Revenue = helpers.ToFixed(report.Revenue, 2)
+	report.Cost = helpers.ToFixed(report.Cost, 2)
+	report.Margin = helpers.ToFixed(report.Revenue-report.Cost, 2)
+	report.MarginPercent = helpers.MarginPercent(report.Revenue, report.Cost)
+
+	sort.SliceStable(report.Dishes, func(i, j int) bool {
+		if report.Dishes[i].Margin != report.Dishes[j].Margin {
+			return report.Dishes[i].Margin > report.Dishes[j].Margin
+		}
+		return report.Dishes[i].UnitMargin > report.Dishes[j].UnitMargin
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// SellerRank is what a food sold over the report period and its share of
+// the revenue of all foods, in percent
+type SellerRank struct {
+	FoodID       primitive.ObjectID `json:"foodID"`
+	FoodName     string             `json:"foodName"`
+	Category     string             `json:"category"`
+	Quantity     int                `json:"quantity"`
+	Revenue      float64            `json:"revenue"`
+	RevenueShare float64            `json:"revenueShare"`
+}
+
+// SellerReport lists the best and worst selling foods from From until To,
+// by quantity and by revenue. Foods that did not sell at all are the worst
+// sellers.
+type SellerReport struct {
+	From            time.Time    `json:"from"`
+	To              time.Time    `json:"to"`
+	BestByQuantity  []SellerRank `json:"bestByQuantity"`
+	BestByRevenue   []SellerRank `json:"bestByRevenue"`
+	WorstByQuantity []SellerRank `json:"worstByQuantity"`
+	WorstByRevenue  []SellerRank `json:"worstByRevenue"`
+}
+
+// CategoryShare is what a menu category sold and its share, in percent, of
+// the items and revenue of all categories
+type CategoryShare struct {
+	models.CategorySales
+	QuantityShare float64 `json:"quantityShare"`
+	RevenueShare  float64 `json:"revenueShare"`
+}
+
+// CategoryMix splits the sales from From until To over the menu categories,
+// the largest by revenue first
+type CategoryMix struct {
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Quantity   int             `json:"quantity"`
+	Revenue    float64         `json:"revenue"`
+	Categories []CategoryShare `json:"categories"`
+}
+
+// Heatmap counts the orders and items ordered from From until To per
+// weekday, from 0 for Sunday, and hour of the day in the time zone of From,
+// e.g. Orders[5][20] for Fridays from 20:00. Peak is the hour with the most
+// orders.
+type Heatmap struct {
+	From   time.Time          `json:"from"`
+	To     time.Time          `json:"to"`
+	Orders [7][24]int         `json:"orders"`
+	Items  [7][24]int         `json:"items"`
+	Peak   models.OrderVolume `json:"peak"`
+}
+
+func GetSellerReport(w http.ResponseWriter, r *http.Request) {
+	from, to, message := reportPeriod(r)
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+
+	foods, err := repos.Foods.List(r.Context(), 0, 0)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	menus, err := repos.Menus.List(r.Context(), 0, 0)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	categories := map[primitive.ObjectID]string{}
+	for _, menu := range menus {
+		categories[menu.ID] = menu.Category
+	}
+
+	sales, err := repos.OrderItems.SalesByFood(r.Context(), from, to)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	salesByFood := map[primitive.ObjectID]models.FoodSales{}
+	revenue := 0.0
+	for _, sale := range sales {
+		salesByFood[sale.FoodID] = sale
+		revenue += sale.Revenue
+	}
+
+	ranks := []SellerRank{}
+	for _, food := range foods {
+		sale := salesByFood[food.ID]
+		rank := SellerRank{
+			FoodID:   food.ID,
+			FoodName: food.Name,
+			Category: categories[food.MenuID],
+			Quantity: sale.Quantity,
+			Revenue:  helpers.ToFixed(sale.Revenue, 2),
+		}
+		if revenue > 0 {
+			rank.RevenueShare = helpers.ToFixed(sale.Revenue/revenue*100, 2)
+		}
+		ranks = append(ranks, rank)
+	}
+
+	report := SellerReport{From: from, To: to}
+	report.BestByQuantity = topSellers(ranks, limit, func(a SellerRank, b SellerRank) bool {
+		return a.Quantity > b.Quantity || a.Quantity == b.Quantity && a.Revenue > b.Revenue
+	})
+	report.BestByRevenue = topSellers(ranks, limit, func(a SellerRank, b SellerRank) bool {
+		return a.Revenue > b.Revenue || a.Revenue == b.Revenue && a.Quantity > b.Quantity
+	})
+	report.WorstByQuantity = topSellers(ranks, limit, func(a SellerRank, b SellerRank) bool {
+		return a.Quantity < b.Quantity || a.Quantity == b.Quantity && a.Revenue < b.Revenue
+	})
+	report.WorstByRevenue = topSellers(ranks, limit, func(a SellerRank, b SellerRank) bool {
+		return a.Revenue < b.Revenue || a.Revenue == b.Revenue && a.Quantity < b.Quantity
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+func GetCategoryMix(w http.ResponseWriter, r *http.Request) {
+	from, to, message := reportPeriod(r)
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	sales, err := repos.OrderItems.SalesByCategory(r.Context(), from, to)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	mix := CategoryMix{From: from, To: to, Categories: []CategoryShare{}}
+	for _, sale := range sales {
+		mix.Quantity += sale.Quantity
+		mix.Revenue += sale.Revenue
+	}
+	for _, sale := range sales {
+		share := CategoryShare{CategorySales: sale}
+		share.Revenue = helpers.ToFixed(sale.Revenue, 2)
+		if mix.Quantity > 0 {
+			share.QuantityShare = helpers.ToFixed(float64(sale.Quantity)/float64(mix.Quantity)*100, 2)
+		}
+		if mix.Revenue > 0 {
+			share.RevenueShare = helpers.ToFixed(sale.Revenue/mix.Revenue*100, 2)
+		}
+		mix.Categories = append(mix.Categories, share)
+	}
+	mix.Revenue = helpers.ToFixed(mix.Revenue, 2)
+	sort.SliceStable(mix.Categories, func(i, j int) bool {
+		if mix.Categories[i].Revenue != mix.Categories[j].Revenue {
+			return mix.Categories[i].Revenue > mix.Categories[j].Revenue
+		}
+		return mix.Categories[i].Category < mix.Categories[j].Category
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(mix)
+}
+
+func GetHeatmap(w http.ResponseWriter, r *http.Request) {
+	from, to, message := reportPeriod(r)
+	if message != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(message)
+		return
+	}
+
+	volumes, err := repos.OrderItems.OrderVolume(r.Context(), from, to)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	heatmap := Heatmap{From: from, To: to}
+	for _, volume := range volumes {
+		heatmap.Orders[volume.Weekday][volume.Hour] += volume.Orders
+		heatmap.Items[volume.Weekday][volume.Hour] += volume.Items
+	}
+	for weekday := range heatmap.Orders {
+		for hour, orders := range heatmap.Orders[weekday] {
+			if orders > heatmap.Peak.Orders {
+				heatmap.Peak = models.OrderVolume{Weekday: weekday, Hour: hour, Orders: orders, Items: heatmap.Items[weekday][hour]}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(heatmap)
+}
+
+// topSellers returns the first limit ranks in the order of before, foods
+// ranking alike in the order of their names
+func topSellers(ranks []SellerRank, limit int, before func(a SellerRank, b SellerRank) bool) []SellerRank {
+	sorted := append([]SellerRank{}, ranks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if before(sorted[i], sorted[j]) {
+			return true
+		}
+		if before(sorted[j], sorted[i]) {
+			return false
+		}
+		return sorted[i].FoodName < sorted[j].FoodName
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// reportPeriod reads the from and to query parameters of a report. To
+// defaults to now and from to 30 days before to. It returns what is wrong
+// with them, if anything.
+func reportPeriod(r *http.Request) (time.Time, time.Time, string) {
+	queryParams := r.URL.Query()
+	to := time.Now()
+	if t := queryParams.Get("to"); t != "" {
+		var err error
+		to, err = time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, time.Time{}, "Invalid time, expected a format like 2006-01-02T15:04:05+05:30"
+		}
+	}
+	from := to.AddDate(0, 0, -30)
+	if f := queryParams.Get("from"); f != "" {
+		var err error
+		from, err = time.Parse(time.RFC3339, f)
+		if err != nil {
+			return time.Time{}, time.Time{}, "Invalid time, expected a format like 2006-01-02T15:04:05+05:30"
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, "The report must start before it ends"
+	}
+	return from, to, ""
+}
+
+// GetSalesSummary adds up the sales of the day given by date, today by
+// default. Once the day is closed its Z-report is returned instead, so the
+// numbers stay the same.
+func GetSalesSummary(w http.ResponseWriter, r *http.Request) {
+	day := time.Now()
+	if d := r.URL.Query().Get("date"); d != "" {
+		var ok bool
+		day, ok = reportDay(d)
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Invalid date, expected a format like 2006-01-02")
+			return
+		}
+	}
+
+	zReport, err := repos.ZReports.GetByDate(r.Context(), day.Format("2006-01-02"))
+	if err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(zReport.SalesSummary)
+		return
+	}
+	if err != repository.ErrNotFound {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	summary, err := salesSummary(r.Context(), day)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(summary)
+}
+
+func GetZReports(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	zReports, err := repos.ZReports.List(r.Context(), int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(zReports)
+}
+
+func GetZReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	day, ok := reportDay(vars["date"])
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid date, expected a format like 2006-01-02")
+		return
+	}
+
+	zReport, err := repos.ZReports.GetByDate(r.Context(), day.Format("2006-01-02"))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Z-report not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(zReport)
+}
+
+// CloseDay freezes the sales summary of a day as its Z-report. A day is
+// closed once it has ended, so every sale of the day is in it.
+func CloseDay(w http.ResponseWriter, r *http.Request) {
+	var dayClose DayClose
+	err := json.NewDecoder(r.Body).Decode(&dayClose)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(dayClose)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	day, ok := reportDay(dayClose.Date)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid date, expected a format like 2006-01-02")
+		return
+	}
+	if day.AddDate(0, 0, 1).After(time.Now()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("A day can only be closed once it has ended")
+		return
+	}
+
+	summary, err := salesSummary(r.Context(), day)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	summary.Closed = true
+
+	zReport := models.ZReport{SalesSummary: summary, ClosedOn: time.Now()}
+	zReport.ClosedBy, _ = primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	zReport.Number, err = repos.Counters.Next(r.Context(), "zReport")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	err = repos.ZReports.Create(r.Context(), &zReport)
+	if err != nil {
+		repos.Counters.Release(r.Context(), "zReport", zReport.Number)
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("The day is already closed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(zReport)
+}
+
+// salesSummary adds up the invoices, payments and credit notes of the day
+// that day falls on
+func salesSummary(ctx context.Context, day time.Time) (models.SalesSummary, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 1)
+	summary := models.SalesSummary{
+		Date:     from.Format("2006-01-02"),
+		From:     from,
+		To:       to,
+		Taxes:    []models.InvoiceTax{},
+		Payments: []models.TenderTotal{},
+		Refunds:  []models.TenderTotal{},
+	}
+
+	invoices, err := repos.Invoices.ListIssued(ctx, from, to)
+	if err != nil {
+		return summary, err
+	}
+	orderIDs := []primitive.ObjectID{}
+	billed := map[primitive.ObjectID]bool{}
+	taxes := []models.InvoiceTax{}
+	for _, invoice := range invoices {
+		summary.Invoices++
+		if !billed[invoice.OrderID] {
+			billed[invoice.OrderID] = true
+			orderIDs = append(orderIDs, invoice.OrderID)
+		}
+		for _, line := range invoice.Lines {
+			summary.GrossSales += line.Amount
+		}
+		summary.Discounts += invoice.Discount
+		summary.NetSales += invoice.SubTotal
+		for _, tax := range invoice.Taxes {
+			summary.TaxTotal += tax.Amount
+		}
+		taxes = append(taxes, invoice.Taxes...)
+		summary.ServiceCharge += invoice.ServiceCharge
+		summary.Rounding += invoice.RoundingAdjustment
+		summary.Total += invoice.GrandTotal
+	}
+	summary.Checks = len(orderIDs)
+	summary.Taxes = helpers.SumTaxes(taxes)
+
+	if len(orderIDs) > 0 {
+		orders, err := repos.Orders.GetMany(ctx, orderIDs)
+		if err != nil {
+			return summary, err
+		}
+		for _, order := range orders {
+			summary.Covers += order.Guests
+		}
+	}
+
+	payments, err := repos.Payments.ListReceived(ctx, from, to)
+	if err != nil {
+		return summary, err
+	}
+	for _, payment := range payments {
+		amount := payment.Amount - payment.ChangeGiven
+		summary.Payments = addTender(summary.Payments, payment.Method, amount)
+		summary.Collected += amount
+	}
+
+	creditNotes, err := repos.CreditNotes.ListReviewed(ctx, models.CreditNoteStatusApproved, from, to)
+	if err != nil {
+		return summary, err
+	}
+	for _, creditNote := range creditNotes {
+		summary.CreditNotes++
+		summary.Credited += creditNote.Total
+		if creditNote.RefundMethod != "" {
+			summary.Refunds = addTender(summary.Refunds, creditNote.RefundMethod, creditNote.Total)
+			summary.Refunded += creditNote.Total
+		}
+	}
+
+	summary.GrossSales = helpers.ToFixed(summary.GrossSales, 2)
+	summary.Discounts = helpers.ToFixed(summary.Discounts, 2)
+	summary.NetSales = helpers.ToFixed(summary.NetSales, 2)
+	summary.TaxTotal = helpers.ToFixed(summary.TaxTotal, 2)
+	summary.ServiceCharge = helpers.ToFixed(summary.ServiceCharge, 2)
+	summary.Rounding = helpers.ToFixed(summary.Rounding, 2)
+	summary.Total = helpers.ToFixed(summary.Total, 2)
+	summary.Collected = helpers.ToFixed(summary.Collected, 2)
+	summary.Credited = helpers.ToFixed(summary.Credited, 2)
+	summary.Refunded = helpers.ToFixed(summary.Refunded, 2)
+	summary.NetCollected = helpers.ToFixed(summary.Collected-summary.Refunded, 2)
+	if summary.Checks > 0 {
+		summary.AverageCheck = helpers.ToFixed(summary.Total/float64(summary.Checks), 2)
+	}
+	if summary.Covers > 0 {
+		summary.AveragePerCover = helpers.ToFixed(summary.Total/float64(summary.Covers), 2)
+	}
+	return summary, nil
+}
+
+// addTender adds amount to the total of its method, keeping the methods in
+// alphabetical order
+func addTender(tenders []models.TenderTotal, method string, amount float64) []models.TenderTotal {
+	for i := range tenders {
+		if tenders[i].Method == method {
+			tenders[i].Count++
+			tenders[i].Amount = helpers.ToFixed(tenders[i].Amount+amount, 2)
+			return tenders
+		}
+	}
+	tenders = append(tenders, models.TenderTotal{Method: method, Count: 1, Amount: helpers.ToFixed(amount, 2)})
+	sort.Slice(tenders, func(i, j int) bool { return tenders[i].Method < tenders[j].Method })
+	return tenders
+}
+
+// reportDay reads a day like 2026-10-18 in the server's time zone
+func reportDay(date string) (time.Time, bool) {
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	return day, err == nil
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetReservations(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	l := queryParams.Get("limit")
+	limit, err := strconv.Atoi(l)
+	if err != nil || limit < 0 {
+		limit = 10
+	}
+	s := queryParams.Get("skip")
+	skip, err := strconv.Atoi(s)
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	reservations, err := repos.Reservations.List(r.Context(), int64(skip), int64(limit))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reservations)
+}
+
+func GetReservation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	reservationID, _ := primitive.ObjectIDFromHex(id)
+	reservation, err := repos.Reservations.Get(r.Context(), reservationID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Reservation not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func CreateReservation(w http.ResponseWriter, r *http.Request) {
+	var reservation models.Reservation
+	err := json.NewDecoder(r.Body).Decode(&reservation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(reservation)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	if reservation.At.Before(time.Now()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Reservation time is in the past")
+		return
+	}
+
+	table, err := repos.Tables.Get(r.Context(), reservation.TableID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Table not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	if reservation.PartySize > table.Guests {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Table seats only " + strconv.Itoa(table.Guests) + " guests")
+		return
+	}
+
+	if reservation.Duration == 0 {
+		reservation.Duration = models.ReservationDefaultDuration
+	}
+	reservation.EndsAt = reservation.At.Add(time.Duration(reservation.Duration) * time.Minute)
+	reservation.Status = models.ReservationStatusBooked
+	reservation.CreatedOn = time.Now()
+	reservation.UpdatedOn = time.Now()
+	err = repos.Reservations.Create(r.Context(), &reservation)
+	if err != nil {
+		if err == repository.ErrConflict {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode("Table is already booked for this time")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func CancelReservation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid ID")
+		return
+	}
+
+	reservationID, _ := primitive.ObjectIDFromHex(id)
+	reservation, err := repos.Reservations.Get(r.Context(), reservationID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Reservation not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	if reservation.Status == models.ReservationStatusCancelled {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Reservation is already cancelled")
+		return
+	}
+
+	err = repos.Reservations.Cancel(r.Context(), reservationID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Reservation cancelled successfully")
+}
+
+// GetTableAvailability lists the tables that seat partySize guests and are
+// not booked for any part of the duration starting at the given time.
+// Smallest tables come first so large ones stay free for large parties.
+func GetTableAvailability(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	partySize, err := strconv.Atoi(queryParams.Get("partySize"))
+	if err != nil || partySize <= 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid party size")
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, queryParams.Get("at"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid time, expected a format like 2006-01-02T15:04:05+05:30")
+		return
+	}
+
+	duration := models.ReservationDefaultDuration
+	if d := queryParams.Get("duration"); d != "" {
+		duration, err = strconv.Atoi(d)
+		if err != nil || duration <= 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode("Invalid duration")
+			return
+		}
+	}
+
+	tables, err := repos.Tables.List(r.Context(), 0, 0)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	reservations, err := repos.Reservations.Overlapping(r.Context(), at, at.Add(time.Duration(duration)*time.Minute))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	booked := map[primitive.ObjectID]bool{}
+	for _, reservation := range reservations {
+		booked[reservation.TableID] = true
+	}
+
+	available := []models.Table{}
+	for _, table := range tables {
+		if table.Guests >= partySize && !booked[table.ID] {
+			available = append(available, table)
+		}
+	}
+	sort.SliceStable(available, func(i, j int) bool {
+		if available[i].Guests != available[j].Guests {
+			return available[i].Guests < available[j].Guests
+		}
+		return available[i].Number < available[j].Number
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(available)
+}
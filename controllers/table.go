@@ -1,18 +1,19 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func GetTables(w http.ResponseWriter, r *http.Request) {
@@ -28,8 +29,7 @@ func GetTables(w http.ResponseWriter, r *http.Request) {
 		skip = 0
 	}
 
-	var tables []models.Table
-	cursor, err := tableCollection.Find(r.Context(), bson.M{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	tables, err := repos.Tables.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -37,13 +37,6 @@ func GetTables(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = cursor.All(r.Context(), &tables); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(tables)
@@ -59,10 +52,9 @@ func GetTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var table models.Table
-	err = tableCollection.FindOne(r.Context(), bson.M{"_id": tableID}).Decode(&table)
+	table, err := repos.Tables.Get(r.Context(), tableID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("Table not found")
@@ -101,7 +93,7 @@ func CreateTable(w http.ResponseWriter, r *http.Request) {
 	table.CreatedOn = time.Now()
 	table.UpdatedOn = time.Now()
 
-	result, err := tableCollection.InsertOne(r.Context(), table)
+	err = repos.Tables.Create(r.Context(), &table)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -111,7 +103,7 @@ func CreateTable(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode("Inserted with ID: " + result.InsertedID.(primitive.ObjectID).Hex())
+	json.NewEncoder(w).Encode("Inserted with ID: " + table.ID.Hex())
 }
 
 func UpdateTable(w http.ResponseWriter, r *http.Request) {
@@ -142,16 +134,43 @@ func UpdateTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Status only changes through the order and invoice flows or /status
+	table.Status = ""
+	table.StatusChangedOn = time.Time{}
 	table.UpdatedOn = time.Now()
 
-	query := bson.M{
-		"_id": tableID,
+	err = repos.Tables.Update(r.Context(), tableID, table)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Table not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
 	}
-	update := bson.M{
-		"$set": table,
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Table updated successfully")
+}
+
+// GetTableStatuses is the floor view for hosts: every table with its current
+// status, ordered by table number. A status query param keeps only the tables
+// in that status.
+func GetTableStatuses(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && !isTableStatus(status) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid status")
+		return
 	}
 
-	result, err := tableCollection.UpdateOne(r.Context(), query, update)
+	tables, err := repos.Tables.List(r.Context(), 0, 0)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -159,12 +178,128 @@ func UpdateTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if result.ModifiedCount == 0 {
+	floor := []models.Table{}
+	for _, table := range tables {
+		if table.Status == "" {
+			table.Status = models.TableStatusFree
+		}
+		if status == "" || table.Status == status {
+			floor = append(floor, table)
+		}
+	}
+	sort.SliceStable(floor, func(i, j int) bool { return floor[i].Number < floor[j].Number })
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(floor)
+}
+
+// UpdateTableStatus lets staff set a status by hand, e.g. needs-cleaning once
+// guests leave and free after the table is cleaned
+func UpdateTableStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tableID, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Table ID")
+		return
+	}
+
+	var table models.Table
+	err = json.NewDecoder(r.Body).Decode(&table)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Table")
+		return
+	}
+
+	if !isTableStatus(table.Status) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid status")
+		return
+	}
+
+	err = repos.Tables.UpdateStatus(r.Context(), tableID, table.Status)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Table not found")
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode("Table not found")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Table is now " + table.Status)
+}
+
+func isTableStatus(status string) bool {
+	switch status {
+	case models.TableStatusFree, models.TableStatusSeated, models.TableStatusOrdering,
+		models.TableStatusAwaitingBill, models.TableStatusNeedsCleaning:
+		return true
+	}
+	return false
+}
+
+// setTableStatus moves a table along as its order progresses. Orders may
+// point at tables that no longer exist, which is not an error here.
+func setTableStatus(ctx context.Context, tableID primitive.ObjectID, status string) error {
+	err := repos.Tables.UpdateStatus(ctx, tableID, status)
+	if err == repository.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// freeTableAfter frees the table an order has left, because it will not be
+// served or moved to another table, unless the table has other orders still
+// being served or waiting to be paid
+func freeTableAfter(ctx context.Context, tableID primitive.ObjectID, orderID primitive.ObjectID) error {
+	orders, err := repos.Orders.ListOpenByTable(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	for _, other := range orders {
+		if other.ID == orderID {
+			continue
+		}
+		invoices, err := repos.Invoices.ListByOrder(ctx, other.ID)
+		if err != nil {
+			return err
+		}
+		if len(invoices) == 0 {
+			return nil
+		}
+		for _, invoice := range invoices {
+			if !helpers.IsInvoiceSettled(invoice.PaymentStatus) {
+				return nil
+			}
+		}
+	}
+	return setTableStatus(ctx, tableID, models.TableStatusFree)
+}
+
+// seatTableOf gives the table of an open order the status the order is at:
+// awaiting the bill once billed, ordering once it has items and seated before
+func seatTableOf(ctx context.Context, order models.Order) error {
+	if order.Billed {
+		return setTableStatus(ctx, order.TableID, models.TableStatusAwaitingBill)
+	}
+	orderItems, err := repos.OrderItems.ListByOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if len(orderItems) > 0 {
+		return setTableStatus(ctx, order.TableID, models.TableStatusOrdering)
+	}
+	return setTableStatus(ctx, order.TableID, models.TableStatusSeated)
 }
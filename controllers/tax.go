@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetTaxRates(w http.ResponseWriter, r *http.Request) {
+	taxRates, err := repos.TaxRates.List(r.Context())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(taxRates)
+}
+
+func GetTaxRate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Tax Rate ID")
+		return
+	}
+
+	taxRateID, _ := primitive.ObjectIDFromHex(id)
+	taxRate, err := repos.TaxRates.Get(r.Context(), taxRateID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Tax rate not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(taxRate)
+}
+
+func CreateTaxRate(w http.ResponseWriter, r *http.Request) {
+	var taxRate models.TaxRate
+	err := json.NewDecoder(r.Body).Decode(&taxRate)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(taxRate)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	// A food's tax category must resolve to exactly one rate
+	categoryTaken, err := repos.TaxRates.CategoryTaken(r.Context(), taxRate.Category, primitive.NilObjectID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if categoryTaken {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Tax rate for this category already exists")
+		return
+	}
+
+	taxRate.CreatedOn = time.Now()
+	taxRate.UpdatedOn = time.Now()
+	err = repos.TaxRates.Create(r.Context(), &taxRate)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(taxRate)
+}
+
+func UpdateTaxRate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if !primitive.IsValidObjectID(id) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Tax Rate ID")
+		return
+	}
+
+	var taxRate models.TaxRate
+	err := json.NewDecoder(r.Body).Decode(&taxRate)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid request body")
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(taxRate)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(err.Error())
+		return
+	}
+
+	taxRateID, _ := primitive.ObjectIDFromHex(id)
+	categoryTaken, err := repos.TaxRates.CategoryTaken(r.Context(), taxRate.Category, taxRateID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	if categoryTaken {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode("Tax rate for this category already exists")
+		return
+	}
+
+	err = repos.TaxRates.Update(r.Context(), taxRateID, taxRate)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("Tax rate not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Tax rate updated successfully")
+}
+
+func taxRatesByCategory(ctx context.Context) (map[string]models.TaxRate, error) {
+	taxRates, err := repos.TaxRates.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byCategory := map[string]models.TaxRate{}
+	for _, taxRate := range taxRates {
+		byCategory[taxRate.Category] = taxRate
+	}
+	return byCategory, nil
+}
+
+// checkTaxCategory returns why a food cannot be saved with the tax category,
+// if it cannot: a category without a tax rate would leave the food untaxed.
+// Foods without a category are untaxed unless a default rate is set up.
+func checkTaxCategory(ctx context.Context, category string) (string, error) {
+	if category == "" || category == models.TaxCategoryDefault {
+		return "", nil
+	}
+	found, err := repos.TaxRates.CategoryTaken(ctx, category, primitive.NilObjectID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "No tax rate is set up for the " + category + " tax category", nil
+	}
+	return "", nil
+}
+
+// checkInvoiceTaxes returns why the invoice cannot be issued when the tax
+// category of one of its lines has no tax rate, as when the rate's category
+// was changed after the food was saved
+func checkInvoiceTaxes(ctx context.Context, invoice models.Invoice) (string, error) {
+	taxRates, err := taxRatesByCategory(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range invoice.Lines {
+		if _, ok := taxRates[line.TaxCategory]; !ok && line.TaxCategory != models.TaxCategoryDefault {
+			return "No tax rate is set up for the " + line.TaxCategory + " tax category of " + line.FoodName, nil
+		}
+	}
+	return "", nil
+}
@@ -1,23 +1,43 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/MayankSaxena03/Restaurant-Management-System/database"
 	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var userCollection = database.OpenCollection(database.Client, "users")
+// UserView is a user as other users see them, without their password and
+// tokens
+type UserView struct {
+	ID        primitive.ObjectID `json:"_id,omitempty"`
+	Username  string             `json:"username,omitempty"`
+	Email     string             `json:"email,omitempty"`
+	Phone     string             `json:"phone,omitempty"`
+	Role      string             `json:"role,omitempty"`
+	CreatedOn time.Time          `json:"createdOn,omitempty"`
+	UpdatedOn time.Time          `json:"updatedOn,omitempty"`
+}
+
+func userView(user models.User) UserView {
+	return UserView{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Role:      user.Role,
+		CreatedOn: user.CreatedOn,
+		UpdatedOn: user.UpdatedOn,
+	}
+}
 
 func GetUsers(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
@@ -32,8 +52,7 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 		skip = 0
 	}
 
-	var users []models.User
-	cursor, err := userCollection.Find(r.Context(), bson.M{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(skip)))
+	users, err := repos.Users.List(r.Context(), int64(skip), int64(limit))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -41,17 +60,14 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = cursor.All(r.Context(), &users)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode("Something went wrong")
-		return
+	views := []UserView{}
+	for _, user := range users {
+		views = append(views, userView(user))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(users)
+	json.NewEncoder(w).Encode(views)
 }
 
 func GetUser(w http.ResponseWriter, r *http.Request) {
@@ -66,13 +82,9 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var user models.User
-	query := bson.M{
-		"_id": userId,
-	}
-	err = userCollection.FindOne(r.Context(), query).Decode(&user)
+	user, err := repos.Users.Get(r.Context(), userId)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("User not found")
@@ -86,7 +98,7 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(user)
+	json.NewEncoder(w).Encode(userView(user))
 }
 
 func SignUp(w http.ResponseWriter, r *http.Request) {
@@ -108,17 +120,7 @@ func SignUp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := bson.M{
-		"$or": []bson.M{
-			{
-				"email": user.Email,
-			},
-			{
-				"phone": user.Phone,
-			},
-		},
-	}
-	count, err := userCollection.CountDocuments(r.Context(), query)
+	userExists, err := repos.Users.EmailOrPhoneTaken(r.Context(), user.Email, user.Phone)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -126,18 +128,41 @@ func SignUp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if count > 0 {
+	if userExists {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode("User with this email or phone already exists")
 		return
 	}
 
+	// Roles are handed out by an admin, so the first user to sign up on a
+	// new install becomes the admin. Once there are users, losing every
+	// admin only lets the configured admin email take over.
+	users, err := repos.Users.Count(r.Context())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	admin, err := isMissingAdmin(r.Context(), user.Email)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+	user.Role = models.RoleWaiter
+	if users == 0 || admin {
+		user.Role = models.RoleAdmin
+	}
+
+	user.ID = primitive.NewObjectID()
 	user.Password = helpers.HashPassword(user.Password)
 	user.CreatedOn = time.Now()
 	user.UpdatedOn = time.Now()
 
-	token, refreshToken, err := helpers.GenerateAllTokens(user.ID, user.Email, user.Username)
+	token, refreshToken, err := helpers.GenerateAllTokens(user.ID, user.Email, user.Username, user.Role, user.TokenVersion)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -148,7 +173,7 @@ func SignUp(w http.ResponseWriter, r *http.Request) {
 	user.Token = token
 	user.RefreshToken = refreshToken
 
-	result, err := userCollection.InsertOne(r.Context(), user)
+	err = repos.Users.Create(r.Context(), &user)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -156,8 +181,6 @@ func SignUp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user.ID = result.InsertedID.(primitive.ObjectID)
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(user)
@@ -173,13 +196,9 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := bson.M{
-		"email": user.Email,
-	}
-	var foundUser models.User
-	err = userCollection.FindOne(r.Context(), query).Decode(&foundUser)
+	foundUser, err := repos.Users.GetByEmail(r.Context(), user.Email)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode("User not found")
@@ -198,7 +217,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, refreshToken, err := helpers.GenerateAllTokens(foundUser.ID, foundUser.Email, foundUser.Username)
+	token, refreshToken, err := helpers.GenerateAllTokens(foundUser.ID, foundUser.Email, foundUser.Username, foundUser.Role, foundUser.TokenVersion)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -206,7 +225,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = helpers.UpdateAllTokens(r.Context(), foundUser.ID, token, refreshToken)
+	err = repos.Users.UpdateTokens(r.Context(), foundUser.ID, token, refreshToken)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -221,3 +240,237 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(foundUser)
 }
+
+func UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid User ID")
+		return
+	}
+
+	var user models.User
+	err = json.NewDecoder(r.Body).Decode(&user)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Request Body")
+		return
+	}
+
+	if !helpers.IsValidRole(user.Role) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Role")
+		return
+	}
+
+	err = repos.Users.UpdateRole(r.Context(), userId, user.Role)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("User not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// The role is carried in the tokens, so the user has to log in again
+	err = repos.Users.RevokeTokens(r.Context(), userId)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("User role updated successfully")
+}
+
+func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var user models.User
+	err := json.NewDecoder(r.Body).Decode(&user)
+	if err != nil || user.RefreshToken == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid Request Body")
+		return
+	}
+
+	claims, err := helpers.ValidateToken(user.RefreshToken)
+	if err != nil || claims.TokenType != helpers.TokenTypeRefresh {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode("Unauthorized")
+		return
+	}
+
+	foundUser, err := repos.Users.Get(r.Context(), claims.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode("Unauthorized")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// Refresh tokens are single use, only the latest one issued is accepted
+	if foundUser.RefreshToken != user.RefreshToken || foundUser.TokenVersion != claims.TokenVersion {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode("Unauthorized")
+		return
+	}
+
+	token, refreshToken, err := helpers.GenerateAllTokens(foundUser.ID, foundUser.Email, foundUser.Username, foundUser.Role, foundUser.TokenVersion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	err = repos.Users.RotateTokens(r.Context(), foundUser.ID, user.RefreshToken, token, refreshToken)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			// Another refresh used the token first
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode("Unauthorized")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.User{Token: token, RefreshToken: refreshToken})
+}
+
+func Logout(w http.ResponseWriter, r *http.Request) {
+	userId, err := primitive.ObjectIDFromHex(r.Header.Get("userId"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode("Unauthorized")
+		return
+	}
+
+	err = repos.Users.RevokeTokens(r.Context(), userId)
+	if err != nil && err != repository.ErrNotFound {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("Logged out successfully")
+}
+
+func ForceLogout(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userId, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode("Invalid User ID")
+		return
+	}
+
+	user, err := repos.Users.Get(r.Context(), userId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("User not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	// role is set by the authentication middleware
+	if helpers.RoleRanksAbove(user.Role, r.Header.Get("role")) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode("Cannot log out a user whose role ranks above yours")
+		return
+	}
+
+	err = repos.Users.RevokeTokens(r.Context(), userId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode("User not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode("Something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode("User logged out successfully")
+}
+
+// MigrateRoles gives the users who signed up before roles existed the waiter
+// role, and makes the user with the configured admin email the admin while
+// there is none, so an existing install is not left without anyone to hand
+// out roles
+func MigrateRoles(ctx context.Context) error {
+	err := repos.Users.AssignMissingRoles(ctx, models.RoleWaiter)
+	if err != nil {
+		return err
+	}
+	if helpers.AdminEmail() == "" {
+		return nil
+	}
+	user, err := repos.Users.GetByEmail(ctx, helpers.AdminEmail())
+	if err == repository.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	admin, err := isMissingAdmin(ctx, user.Email)
+	if err != nil || !admin {
+		return err
+	}
+	return repos.Users.UpdateRole(ctx, user.ID, models.RoleAdmin)
+}
+
+// isMissingAdmin reports whether the user with the email is the configured
+// admin of an install that has no admin
+func isMissingAdmin(ctx context.Context, email string) (bool, error) {
+	if email == "" || email != helpers.AdminEmail() {
+		return false, nil
+	}
+	admins, err := repos.Users.CountByRole(ctx, models.RoleAdmin)
+	if err != nil {
+		return false, err
+	}
+	return admins == 0, nil
+}
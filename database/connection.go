@@ -33,8 +33,6 @@ func DBInstance() *mongo.Client {
 	return client
 }
 
-var Client *mongo.Client = DBInstance()
-
 func OpenCollection(client *mongo.Client, collectionName string) *mongo.Collection {
 	collection := client.Database("restaurant-management").Collection(collectionName)
 	return collection
@@ -0,0 +1,39 @@
+package helpers
+
+import "sync"
+
+// Broker hands every published value to all current subscribers. A
+// subscriber that falls behind misses values rather than blocking publishers.
+type Broker[T any] struct {
+	mu          sync.Mutex
+	subscribers map[chan T]struct{}
+}
+
+func NewBroker[T any]() *Broker[T] {
+	return &Broker[T]{subscribers: map[chan T]struct{}{}}
+}
+
+func (b *Broker[T]) Subscribe() chan T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscriber := make(chan T, 16)
+	b.subscribers[subscriber] = struct{}{}
+	return subscriber
+}
+
+func (b *Broker[T]) Unsubscribe(subscriber chan T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, subscriber)
+}
+
+func (b *Broker[T]) Publish(value T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- value:
+		default:
+		}
+	}
+}
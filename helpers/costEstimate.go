@@ -1,6 +1,14 @@
 package helpers
 
-import "math"
+import (
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 func Round(num float64) int {
 	return int(num + math.Copysign(0.5, num))
@@ -10,3 +18,91 @@ func ToFixed(num float64, precision int) float64 {
 	output := math.Pow(10, float64(precision))
 	return float64(Round(num*output)) / output
 }
+
+// Percentage returns rate percent of amount rounded to 2 decimal places
+func Percentage(amount float64, rate float64) float64 {
+	return ToFixed(amount*rate/100, 2)
+}
+
+// EnvRate reads a percentage from the environment, 0 if missing or invalid
+func EnvRate(key string) float64 {
+	rate, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || rate < 0 {
+		return 0
+	}
+	return rate
+}
+
+// SplitAmount divides amount into parts proportional to weights, each rounded
+// to 2 decimal places. The parts always add up to amount: cents lost to
+// rounding go to the parts that lost the most, earlier parts first on ties.
+// Weights that are all zero split the amount evenly.
+func SplitAmount(amount float64, weights []float64) []float64 {
+	parts := make([]float64, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	totalWeight := 0.0
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		weights = make([]float64, len(parts))
+		for i := range weights {
+			weights[i] = 1
+		}
+		totalWeight = float64(len(weights))
+	}
+
+	cents := Round(amount * 100)
+	sign := 1
+	if cents < 0 {
+		sign, cents = -1, -cents
+	}
+
+	shares := make([]int, len(weights))
+	remainders := make([]float64, len(weights))
+	allocated := 0
+	for i, weight := range weights {
+		exact := float64(cents) * weight / totalWeight
+		shares[i] = int(math.Floor(exact))
+		remainders[i] = exact - float64(shares[i])
+		allocated += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; allocated < cents; i++ {
+		shares[order[i%len(order)]]++
+		allocated++
+	}
+
+	for i, share := range shares {
+		parts[i] = float64(sign*share) / 100
+	}
+	return parts
+}
+
+// PlateCost is what the ingredients of one portion cost by the recipe, at
+// the price each ingredient was last bought at. Ingredients that are missing
+// or were never bought add nothing.
+func PlateCost(recipe models.Recipe, ingredients map[primitive.ObjectID]models.Ingredient) float64 {
+	cost := 0.0
+	for _, recipeIngredient := range recipe.Ingredients {
+		cost += recipeIngredient.Quantity * ingredients[recipeIngredient.IngredientID].CostPrice
+	}
+	return ToFixed(cost, 2)
+}
+
+// MarginPercent is the gross margin as a percentage of the revenue, 0 when
+// there is no revenue
+func MarginPercent(revenue float64, cost float64) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return ToFixed((revenue-cost)/revenue*100, 2)
+}
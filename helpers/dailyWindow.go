@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+// ValidDailyWindow reports whether both ends of the window are "15:04" times
+// and the window is not empty
+func ValidDailyWindow(window models.DailyWindow) bool {
+	from, err := time.Parse("15:04", window.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse("15:04", window.To)
+	if err != nil {
+		return false
+	}
+	return !from.Equal(to)
+}
+
+// InDailyWindows reports whether the time of day of at falls in any of the
+// windows. No windows means all day.
+func InDailyWindows(at time.Time, windows []models.DailyWindow) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	now := at.Format("15:04")
+	for _, window := range windows {
+		if window.From < window.To && now >= window.From && now < window.To {
+			return true
+		}
+		// Windows past midnight, like 22:00 to 02:00
+		if window.From > window.To && (now >= window.From || now < window.To) {
+			return true
+		}
+	}
+	return false
+}
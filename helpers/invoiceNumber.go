@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// When the invoice sequence starts again from 1
+const (
+	InvoiceNumberResetFiscalYear   = "fiscal-year"
+	InvoiceNumberResetCalendarYear = "calendar-year"
+	InvoiceNumberResetMonthly      = "monthly"
+	InvoiceNumberResetNever        = "never"
+)
+
+// InvoiceNumbering builds invoice numbers like BLR1/2026-27/000123 from the
+// outlet prefix, the period the sequence is counted in and the sequence
+type InvoiceNumbering struct {
+	Prefix          string
+	Reset           string
+	FiscalYearStart time.Month
+}
+
+// InvoiceNumberingFromEnv reads INVOICE_PREFIX, INVOICE_NUMBER_RESET and
+// FISCAL_YEAR_START_MONTH. By default numbers start with INV and restart
+// every fiscal year beginning in April.
+func InvoiceNumberingFromEnv() InvoiceNumbering {
+	numbering := InvoiceNumbering{
+		Prefix:          strings.Trim(os.Getenv("INVOICE_PREFIX"), "/ "),
+		Reset:           os.Getenv("INVOICE_NUMBER_RESET"),
+		FiscalYearStart: time.April,
+	}
+	if numbering.Prefix == "" {
+		numbering.Prefix = "INV"
+	}
+	switch numbering.Reset {
+	case InvoiceNumberResetCalendarYear, InvoiceNumberResetMonthly, InvoiceNumberResetNever:
+	default:
+		numbering.Reset = InvoiceNumberResetFiscalYear
+	}
+	month, err := strconv.Atoi(os.Getenv("FISCAL_YEAR_START_MONTH"))
+	if err == nil && month >= 1 && month <= 12 {
+		numbering.FiscalYearStart = time.Month(month)
+	}
+	return numbering
+}
+
+// Period names the stretch of time at falls in, e.g. 2026-27 for the fiscal
+// year from April 2026, and is empty when the sequence never restarts
+func (n InvoiceNumbering) Period(at time.Time) string {
+	switch n.Reset {
+	case InvoiceNumberResetNever:
+		return ""
+	case InvoiceNumberResetMonthly:
+		return at.Format("2006-01")
+	case InvoiceNumberResetCalendarYear:
+		return strconv.Itoa(at.Year())
+	}
+	if n.FiscalYearStart == time.January {
+		return strconv.Itoa(at.Year())
+	}
+	start := at.Year()
+	if at.Month() < n.FiscalYearStart {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// Counter is the name of the counter the sequence for at is kept in, so each
+// outlet and period counts on its own
+func (n InvoiceNumbering) Counter(at time.Time) string {
+	return strings.TrimSuffix("invoice/"+n.Prefix+"/"+n.Period(at), "/")
+}
+
+func (n InvoiceNumbering) Format(at time.Time, sequence int64) string {
+	parts := []string{n.Prefix}
+	if period := n.Period(at); period != "" {
+		parts = append(parts, period)
+	}
+	parts = append(parts, fmt.Sprintf("%06d", sequence))
+	return strings.Join(parts, "/")
+}
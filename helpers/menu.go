@@ -0,0 +1,26 @@
+package helpers
+
+import (
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+// IsMenuActive reports whether the menu is being served at the given time:
+// between its start and end dates, when they are set, and in one of its
+// serving hours
+func IsMenuActive(menu models.Menu, at time.Time) bool {
+	if !menu.StartDate.IsZero() && at.Before(menu.StartDate) {
+		return false
+	}
+	if !menu.EndDate.IsZero() && at.After(menu.EndDate) {
+		return false
+	}
+	return InDailyWindows(at, menu.ServingHours)
+}
+
+// IsFoodAvailable reports whether the food has not been 86'd and has
+// portions left, if they are counted
+func IsFoodAvailable(food models.Food) bool {
+	return !food.SoldOut && (food.RemainingPortions == nil || *food.RemainingPortions > 0)
+}
@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+// CheckModifierGroups checks the modifier groups of a food, returning what
+// is wrong with them
+func CheckModifierGroups(groups []models.ModifierGroup) string {
+	names := map[string]bool{}
+	for _, group := range groups {
+		if names[strings.ToLower(group.Name)] {
+			return "Modifier group " + group.Name + " is listed twice"
+		}
+		names[strings.ToLower(group.Name)] = true
+
+		if group.Max > 0 && group.Min > group.Max {
+			return "Modifier group " + group.Name + " needs a min no greater than its max"
+		}
+		if group.Min > len(group.Options) {
+			return "Modifier group " + group.Name + " has fewer options than its min"
+		}
+		options := map[string]bool{}
+		for _, option := range group.Options {
+			if options[strings.ToLower(option.Name)] {
+				return "Option " + option.Name + " is listed twice in " + group.Name
+			}
+			options[strings.ToLower(option.Name)] = true
+		}
+	}
+	return ""
+}
+
+// ResolveModifiers checks the modifiers picked for an order item against the
+// modifier groups of its food and prices them from the food. Group and
+// option names are matched regardless of case. It returns what is wrong
+// with the selection, if anything.
+func ResolveModifiers(food models.Food, picked []models.OrderItemModifier) ([]models.OrderItemModifier, string) {
+	resolved := []models.OrderItemModifier{}
+	counts := map[string]int{}
+	seen := map[string]bool{}
+	for _, modifier := range picked {
+		group, ok := findModifierGroup(food.ModifierGroups, modifier.Group)
+		if !ok {
+			return nil, food.Name + " has no " + modifier.Group + " to choose"
+		}
+		option, ok := findModifierOption(group.Options, modifier.Option)
+		if !ok {
+			return nil, modifier.Option + " is not a " + group.Name + " of " + food.Name
+		}
+		key := group.Name + "\x00" + option.Name
+		if seen[key] {
+			return nil, option.Name + " is picked more than once for " + food.Name
+		}
+		seen[key] = true
+		counts[group.Name]++
+		resolved = append(resolved, models.OrderItemModifier{Group: group.Name, Option: option.Name, Price: option.Price})
+	}
+
+	for _, group := range food.ModifierGroups {
+		if counts[group.Name] < group.Min {
+			return nil, fmt.Sprintf("Choose at least %d %s for %s", group.Min, group.Name, food.Name)
+		}
+		if group.Max > 0 && counts[group.Name] > group.Max {
+			return nil, fmt.Sprintf("Choose at most %d %s for %s", group.Max, group.Name, food.Name)
+		}
+	}
+	return resolved, ""
+}
+
+// OrderItemUnitPrice is the price of one unit of the order item including
+// its modifiers
+func OrderItemUnitPrice(orderItem models.OrderItem) float64 {
+	price := orderItem.UnitPrice
+	for _, modifier := range orderItem.Modifiers {
+		price += modifier.Price
+	}
+	return ToFixed(price, 2)
+}
+
+// ModifierNames lists the modifiers the way the kitchen and receipts show
+// them, like "Size: Large"
+func ModifierNames(modifiers []models.OrderItemModifier) []string {
+	names := []string{}
+	for _, modifier := range modifiers {
+		names = append(names, modifier.Group+": "+modifier.Option)
+	}
+	return names
+}
+
+func findModifierGroup(groups []models.ModifierGroup, name string) (models.ModifierGroup, bool) {
+	for _, group := range groups {
+		if strings.EqualFold(group.Name, name) {
+			return group, true
+		}
+	}
+	return models.ModifierGroup{}, false
+}
+
+func findModifierOption(options []models.ModifierOption, name string) (models.ModifierOption, bool) {
+	for _, option := range options {
+		if strings.EqualFold(option.Name, name) {
+			return option, true
+		}
+	}
+	return models.ModifierOption{}, false
+}
@@ -0,0 +1,26 @@
+package helpers
+
+import "github.com/MayankSaxena03/Restaurant-Management-System/models"
+
+// Allowed order status transitions. Orders that have not reached the kitchen
+// are cancelled, anything after that is voided. Closed, cancelled and voided
+// orders are final.
+var orderStatusTransitions = map[string][]string{
+	models.OrderStatusPlaced:    {models.OrderStatusInKitchen, models.OrderStatusCancelled},
+	models.OrderStatusInKitchen: {models.OrderStatusReady, models.OrderStatusVoided},
+	models.OrderStatusReady:     {models.OrderStatusServed, models.OrderStatusVoided},
+	models.OrderStatusServed:    {models.OrderStatusClosed, models.OrderStatusVoided},
+}
+
+func CanTransitionOrder(from string, to string) bool {
+	// Orders created before statuses existed are treated as placed
+	if from == "" {
+		from = models.OrderStatusPlaced
+	}
+	for _, next := range orderStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
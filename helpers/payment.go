@@ -0,0 +1,26 @@
+package helpers
+
+import "github.com/MayankSaxena03/Restaurant-Management-System/models"
+
+// InvoicePaymentStatus derives the status of an invoice from what it charges
+// and what has been paid against it, compared to the cent
+func InvoicePaymentStatus(total float64, paid float64) string {
+	totalCents := Round(total * 100)
+	paidCents := Round(paid * 100)
+	switch {
+	case paidCents == totalCents:
+		return models.PaymentStatusPaid
+	case paidCents <= 0:
+		return models.PaymentStatusUnpaid
+	case paidCents < totalCents:
+		return models.PaymentStatusPartiallyPaid
+	default:
+		return models.PaymentStatusOverpaid
+	}
+}
+
+// IsInvoiceSettled reports whether nothing is left to pay. Invoices from
+// before the payments ledger carry a hand-set "PAID".
+func IsInvoiceSettled(status string) bool {
+	return status == models.PaymentStatusPaid || status == models.PaymentStatusOverpaid || status == "PAID"
+}
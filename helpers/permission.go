@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+const (
+	PermissionManageMenu       = "menu:manage"
+	PermissionManageTables     = "tables:manage"
+	PermissionSeatGuests       = "tables:seat"
+	PermissionReservations     = "reservations:write"
+	PermissionTakeOrders       = "orders:write"
+	PermissionCookOrders       = "orders:cook"
+	PermissionCloseOrders      = "orders:close"
+	PermissionVoidOrders       = "orders:void"
+	PermissionReadInvoices     = "invoices:read"
+	PermissionCreateInvoice    = "invoices:create"
+	PermissionUpdateInvoice    = "invoices:update"
+	PermissionRefundInvoice    = "invoices:refund"
+	PermissionApproveRefund    = "refunds:approve"
+	PermissionManagePromotions = "promotions:manage"
+	PermissionManageInventory  = "inventory:manage"
+	PermissionReadReports      = "reports:read"
+	PermissionCloseDay         = "reports:close"
+	PermissionReadUsers        = "users:read"
+	PermissionManageUsers      = "users:manage"
+	PermissionRevokeUsers      = "users:revoke"
+)
+
+var rolePermissions = map[string][]string{
+	models.RoleAdmin: {
+		PermissionManageMenu, PermissionManageTables, PermissionSeatGuests, PermissionReservations,
+		PermissionTakeOrders, PermissionCookOrders, PermissionCloseOrders, PermissionVoidOrders,
+		PermissionReadInvoices, PermissionCreateInvoice, PermissionUpdateInvoice, PermissionRefundInvoice,
+		PermissionApproveRefund, PermissionManagePromotions, PermissionManageInventory, PermissionReadReports,
+		PermissionCloseDay, PermissionReadUsers, PermissionManageUsers, PermissionRevokeUsers,
+	},
+	models.RoleManager: {
+		PermissionManageMenu, PermissionManageTables, PermissionSeatGuests, PermissionReservations,
+		PermissionTakeOrders, PermissionCookOrders, PermissionCloseOrders, PermissionVoidOrders,
+		PermissionReadInvoices, PermissionCreateInvoice, PermissionUpdateInvoice, PermissionRefundInvoice,
+		PermissionApproveRefund, PermissionManagePromotions, PermissionManageInventory, PermissionReadReports,
+		PermissionCloseDay, PermissionRevokeUsers,
+	},
+	models.RoleWaiter: {
+		PermissionSeatGuests, PermissionReservations, PermissionTakeOrders, PermissionCloseOrders,
+		PermissionReadInvoices, PermissionCreateInvoice,
+	},
+	models.RoleCashier: {
+		PermissionCloseOrders, PermissionReadInvoices, PermissionCreateInvoice, PermissionUpdateInvoice,
+		PermissionRefundInvoice,
+	},
+	models.RoleChef: {
+		PermissionCookOrders,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// roleRanks orders the roles by how far they reach, from the admin down to
+// the floor staff
+var roleRanks = map[string]int{
+	models.RoleAdmin:   3,
+	models.RoleManager: 2,
+	models.RoleWaiter:  1,
+	models.RoleCashier: 1,
+	models.RoleChef:    1,
+}
+
+// RoleRanksAbove reports whether role ranks above other, so a user with other
+// cannot act on a user with role
+func RoleRanksAbove(role string, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
+
+func HasPermission(role string, permission string) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminEmail returns the email of the user to make admin while an install
+// has no admin, set with ADMIN_EMAIL
+func AdminEmail() string {
+	return strings.TrimSpace(os.Getenv("ADMIN_EMAIL"))
+}
@@ -0,0 +1,292 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PromotionLine is an order item as promotions see it. Discount is what the
+// promotions applied so far took off the line.
+type PromotionLine struct {
+	OrderItemID primitive.ObjectID
+	FoodID      primitive.ObjectID
+	FoodName    string
+	UnitPrice   float64
+	Quantity    int
+	OrderedOn   time.Time
+	Discount    float64
+}
+
+// remaining is what the line still costs after its discounts
+func (l PromotionLine) remaining() float64 {
+	return ToFixed(l.UnitPrice*float64(l.Quantity)-l.Discount, 2)
+}
+
+// ApplyPromotions applies the promotions to lines in the order given, each
+// one on what the earlier ones left, and adds what they took off to the
+// lines' Discount. Promotions with a coupon code only apply when the code is
+// in coupons; the others only to lines ordered while they were running. It
+// returns the promotions that took anything off, with an explanation of each.
+// The caller passes only promotions that are not used up.
+func ApplyPromotions(lines []PromotionLine, promotions []models.Promotion, coupons []string) []models.InvoicePromotion {
+	applied := []models.InvoicePromotion{}
+	for _, promotion := range promotions {
+		if promotion.CouponCode != "" && !hasCoupon(coupons, promotion.CouponCode) {
+			continue
+		}
+
+		orderTotal := 0.0
+		for _, line := range lines {
+			orderTotal += line.remaining()
+		}
+		if ToFixed(orderTotal, 2) < promotion.MinOrderAmount {
+			continue
+		}
+
+		eligible := []int{}
+		for i, line := range lines {
+			if line.remaining() > 0 && promotionCovers(promotion, line) {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		var off []float64
+		var explanation string
+		switch promotion.Type {
+		case models.PromotionTypePercentage:
+			off, explanation = percentageOff(promotion, lines, eligible)
+		case models.PromotionTypeFlat:
+			off, explanation = flatOff(promotion, lines, eligible)
+		case models.PromotionTypeBuyXGetY:
+			off, explanation = buyXGetYOff(promotion, lines, eligible)
+		case models.PromotionTypeCombo:
+			off, explanation = comboOff(promotion, lines, eligible)
+		}
+
+		total := 0.0
+		names := []string{}
+		for i := range off {
+			amount := ToFixed(off[i], 2)
+			if amount > lines[i].remaining() {
+				amount = lines[i].remaining()
+			}
+			if amount <= 0 {
+				continue
+			}
+			lines[i].Discount = ToFixed(lines[i].Discount+amount, 2)
+			total += amount
+			names = appendOnce(names, lines[i].FoodName)
+		}
+		total = ToFixed(total, 2)
+		if total <= 0 {
+			continue
+		}
+
+		switch {
+		case promotion.Type == models.PromotionTypeCombo:
+		case promotion.Type == models.PromotionTypeBuyXGetY && len(promotion.FoodIDs) > 0:
+			explanation += " on " + strings.Join(names, ", ")
+		case len(promotion.FoodIDs) > 0:
+			explanation += " " + strings.Join(names, ", ")
+		case promotion.Type != models.PromotionTypeBuyXGetY:
+			explanation += " the order"
+		}
+		if len(promotion.HappyHours) > 0 {
+			windows := []string{}
+			for _, window := range promotion.HappyHours {
+				windows = append(windows, window.From+"-"+window.To)
+			}
+			explanation += ", happy hour " + strings.Join(windows, ", ")
+		}
+		if promotion.CouponCode != "" {
+			explanation = "Coupon " + promotion.CouponCode + ": " + explanation
+		}
+		applied = append(applied, models.InvoicePromotion{
+			PromotionID: promotion.ID,
+			Name:        promotion.Name,
+			CouponCode:  promotion.CouponCode,
+			Amount:      total,
+			Explanation: explanation,
+		})
+	}
+	return applied
+}
+
+// promotionCovers reports whether the promotion can apply to the line: its
+// food is one of the promotion's, if it names any, and it was ordered in the
+// promotion's happy hours, if it has any. A promotion without a coupon must
+// also have been running when the line was ordered, so it is priced as it
+// was when ordering.
+func promotionCovers(promotion models.Promotion, line PromotionLine) bool {
+	if promotion.CouponCode == "" && !PromotionRunning(promotion, line.OrderedOn) {
+		return false
+	}
+	if len(promotion.FoodIDs) > 0 {
+		found := false
+		for _, foodID := range promotion.FoodIDs {
+			if foodID == line.FoodID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return InDailyWindows(line.OrderedOn, promotion.HappyHours)
+}
+
+// PromotionRunning reports whether the promotion's dates cover at
+func PromotionRunning(promotion models.Promotion, at time.Time) bool {
+	return (promotion.StartDate.IsZero() || !promotion.StartDate.After(at)) &&
+		(promotion.EndDate.IsZero() || !promotion.EndDate.Before(at))
+}
+
+func percentageOff(promotion models.Promotion, lines []PromotionLine, eligible []int) ([]float64, string) {
+	off := make([]float64, len(lines))
+	for _, i := range eligible {
+		off[i] = Percentage(lines[i].remaining(), promotion.Value)
+	}
+	return off, strconv.FormatFloat(promotion.Value, 'f', -1, 64) + "% off"
+}
+
+// flatOff shares the flat amount out over the eligible lines by what they cost
+func flatOff(promotion models.Promotion, lines []PromotionLine, eligible []int) ([]float64, string) {
+	off := make([]float64, len(lines))
+	weights := []float64{}
+	total := 0.0
+	for _, i := range eligible {
+		weights = append(weights, lines[i].remaining())
+		total += lines[i].remaining()
+	}
+	amount := promotion.Value
+	if amount > total {
+		amount = total
+	}
+	for j, share := range SplitAmount(amount, weights) {
+		off[eligible[j]] = share
+	}
+	return off, fmt.Sprintf("%.2f off", promotion.Value)
+}
+
+// buyXGetYOff gives away the cheapest units, FreeQuantity out of every
+// BuyQuantity + FreeQuantity units bought
+func buyXGetYOff(promotion models.Promotion, lines []PromotionLine, eligible []int) ([]float64, string) {
+	off := make([]float64, len(lines))
+	explanation := fmt.Sprintf("Buy %d get %d free", promotion.BuyQuantity, promotion.FreeQuantity)
+	group := promotion.BuyQuantity + promotion.FreeQuantity
+	if promotion.BuyQuantity <= 0 || promotion.FreeQuantity <= 0 {
+		return off, explanation
+	}
+
+	units := []int{}
+	for _, i := range eligible {
+		for unit := 0; unit < lines[i].Quantity; unit++ {
+			units = append(units, i)
+		}
+	}
+	sort.SliceStable(units, func(a, b int) bool { return lines[units[a]].UnitPrice > lines[units[b]].UnitPrice })
+
+	free := len(units) / group * promotion.FreeQuantity
+	for _, i := range units[len(units)-free:] {
+		off[i] += lines[i].UnitPrice
+	}
+	return off, explanation
+}
+
+// comboOff sells every full set of the combo's foods for the combo price.
+// What a set saves is shared between its foods by their prices.
+func comboOff(promotion models.Promotion, lines []PromotionLine, eligible []int) ([]float64, string) {
+	off := make([]float64, len(lines))
+	names := []string{}
+	needed := map[primitive.ObjectID]int{}
+	for _, foodID := range promotion.FoodIDs {
+		needed[foodID]++
+	}
+
+	units := map[primitive.ObjectID]int{}
+	prices := map[primitive.ObjectID]float64{}
+	for _, i := range eligible {
+		if _, ok := prices[lines[i].FoodID]; !ok {
+			prices[lines[i].FoodID] = lines[i].UnitPrice
+		}
+		units[lines[i].FoodID] += lines[i].Quantity
+	}
+
+	sets := -1
+	fullPrice := 0.0
+	weights := []float64{}
+	for _, foodID := range promotion.FoodIDs {
+		if sets < 0 || units[foodID]/needed[foodID] < sets {
+			sets = units[foodID] / needed[foodID]
+		}
+		fullPrice += prices[foodID]
+		weights = append(weights, prices[foodID])
+	}
+	for _, i := range eligible {
+		names = appendOnce(names, lines[i].FoodName)
+	}
+	explanation := fmt.Sprintf("%s for %.2f", strings.Join(names, " + "), promotion.Value)
+	saving := ToFixed(fullPrice-promotion.Value, 2)
+	if sets <= 0 || saving <= 0 {
+		return off, explanation
+	}
+	if sets > 1 {
+		explanation += fmt.Sprintf(" x%d", sets)
+	}
+
+	savings := map[primitive.ObjectID]float64{}
+	for j, share := range SplitAmount(saving, weights) {
+		savings[promotion.FoodIDs[j]] += share
+	}
+	for foodID, share := range savings {
+		// The units that make up the sets are taken from the food's lines
+		// in order
+		left := sets * needed[foodID]
+		taken := []int{}
+		used := []float64{}
+		for _, i := range eligible {
+			if lines[i].FoodID != foodID || left == 0 {
+				continue
+			}
+			quantity := lines[i].Quantity
+			if quantity > left {
+				quantity = left
+			}
+			left -= quantity
+			taken = append(taken, i)
+			used = append(used, float64(quantity))
+		}
+		for j, amount := range SplitAmount(share*float64(sets), used) {
+			off[taken[j]] += amount
+		}
+	}
+	return off, explanation
+}
+
+func hasCoupon(coupons []string, code string) bool {
+	for _, coupon := range coupons {
+		if strings.EqualFold(coupon, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendOnce(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,362 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+// Characters per line of the usual thermal paper widths in the printer's
+// standard font
+const (
+	ReceiptWidth80mm = 48
+	ReceiptWidth58mm = 32
+)
+
+// ReceiptHeader is the restaurant's name and contact details printed at the
+// top of every receipt
+type ReceiptHeader struct {
+	Name    string
+	Address string
+	Phone   string
+	GSTIN   string
+}
+
+// ReceiptHeaderFromEnv reads RESTAURANT_NAME, RESTAURANT_ADDRESS,
+// RESTAURANT_PHONE and RESTAURANT_GSTIN
+func ReceiptHeaderFromEnv() ReceiptHeader {
+	header := ReceiptHeader{
+		Name:    os.Getenv("RESTAURANT_NAME"),
+		Address: os.Getenv("RESTAURANT_ADDRESS"),
+		Phone:   os.Getenv("RESTAURANT_PHONE"),
+		GSTIN:   os.Getenv("RESTAURANT_GSTIN"),
+	}
+	if header.Name == "" {
+		header.Name = "Restaurant"
+	}
+	return header
+}
+
+// Receipt is everything printed on the receipt of an invoice
+type Receipt struct {
+	Header   ReceiptHeader
+	Invoice  models.Invoice
+	Payments []models.Payment
+}
+
+// receiptLine is one printed line. Renderers pad centered lines themselves,
+// double lines are printed at twice the width and height where they can be.
+type receiptLine struct {
+	text   string
+	center bool
+	bold   bool
+	double bool
+}
+
+// Text lays the receipt out in width columns of plain text
+func (r Receipt) Text(width int) []byte {
+	var text bytes.Buffer
+	for _, line := range r.lines(width) {
+		if line.center && len(line.text) < width {
+			text.WriteString(strings.Repeat(" ", (width-len(line.text))/2))
+		}
+		text.WriteString(line.text)
+		text.WriteString("\n")
+	}
+	return text.Bytes()
+}
+
+// ESC/POS commands understood by practically every receipt printer
+var (
+	escposInit       = []byte{0x1b, 0x40}
+	escposAlignLeft  = []byte{0x1b, 0x61, 0x00}
+	escposAlignMid   = []byte{0x1b, 0x61, 0x01}
+	escposBoldOn     = []byte{0x1b, 0x45, 0x01}
+	escposBoldOff    = []byte{0x1b, 0x45, 0x00}
+	escposDoubleOn   = []byte{0x1d, 0x21, 0x11}
+	escposDoubleOff  = []byte{0x1d, 0x21, 0x00}
+	escposFeed       = []byte{0x1b, 0x64, 0x04}
+	escposPartialCut = []byte{0x1d, 0x56, 0x42, 0x00}
+)
+
+// ESCPOS lays the receipt out in width columns as raw ESC/POS bytes, ending
+// with a paper feed and cut
+func (r Receipt) ESCPOS(width int) []byte {
+	var escpos bytes.Buffer
+	escpos.Write(escposInit)
+	for _, line := range r.lines(width) {
+		if line.center {
+			escpos.Write(escposAlignMid)
+		} else {
+			escpos.Write(escposAlignLeft)
+		}
+		if line.bold {
+			escpos.Write(escposBoldOn)
+		}
+		if line.double {
+			escpos.Write(escposDoubleOn)
+		}
+		escpos.WriteString(ascii(line.text))
+		escpos.WriteString("\n")
+		if line.double {
+			escpos.Write(escposDoubleOff)
+		}
+		if line.bold {
+			escpos.Write(escposBoldOff)
+		}
+	}
+	escpos.Write(escposAlignLeft)
+	escpos.Write(escposFeed)
+	escpos.Write(escposPartialCut)
+	return escpos.Bytes()
+}
+
+// PDF lays the 80mm receipt out on a single page of the same width, in
+// Courier so the columns line up
+func (r Receipt) PDF() []byte {
+	const (
+		pageWidth  = 226.77 // 80mm in points
+		margin     = 14.0
+		fontSize   = 7.0
+		lineHeight = 9.0
+	)
+	lines := r.lines(ReceiptWidth80mm)
+
+	height := 2 * margin
+	for _, line := range lines {
+		if line.double {
+			height += 2 * lineHeight
+		} else {
+			height += lineHeight
+		}
+	}
+
+	var content bytes.Buffer
+	y := height - margin
+	for _, line := range lines {
+		size := fontSize
+		if line.double {
+			size = 2 * fontSize
+		}
+		y -= size * lineHeight / fontSize
+		font := "F1"
+		if line.bold {
+			font = "F2"
+		}
+		// Courier glyphs are 0.6 of the font size wide
+		textWidth := 0.6 * size * float64(len(line.text))
+		x := (pageWidth - 0.6*fontSize*ReceiptWidth80mm) / 2
+		if line.center {
+			x = (pageWidth - textWidth) / 2
+		}
+		fmt.Fprintf(&content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y+2, pdfEscape(ascii(line.text)))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pageWidth, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, pdf.Len())
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+func (r Receipt) lines(width int) []receiptLine {
+	invoice := r.Invoice
+	rule := receiptLine{text: strings.Repeat("-", width)}
+	lines := []receiptLine{}
+
+	for _, text := range wrap(r.Header.Name, width/2) {
+		lines = append(lines, receiptLine{text: text, center: true, bold: true, double: true})
+	}
+	for _, text := range wrap(r.Header.Address, width) {
+		lines = append(lines, receiptLine{text: text, center: true})
+	}
+	if r.Header.Phone != "" {
+		for _, text := range wrap("Phone: "+r.Header.Phone, width) {
+			lines = append(lines, receiptLine{text: text, center: true})
+		}
+	}
+	if r.Header.GSTIN != "" {
+		for _, text := range wrap("GSTIN: "+r.Header.GSTIN, width) {
+			lines = append(lines, receiptLine{text: text, center: true})
+		}
+	}
+	lines = append(lines, rule)
+
+	number := invoice.Number
+	if number == "" {
+		number = invoice.ID.Hex()
+	}
+	lines = append(lines, receiptLine{text: "Invoice: " + number})
+	if invoice.SplitParts > 0 {
+		lines = append(lines, receiptLine{text: fmt.Sprintf("Bill part %d of %d", invoice.SplitPart, invoice.SplitParts)})
+	}
+	lines = append(lines, receiptLine{text: "Date: " + invoice.CreatedOn.Local().Format("02-01-2006 15:04")})
+	if invoice.TableNumber > 0 {
+		lines = append(lines, receiptLine{text: "Table: " + strconv.Itoa(invoice.TableNumber)})
+	}
+	lines = append(lines, rule)
+
+	// Wide paper fits quantity, rate and amount next to the name, narrow
+	// paper prints them on a line of their own
+	const columns = 23
+	if width-columns >= 16 {
+		nameWidth := width - columns
+		lines = append(lines, receiptLine{text: fmt.Sprintf("%-*s%4s%9s%10s", nameWidth, "Item", "Qty", "Rate", "Amount")})
+		for _, line := range invoice.Lines {
+			names := wrap(line.FoodName, nameWidth)
+			if len(names) == 0 {
+				names = []string{""}
+			}
+			lines = append(lines, receiptLine{text: fmt.Sprintf("%-*s%4d%9.2f%10.2f", nameWidth, names[0], line.Quantity, line.UnitPrice, line.Amount)})
+			for _, name := range names[1:] {
+				lines = append(lines, receiptLine{text: name})
+			}
+			for _, modifier := range ModifierNames(line.Modifiers) {
+				lines = append(lines, receiptLine{text: truncate("  "+modifier, nameWidth)})
+			}
+		}
+	} else {
+		for _, line := range invoice.Lines {
+			for _, name := range wrap(line.FoodName, width) {
+				lines = append(lines, receiptLine{text: name})
+			}
+			for _, modifier := range ModifierNames(line.Modifiers) {
+				lines = append(lines, receiptLine{text: truncate("  "+modifier, width)})
+			}
+			lines = append(lines, amountLine(fmt.Sprintf("  %d x %.2f", line.Quantity, line.UnitPrice), line.Amount, width))
+		}
+	}
+	lines = append(lines, rule)
+
+	for _, promotion := range invoice.Promotions {
+		lines = append(lines, amountLine(promotion.Name, -promotion.Amount, width))
+	}
+	lines = append(lines, amountLine("Taxable amount", invoice.SubTotal, width))
+	for _, tax := range invoice.Taxes {
+		lines = append(lines, amountLine(tax.Name+" "+strconv.FormatFloat(tax.Rate, 'f', -1, 64)+"%", tax.Amount, width))
+	}
+	if invoice.ServiceCharge != 0 {
+		lines = append(lines, amountLine("Service charge", invoice.ServiceCharge, width))
+	}
+	if invoice.RoundingAdjustment != 0 {
+		lines = append(lines, amountLine("Rounding", invoice.RoundingAdjustment, width))
+	}
+	total := amountLine("TOTAL", invoice.GrandTotal, width)
+	total.bold = true
+	lines = append(lines, total)
+	if invoice.Credited != 0 {
+		lines = append(lines, amountLine("Credit notes", -invoice.Credited, width))
+	}
+	lines = append(lines, rule)
+
+	for _, payment := range r.Payments {
+		lines = append(lines, amountLine("Paid by "+paymentMethodName(payment.Method), payment.Amount, width))
+		if payment.Reference != "" {
+			lines = append(lines, receiptLine{text: truncate("  Ref: "+payment.Reference, width)})
+		}
+		if payment.ChangeGiven != 0 {
+			lines = append(lines, amountLine("Change given", payment.ChangeGiven, width))
+		}
+	}
+	if invoice.Refunded != 0 {
+		lines = append(lines, amountLine("Refunded", invoice.Refunded, width))
+	}
+	lines = append(lines, amountLine("Balance due", invoice.Balance, width))
+	if invoice.PaymentStatus != "" {
+		lines = append(lines, receiptLine{text: "Status: " + strings.ToUpper(invoice.PaymentStatus)})
+	}
+	lines = append(lines, rule)
+	lines = append(lines, receiptLine{text: "Thank you, visit again!", center: true})
+	return lines
+}
+
+// amountLine puts label on the left and amount on the right of the line
+func amountLine(label string, amount float64, width int) receiptLine {
+	value := fmt.Sprintf("%.2f", amount)
+	label = truncate(label, width-len(value)-1)
+	return receiptLine{text: label + strings.Repeat(" ", width-len(label)-len(value)) + value}
+}
+
+func paymentMethodName(method string) string {
+	switch method {
+	case models.PaymentMethodUPI:
+		return "UPI"
+	case "":
+		return method
+	}
+	return strings.ToUpper(method[:1]) + method[1:]
+}
+
+// wrap breaks text into lines of at most width characters, at spaces where
+// it can
+func wrap(text string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(ascii(text)) {
+		for len(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func truncate(text string, width int) string {
+	text = ascii(text)
+	if len(text) > width {
+		return text[:width]
+	}
+	return text
+}
+
+// ascii replaces what receipt printers and the PDF base fonts cannot print
+func ascii(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, text)
+}
+
+func pdfEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
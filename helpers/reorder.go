@@ -0,0 +1,22 @@
+package helpers
+
+import (
+	"math"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+// ReorderQuantity is how much of an ingredient to buy so that, with what is
+// already on order, it lasts coverDays at its average daily use and is then
+// still at its low stock threshold. It is 0 when the ingredient is not low
+// on stock or enough is on order. Quantities are rounded up to whole units.
+func ReorderQuantity(ingredient models.Ingredient, dailyUse float64, onOrder float64, coverDays int) float64 {
+	if ingredient.Stock > ingredient.LowStockThreshold {
+		return 0
+	}
+	needed := ingredient.LowStockThreshold + dailyUse*float64(coverDays) - ingredient.Stock - onOrder
+	if needed <= 0 {
+		return 0
+	}
+	return math.Ceil(ToFixed(needed, 3))
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import "github.com/MayankSaxena03/Restaurant-Management-System/models"
+
+// LineTax splits a line amount into its taxable value and the tax charged for
+// each component of the rate. For inclusive rates the amount already contains
+// the tax, otherwise the tax is charged on top of it.
+func LineTax(amount float64, taxRate models.TaxRate) (float64, []models.InvoiceTax) {
+	totalRate := 0.0
+	for _, component := range taxRate.Components {
+		totalRate += component.Rate
+	}
+	if totalRate == 0 {
+		return amount, []models.InvoiceTax{}
+	}
+
+	netAmount := amount
+	if taxRate.Inclusive {
+		netAmount = ToFixed(amount*100/(100+totalRate), 2)
+	}
+
+	taxes := []models.InvoiceTax{}
+	taxTotal := 0.0
+	for _, component := range taxRate.Components {
+		tax := models.InvoiceTax{
+			Name:   component.Name,
+			Rate:   component.Rate,
+			Amount: Percentage(netAmount, component.Rate),
+		}
+		taxes = append(taxes, tax)
+		taxTotal += tax.Amount
+	}
+
+	// Rounding must never change what the customer sees on the menu
+	if taxRate.Inclusive {
+		netAmount = ToFixed(amount-taxTotal, 2)
+	}
+
+	return netAmount, taxes
+}
+
+// SumTaxes adds up taxes with the same name and rate, keeping the order in which they first appear
+func SumTaxes(taxes []models.InvoiceTax) []models.InvoiceTax {
+	totals := []models.InvoiceTax{}
+	for _, tax := range taxes {
+		found := false
+		for i := range totals {
+			if totals[i].Name == tax.Name && totals[i].Rate == tax.Rate {
+				totals[i].Amount = ToFixed(totals[i].Amount+tax.Amount, 2)
+				found = true
+				break
+			}
+		}
+		if !found {
+			totals = append(totals, tax)
+		}
+	}
+	return totals
+}
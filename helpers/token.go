@@ -1,42 +1,54 @@
 package helpers
 
 import (
-	"context"
 	"errors"
 	"os"
 	"time"
 
-	"github.com/MayankSaxena03/Restaurant-Management-System/database"
 	"github.com/dgrijalva/jwt-go"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var userCollection = database.OpenCollection(database.Client, "users")
 var SECRETKEY = os.Getenv("SECRETKEY")
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type SignedDetails struct {
-	UserID   primitive.ObjectID
-	Username string
-	Email    string
+	UserID       primitive.ObjectID
+	Username     string
+	Email        string
+	Role         string
+	TokenType    string
+	TokenVersion int
 	jwt.StandardClaims
 }
 
-func GenerateAllTokens(userID primitive.ObjectID, email string, username string) (string, string, error) {
+func GenerateAllTokens(userID primitive.ObjectID, email string, username string, role string, tokenVersion int) (string, string, error) {
 	claims := &SignedDetails{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+		UserID:       userID,
+		Username:     username,
+		Email:        email,
+		Role:         role,
+		TokenType:    TokenTypeAccess,
+		TokenVersion: tokenVersion,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
 		},
 	}
 
 	refreshClaims := &SignedDetails{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
+		UserID:       userID,
+		Username:     username,
+		Email:        email,
+		Role:         role,
+		TokenType:    TokenTypeRefresh,
+		TokenVersion: tokenVersion,
 		StandardClaims: jwt.StandardClaims{
+			// A unique ID keeps a rotated refresh token from matching the one it replaces
+			Id:        primitive.NewObjectID().Hex(),
 			ExpiresAt: time.Now().Add(time.Hour * 24 * 7).Unix(),
 		},
 	}
@@ -54,21 +66,6 @@ func GenerateAllTokens(userID primitive.ObjectID, email string, username string)
 	return token, refreshToken, nil
 }
 
-func UpdateAllTokens(ctx context.Context, userID primitive.ObjectID, token string, refreshToken string) error {
-	query := bson.M{
-		"_id": userID,
-	}
-	update := bson.M{
-		"$set": bson.M{
-			"token":        token,
-			"refreshToken": refreshToken,
-			"updatedOn":    time.Now(),
-		},
-	}
-	_, err := userCollection.UpdateOne(ctx, query, update)
-	return err
-}
-
 func ValidateToken(signedToken string) (*SignedDetails, error) {
 	token, err := jwt.ParseWithClaims(
 		signedToken,
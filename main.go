@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/database"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository/mongodb"
 	"github.com/MayankSaxena03/Restaurant-Management-System/routes"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -16,16 +22,32 @@ func main() {
 		port = "8080"
 	}
 
+	repositories := mongodb.NewRepositories(database.DBInstance())
+	controllers.SetRepositories(repositories)
+	middleware.SetRepositories(repositories)
+	if err := controllers.MigrateRoles(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
 	router := mux.NewRouter()
 
 	routes.UserAuthRoutes(router)
 	// router.MiddlewareFunc(middleware.Authentication)
+	routes.CreditNoteRoutes(router)
 	routes.FoodRoutes(router)
+	routes.InventoryRoutes(router)
 	routes.InvoiceRoutes(router)
+	routes.KitchenRoutes(router)
 	routes.MenuRoutes(router)
 	routes.OrderRoutes(router)
 	routes.OrderItemRoutes(router)
+	routes.PromotionRoutes(router)
+	routes.PurchaseOrderRoutes(router)
+	routes.ReportRoutes(router)
+	routes.ReservationRoutes(router)
+	routes.SupplierRoutes(router)
 	routes.TableRoutes(router)
+	routes.TaxRoutes(router)
 	routes.UserRoutes(router)
 
 	fmt.Println("Server is running on port " + port)
@@ -5,8 +5,16 @@ import (
 	"net/http"
 
 	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
 )
 
+var users repository.UserRepository
+
+// SetRepositories sets where Authentication looks up revoked tokens
+func SetRepositories(repositories *repository.Repositories) {
+	users = repositories.Users
+}
+
 // Middleware for mux router
 func Authentication(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -20,7 +28,21 @@ func Authentication(next http.Handler) http.Handler {
 		}
 		// Validate the token
 		claims, err := helpers.ValidateToken(token)
-		if err != nil {
+		if err != nil || claims.TokenType == helpers.TokenTypeRefresh {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode("Unauthorized")
+			return
+		}
+		// Tokens issued before a logout are no longer accepted
+		user, err := users.Get(r.Context(), claims.UserID)
+		if err != nil && err != repository.ErrNotFound {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode("Something went wrong")
+			return
+		}
+		if err == repository.ErrNotFound || user.TokenVersion != claims.TokenVersion {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			json.NewEncoder(w).Encode("Unauthorized")
@@ -30,6 +52,7 @@ func Authentication(next http.Handler) http.Handler {
 		r.Header.Set("userId", claims.UserID.Hex())
 		r.Header.Set("email", claims.Email)
 		r.Header.Set("username", claims.Username)
+		r.Header.Set("role", claims.Role)
 		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+)
+
+// Authorize only lets the request through if the role set by Authentication
+// has the given permission. It must run after Authentication.
+func Authorize(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !helpers.HasPermission(r.Header.Get("role"), permission) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode("Forbidden")
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CreditNoteStatusPending  = "pending"
+	CreditNoteStatusApproved = "approved"
+	CreditNoteStatusRejected = "rejected"
+)
+
+const (
+	RefundReasonWrongItem  = "wrong-item"
+	RefundReasonQuality    = "quality"
+	RefundReasonOvercharge = "overcharge"
+	RefundReasonNotServed  = "not-served"
+	RefundReasonOther      = "other"
+)
+
+// CreditNote takes back all or part of an issued invoice. It is numbered in
+// its own sequence and only counts against the invoice once a manager
+// approves it. RefundMethod is how money already paid was handed back; a
+// credit note without one lowers what is still owed on the invoice.
+type CreditNote struct {
+	ID                 primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Number             string             `json:"number,omitempty" bson:"number,omitempty"`
+	InvoiceID          primitive.ObjectID `json:"invoiceID,omitempty" bson:"invoiceID,omitempty" validate:"required"`
+	InvoiceNumber      string             `json:"invoiceNumber,omitempty" bson:"invoiceNumber,omitempty"`
+	Reason             string             `json:"reason,omitempty" bson:"reason,omitempty" validate:"required,oneof=wrong-item quality overcharge not-served other"`
+	Comment            string             `json:"comment,omitempty" bson:"comment,omitempty" validate:"max=500"`
+	Lines              []CreditNoteLine   `json:"lines,omitempty" bson:"lines,omitempty" validate:"dive"`
+	SubTotal           float64            `json:"subTotal,omitempty" bson:"subTotal,omitempty"`
+	Taxes              []InvoiceTax       `json:"taxes,omitempty" bson:"taxes,omitempty"`
+	ServiceCharge      float64            `json:"serviceCharge,omitempty" bson:"serviceCharge,omitempty"`
+	RoundingAdjustment float64            `json:"roundingAdjustment,omitempty" bson:"roundingAdjustment,omitempty"`
+	Total              float64            `json:"total,omitempty" bson:"total,omitempty"`
+	RefundMethod       string             `json:"refundMethod,omitempty" bson:"refundMethod,omitempty" validate:"omitempty,oneof=cash card upi voucher"`
+	Status             string             `json:"status,omitempty" bson:"status,omitempty"`
+	RequestedBy        primitive.ObjectID `json:"requestedBy,omitempty" bson:"requestedBy,omitempty"`
+	ReviewedBy         primitive.ObjectID `json:"reviewedBy,omitempty" bson:"reviewedBy,omitempty"`
+	ReviewedOn         time.Time          `json:"reviewedOn,omitempty" bson:"reviewedOn,omitempty"`
+	CreatedOn          time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn          time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// CreditNoteLine takes back Quantity of an invoice line. Only the order item
+// and quantity come from the request, the amounts are worked out from the
+// invoice.
+type CreditNoteLine struct {
+	OrderItemID primitive.ObjectID `json:"orderItemID,omitempty" bson:"orderItemID,omitempty" validate:"required"`
+	FoodName    string             `json:"foodName,omitempty" bson:"foodName,omitempty"`
+	UnitPrice   float64            `json:"unitPrice,omitempty" bson:"unitPrice,omitempty"`
+	Quantity    int                `json:"quantity,omitempty" bson:"quantity,omitempty" validate:"required,gt=0"`
+	Amount      float64            `json:"amount,omitempty" bson:"amount,omitempty"`
+	NetAmount   float64            `json:"netAmount,omitempty" bson:"netAmount,omitempty"`
+	Taxes       []InvoiceTax       `json:"taxes,omitempty" bson:"taxes,omitempty"`
+}
@@ -6,12 +6,38 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Food is a dish on a menu. A food that is SoldOut (86'd) cannot be ordered.
+// When RemainingPortions is set it counts down as the food is ordered and
+// the food cannot be ordered once it reaches 0. ModifierGroups are the
+// choices offered with the food, like its size or extra toppings.
 type Food struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Name      string             `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=50"`
-	Price     float64            `json:"price,omitempty" bson:"price,omitempty" validate:"required"`
-	FoodImage string             `json:"foodImage,omitempty" bson:"foodImage,omitempty" validate:"required"`
-	CreatedOn time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
-	MenuID    primitive.ObjectID `json:"menuID,omitempty" bson:"menuID,omitempty"`
+	ID                primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name              string             `json:"name,omitempty" bson:"name,omitempty" validate:"required,min=3,max=50"`
+	Price             float64            `json:"price,omitempty" bson:"price,omitempty" validate:"required"`
+	FoodImage         string             `json:"foodImage,omitempty" bson:"foodImage,omitempty" validate:"required"`
+	TaxCategory       string             `json:"taxCategory,omitempty" bson:"taxCategory,omitempty"`
+	Station           string             `json:"station,omitempty" bson:"station,omitempty"`
+	SoldOut           bool               `json:"soldOut,omitempty" bson:"soldOut,omitempty"`
+	RemainingPortions *int               `json:"remainingPortions,omitempty" bson:"remainingPortions,omitempty" validate:"omitempty,min=0"`
+	ModifierGroups    []ModifierGroup    `json:"modifierGroups,omitempty" bson:"modifierGroups,omitempty" validate:"dive"`
+	CreatedOn         time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn         time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	MenuID            primitive.ObjectID `json:"menuID,omitempty" bson:"menuID,omitempty"`
+}
+
+// ModifierGroup is a choice offered with a food. At least Min and at most
+// Max of its options are picked for every order item, a Max of 0 allows any
+// number of them.
+type ModifierGroup struct {
+	Name    string           `json:"name,omitempty" bson:"name,omitempty" validate:"required,max=50"`
+	Min     int              `json:"min,omitempty" bson:"min,omitempty" validate:"gte=0"`
+	Max     int              `json:"max,omitempty" bson:"max,omitempty" validate:"gte=0"`
+	Options []ModifierOption `json:"options,omitempty" bson:"options,omitempty" validate:"required,min=1,dive"`
+}
+
+// ModifierOption is one option of a modifier group and what it adds to the
+// price of the food, negative when it makes the food cheaper
+type ModifierOption struct {
+	Name  string  `json:"name,omitempty" bson:"name,omitempty" validate:"required,max=50"`
+	Price float64 `json:"price,omitempty" bson:"price,omitempty"`
 }
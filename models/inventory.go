@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StockMovementSale       = "sale"
+	StockMovementVoid       = "void"
+	StockMovementAdjustment = "adjustment"
+	StockMovementPurchase   = "purchase"
+)
+
+// Ingredient is something the kitchen keeps in stock, counted in Unit, e.g.
+// g, ml or pcs. It is low on stock once Stock falls to LowStockThreshold.
+// Stock only changes through stock movements and goes below zero when more
+// is sold than was counted in. CostPrice is what one unit cost when it was
+// last received, from SupplierID.
+type Ingredient struct {
+	ID                primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name              string             `json:"name,omitempty" bson:"name,omitempty" validate:"required,max=100"`
+	Unit              string             `json:"unit,omitempty" bson:"unit,omitempty" validate:"required,max=20"`
+	Stock             float64            `json:"stock" bson:"stock" validate:"gte=0"`
+	LowStockThreshold float64            `json:"lowStockThreshold" bson:"lowStockThreshold" validate:"gte=0"`
+	CostPrice         float64            `json:"costPrice,omitempty" bson:"costPrice,omitempty" validate:"gte=0"`
+	SupplierID        primitive.ObjectID `json:"supplierID,omitempty" bson:"supplierID,omitempty"`
+	CreatedOn         time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn         time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// Recipe is what one portion of a food uses up. A food has at most one
+// recipe; foods without one use up nothing.
+type Recipe struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	FoodID      primitive.ObjectID `json:"foodID,omitempty" bson:"foodID,omitempty" validate:"required"`
+	Ingredients []RecipeIngredient `json:"ingredients,omitempty" bson:"ingredients,omitempty" validate:"required,min=1,dive"`
+	CreatedOn   time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn   time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// RecipeIngredient is how much of an ingredient, in its unit, a portion uses
+type RecipeIngredient struct {
+	IngredientID primitive.ObjectID `json:"ingredientID,omitempty" bson:"ingredientID,omitempty" validate:"required"`
+	Quantity     float64            `json:"quantity,omitempty" bson:"quantity,omitempty" validate:"required,gt=0"`
+}
+
+// StockMovement is one change to the stock of an ingredient; a negative
+// Quantity takes stock out. Sales are recorded against the order item that
+// used the stock, so voiding the order can put it back; changing the item
+// records the difference as another sale. Purchases are recorded against
+// the purchase order they were received on, at their UnitCost.
+type StockMovement struct {
+	ID              primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	IngredientID    primitive.ObjectID `json:"ingredientID,omitempty" bson:"ingredientID,omitempty"`
+	Quantity        float64            `json:"quantity,omitempty" bson:"quantity,omitempty"`
+	Reason          string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	OrderID         primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty"`
+	OrderItemID     primitive.ObjectID `json:"orderItemID,omitempty" bson:"orderItemID,omitempty"`
+	PurchaseOrderID primitive.ObjectID `json:"purchaseOrderID,omitempty" bson:"purchaseOrderID,omitempty"`
+	UnitCost        float64            `json:"unitCost,omitempty" bson:"unitCost,omitempty"`
+	Note            string             `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedBy       primitive.ObjectID `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedOn       time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+}
@@ -6,12 +6,78 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	PaymentStatusUnpaid        = "unpaid"
+	PaymentStatusPartiallyPaid = "partially-paid"
+	PaymentStatusPaid          = "paid"
+	PaymentStatusOverpaid      = "overpaid"
+)
+
+// Invoice bills an order, or one part of it when the bill is split. Number is
+// the sequential number printed on the tax receipt. The parts
+// of a split bill share a SplitGroupID and RoundingAdjustment makes them add
+// up to the order total to the cent. PaymentMethod, PaymentStatus, AmountPaid
+// and Balance follow the payments recorded against the invoice; Credited and
+// Refunded sum its approved credit notes.
 type Invoice struct {
-	ID             primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	OrderID        primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty" validate:"required"`
-	PaymentMethod  string             `json:"paymentMethod,omitempty" bson:"paymentMethod,omitempty" validate:"required"`
-	PaymentStatus  string             `json:"paymentStatus,omitempty" bson:"paymentStatus,omitempty" validate:"required"`
-	PaymentDueDate time.Time          `json:"paymentDueDate,omitempty" bson:"paymentDueDate,omitempty"`
-	CreatedOn      time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn      time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	ID                 primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Number             string             `json:"number,omitempty" bson:"number,omitempty"`
+	OrderID            primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty" validate:"required"`
+	TableNumber        int                `json:"tableNumber,omitempty" bson:"tableNumber,omitempty"`
+	Lines              []InvoiceLine      `json:"lines,omitempty" bson:"lines,omitempty"`
+	Discount           float64            `json:"discount,omitempty" bson:"discount,omitempty"`
+	Promotions         []InvoicePromotion `json:"promotions,omitempty" bson:"promotions,omitempty"`
+	SubTotal           float64            `json:"subTotal,omitempty" bson:"subTotal,omitempty"`
+	Taxes              []InvoiceTax       `json:"taxes,omitempty" bson:"taxes,omitempty"`
+	ServiceCharge      float64            `json:"serviceCharge,omitempty" bson:"serviceCharge,omitempty"`
+	RoundingAdjustment float64            `json:"roundingAdjustment,omitempty" bson:"roundingAdjustment,omitempty"`
+	GrandTotal         float64            `json:"grandTotal,omitempty" bson:"grandTotal,omitempty"`
+	SplitGroupID       primitive.ObjectID `json:"splitGroupID,omitempty" bson:"splitGroupID,omitempty"`
+	SplitPart          int                `json:"splitPart,omitempty" bson:"splitPart,omitempty"`
+	SplitParts         int                `json:"splitParts,omitempty" bson:"splitParts,omitempty"`
+	PaymentMethod      string             `json:"paymentMethod,omitempty" bson:"paymentMethod,omitempty"`
+	PaymentStatus      string             `json:"paymentStatus,omitempty" bson:"paymentStatus,omitempty"`
+	AmountPaid         float64            `json:"amountPaid,omitempty" bson:"amountPaid,omitempty"`
+	Credited           float64            `json:"credited,omitempty" bson:"credited,omitempty"`
+	Refunded           float64            `json:"refunded,omitempty" bson:"refunded,omitempty"`
+	Balance            float64            `json:"balance,omitempty" bson:"balance,omitempty"`
+	PaymentDueDate     time.Time          `json:"paymentDueDate,omitempty" bson:"paymentDueDate,omitempty"`
+	CreatedOn          time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn          time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// InvoiceLine is a copy of an order item taken when the invoice is created,
+// so later changes to the food do not change the invoice. Discount is what
+// promotions took off Amount and tax is charged on the rest. When a bill is
+// split by amount, Share is the fraction of the line billed on this invoice.
+type InvoiceLine struct {
+	OrderItemID primitive.ObjectID  `json:"orderItemID,omitempty" bson:"orderItemID,omitempty"`
+	FoodID      primitive.ObjectID  `json:"foodID,omitempty" bson:"foodID,omitempty"`
+	FoodName    string              `json:"foodName,omitempty" bson:"foodName,omitempty"`
+	Modifiers   []OrderItemModifier `json:"modifiers,omitempty" bson:"modifiers,omitempty"`
+	UnitPrice   float64             `json:"unitPrice,omitempty" bson:"unitPrice,omitempty"`
+	Quantity    int                 `json:"quantity,omitempty" bson:"quantity,omitempty"`
+	Amount      float64             `json:"amount,omitempty" bson:"amount,omitempty"`
+	Discount    float64             `json:"discount,omitempty" bson:"discount,omitempty"`
+	TaxCategory string              `json:"taxCategory,omitempty" bson:"taxCategory,omitempty"`
+	Inclusive   bool                `json:"inclusive,omitempty" bson:"inclusive,omitempty"`
+	NetAmount   float64             `json:"netAmount,omitempty" bson:"netAmount,omitempty"`
+	Share       float64             `json:"share,omitempty" bson:"share,omitempty"`
+	Taxes       []InvoiceTax        `json:"taxes,omitempty" bson:"taxes,omitempty"`
+}
+
+type InvoiceTax struct {
+	Name   string  `json:"name,omitempty" bson:"name,omitempty"`
+	Rate   float64 `json:"rate,omitempty" bson:"rate,omitempty"`
+	Amount float64 `json:"amount,omitempty" bson:"amount,omitempty"`
+}
+
+// InvoicePromotion is a promotion that took Amount off the invoice, with a
+// line explaining what it did
+type InvoicePromotion struct {
+	PromotionID primitive.ObjectID `json:"promotionID,omitempty" bson:"promotionID,omitempty"`
+	Name        string             `json:"name,omitempty" bson:"name,omitempty"`
+	CouponCode  string             `json:"couponCode,omitempty" bson:"couponCode,omitempty"`
+	Amount      float64            `json:"amount,omitempty" bson:"amount,omitempty"`
+	Explanation string             `json:"explanation,omitempty" bson:"explanation,omitempty"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TicketStatusQueued     = "queued"
+	TicketStatusInProgress = "in-progress"
+	TicketStatusReady      = "ready"
+	TicketStatusCancelled  = "cancelled"
+)
+
+// KitchenStationDefault cooks foods that do not name a station
+const KitchenStationDefault = "main"
+
+// KitchenTicket is one order item on the board of the station that cooks it.
+// OrderNotes are not stored, they are the order's notes at the time the
+// ticket is read.
+type KitchenTicket struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	OrderID     primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty"`
+	OrderItemID primitive.ObjectID `json:"orderItemID,omitempty" bson:"orderItemID,omitempty"`
+	TableNumber int                `json:"tableNumber,omitempty" bson:"tableNumber,omitempty"`
+	FoodID      primitive.ObjectID `json:"foodID,omitempty" bson:"foodID,omitempty"`
+	FoodName    string             `json:"foodName,omitempty" bson:"foodName,omitempty"`
+	Quantity    int                `json:"quantity,omitempty" bson:"quantity,omitempty"`
+	Modifiers   []string           `json:"modifiers,omitempty" bson:"modifiers,omitempty"`
+	Station     string             `json:"station,omitempty" bson:"station,omitempty"`
+	Status      string             `json:"status,omitempty" bson:"status,omitempty"`
+	Note        string             `json:"note,omitempty" bson:"note,omitempty"`
+	OrderNotes  []string           `json:"orderNotes,omitempty" bson:"-"`
+	StartedOn   time.Time          `json:"startedOn,omitempty" bson:"startedOn,omitempty"`
+	ReadyOn     time.Time          `json:"readyOn,omitempty" bson:"readyOn,omitempty"`
+	CreatedOn   time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn   time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
@@ -6,12 +6,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Menu groups foods that are served together. A menu is served between its
+// StartDate and EndDate, when they are set, and only in its ServingHours
+// every day, e.g. breakfast from 07:00 to 11:00. A menu without serving
+// hours is served all day.
 type Menu struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Name      string             `json:"name,omitempty" bson:"name,omitempty" validate:"required"`
-	Category  string             `json:"category,omitempty" bson:"category,omitempty" validate:"required"`
-	StartDate time.Time          `json:"startDate,omitempty" bson:"startDate,omitempty"`
-	EndDate   time.Time          `json:"endDate,omitempty" bson:"endDate,omitempty"`
-	CreatedOn time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	ID           primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name         string             `json:"name,omitempty" bson:"name,omitempty" validate:"required"`
+	Category     string             `json:"category,omitempty" bson:"category,omitempty" validate:"required"`
+	StartDate    time.Time          `json:"startDate,omitempty" bson:"startDate,omitempty"`
+	EndDate      time.Time          `json:"endDate,omitempty" bson:"endDate,omitempty"`
+	ServingHours []DailyWindow      `json:"servingHours,omitempty" bson:"servingHours,omitempty" validate:"dive"`
+	CreatedOn    time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn    time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
 }
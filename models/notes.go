@@ -6,10 +6,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Note is a remark on a whole order, e.g. "birthday, bring candle". Remarks
+// about a single dish go on the order item instead.
 type Note struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Text      string             `json:"text,omitempty" bson:"text,omitempty"`
-	OrderID   primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty"`
-	CreatedOn time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Text       string             `json:"text,omitempty" bson:"text,omitempty" validate:"required,max=500"`
+	OrderID    primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty"`
+	AuthorID   primitive.ObjectID `json:"authorID,omitempty" bson:"authorID,omitempty"`
+	AuthorName string             `json:"authorName,omitempty" bson:"authorName,omitempty"`
+	CreatedOn  time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn  time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
 }
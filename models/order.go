@@ -6,9 +6,34 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	OrderStatusPlaced    = "placed"
+	OrderStatusInKitchen = "in-kitchen"
+	OrderStatusReady     = "ready"
+	OrderStatusServed    = "served"
+	OrderStatusClosed    = "closed"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusVoided    = "voided"
+)
+
+// Order is what a table orders during one visit. Guests is how many people
+// it is for, counted as the covers of the day the order is billed. Billed is
+// set once an invoice, or the parts of a split bill, is issued for it.
 type Order struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	TableID   primitive.ObjectID `json:"tableID,omitempty" bson:"tableID,omitempty" validate:"required"`
-	CreatedOn time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	ID            primitive.ObjectID  `json:"_id,omitempty" bson:"_id,omitempty"`
+	TableID       primitive.ObjectID  `json:"tableID,omitempty" bson:"tableID,omitempty" validate:"required"`
+	Guests        int                 `json:"guests,omitempty" bson:"guests,omitempty" validate:"gte=0"`
+	Status        string              `json:"status,omitempty" bson:"status,omitempty"`
+	StatusHistory []OrderStatusChange `json:"statusHistory,omitempty" bson:"statusHistory,omitempty"`
+	CouponCodes   []string            `json:"couponCodes,omitempty" bson:"couponCodes,omitempty"`
+	Billed        bool                `json:"billed,omitempty" bson:"billed,omitempty"`
+	CreatedOn     time.Time           `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn     time.Time           `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+type OrderStatusChange struct {
+	From      string             `json:"from,omitempty" bson:"from,omitempty"`
+	To        string             `json:"to,omitempty" bson:"to,omitempty"`
+	ChangedBy primitive.ObjectID `json:"changedBy,omitempty" bson:"changedBy,omitempty"`
+	ChangedOn time.Time          `json:"changedOn,omitempty" bson:"changedOn,omitempty"`
 }
@@ -6,12 +6,55 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// OrderItem is a food ordered for an order. UnitPrice is the food's price
+// when it was ordered and each modifier carries its own price; both are set
+// by the server, not the client.
 type OrderItem struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Quantity  int                `json:"quantity,omitempty" bson:"quantity,omitempty" validate:"required"`
-	UnitPrice float64            `json:"unitPrice,omitempty" bson:"unitPrice,omitempty" validate:"required"`
-	FoodID    primitive.ObjectID `json:"foodID,omitempty" bson:"foodID,omitempty" validate:"required"`
-	OrderID   primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty" validate:"required"`
-	CreatedOn time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	ID        primitive.ObjectID  `json:"_id,omitempty" bson:"_id,omitempty"`
+	Quantity  int                 `json:"quantity,omitempty" bson:"quantity,omitempty" validate:"required,min=1"`
+	UnitPrice float64             `json:"unitPrice,omitempty" bson:"unitPrice,omitempty"`
+	FoodID    primitive.ObjectID  `json:"foodID,omitempty" bson:"foodID,omitempty" validate:"required"`
+	OrderID   primitive.ObjectID  `json:"orderID,omitempty" bson:"orderID,omitempty" validate:"required"`
+	Modifiers []OrderItemModifier `json:"modifiers,omitempty" bson:"modifiers,omitempty" validate:"dive"`
+	Note      string              `json:"note,omitempty" bson:"note,omitempty" validate:"max=200"`
+	CreatedOn time.Time           `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn time.Time           `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// OrderItemModifier is a modifier option picked for an order item, with
+// what it added to the unit price when the item was ordered
+type OrderItemModifier struct {
+	Group  string  `json:"group,omitempty" bson:"group,omitempty" validate:"required"`
+	Option string  `json:"option,omitempty" bson:"option,omitempty" validate:"required"`
+	Price  float64 `json:"price,omitempty" bson:"price,omitempty"`
+}
+
+// OrderItemsSummary is an order's items joined with their food and table,
+// along with the amount due at the prices they were ordered at.
+type OrderItemsSummary struct {
+	ID          OrderItemsSummaryKey `json:"_id" bson:"_id"`
+	PaymentDue  float64              `json:"paymentDue" bson:"paymentDue"`
+	TotalCount  int                  `json:"totalCount" bson:"totalCount"`
+	TableNumber int                  `json:"tableNumber" bson:"tableNumber"`
+	OrderItems  []OrderItemDetail    `json:"orderItems" bson:"orderItems"`
+	Notes       []Note               `json:"notes" bson:"notes,omitempty"`
+}
+
+type OrderItemsSummaryKey struct {
+	OrderID     primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty"`
+	TableID     primitive.ObjectID `json:"tableID,omitempty" bson:"tableID,omitempty"`
+	TableNumber int                `json:"tableNumber,omitempty" bson:"tableNumber,omitempty"`
+}
+
+type OrderItemDetail struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	FoodID      primitive.ObjectID `json:"foodID,omitempty" bson:"foodID,omitempty"`
+	FoodName    string             `json:"foodName,omitempty" bson:"foodName,omitempty"`
+	FoodImage   string             `json:"foodImage,omitempty" bson:"foodImage,omitempty"`
+	TableNumber int                `json:"tableNumber,omitempty" bson:"tableNumber,omitempty"`
+	TableID     primitive.ObjectID `json:"tableID,omitempty" bson:"tableID,omitempty"`
+	Price       float64            `json:"price,omitempty" bson:"price,omitempty"`
+	OrderID     primitive.ObjectID `json:"orderID,omitempty" bson:"orderID,omitempty"`
+	Quantity    int                `json:"quantity,omitempty" bson:"quantity,omitempty"`
+	Note        string             `json:"note,omitempty" bson:"note,omitempty"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PaymentMethodCash    = "cash"
+	PaymentMethodCard    = "card"
+	PaymentMethodUPI     = "upi"
+	PaymentMethodVoucher = "voucher"
+)
+
+// Payment is one tender received against an invoice. Amount is what the
+// guest handed over; cash above the balance is handed back as ChangeGiven, so
+// only Amount minus ChangeGiven counts towards the invoice. Reference holds
+// the card slip, UPI transaction or voucher code.
+type Payment struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	InvoiceID   primitive.ObjectID `json:"invoiceID,omitempty" bson:"invoiceID,omitempty"`
+	Method      string             `json:"method,omitempty" bson:"method,omitempty" validate:"required,oneof=cash card upi voucher"`
+	Amount      float64            `json:"amount,omitempty" bson:"amount,omitempty" validate:"required,gt=0"`
+	ChangeGiven float64            `json:"changeGiven,omitempty" bson:"changeGiven,omitempty"`
+	Reference   string             `json:"reference,omitempty" bson:"reference,omitempty" validate:"max=100"`
+	ReceivedBy  primitive.ObjectID `json:"receivedBy,omitempty" bson:"receivedBy,omitempty"`
+	CreatedOn   time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFlat       = "flat"
+	PromotionTypeBuyXGetY   = "buy-x-get-y"
+	PromotionTypeCombo      = "combo"
+)
+
+// Promotion takes money off the items of an order. Percentage and flat
+// promotions take Value percent or Value off the foods in FoodIDs, or off the
+// whole order when FoodIDs is empty. Buy-x-get-y gives FreeQuantity of every
+// BuyQuantity + FreeQuantity units of FoodIDs away, cheapest first, and a
+// combo sells one of each of FoodIDs together for Value. HappyHours limits a
+// promotion to items ordered in those daily windows. Promotions with a
+// CouponCode only apply to orders the code was entered on, at most UsageLimit
+// times when a limit is set.
+type Promotion struct {
+	ID             primitive.ObjectID   `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name           string               `json:"name,omitempty" bson:"name,omitempty" validate:"required,max=100"`
+	Type           string               `json:"type,omitempty" bson:"type,omitempty" validate:"required,oneof=percentage flat buy-x-get-y combo"`
+	Value          float64              `json:"value,omitempty" bson:"value,omitempty" validate:"gte=0"`
+	FoodIDs        []primitive.ObjectID `json:"foodIDs,omitempty" bson:"foodIDs,omitempty"`
+	BuyQuantity    int                  `json:"buyQuantity,omitempty" bson:"buyQuantity,omitempty" validate:"gte=0"`
+	FreeQuantity   int                  `json:"freeQuantity,omitempty" bson:"freeQuantity,omitempty" validate:"gte=0"`
+	MinOrderAmount float64              `json:"minOrderAmount,omitempty" bson:"minOrderAmount,omitempty" validate:"gte=0"`
+	HappyHours     []DailyWindow        `json:"happyHours,omitempty" bson:"happyHours,omitempty" validate:"dive"`
+	CouponCode     string               `json:"couponCode,omitempty" bson:"couponCode,omitempty" validate:"omitempty,alphanum,max=20"`
+	UsageLimit     int                  `json:"usageLimit,omitempty" bson:"usageLimit,omitempty" validate:"gte=0"`
+	UsedCount      int                  `json:"usedCount,omitempty" bson:"usedCount,omitempty"`
+	StartDate      time.Time            `json:"startDate,omitempty" bson:"startDate,omitempty"`
+	EndDate        time.Time            `json:"endDate,omitempty" bson:"endDate,omitempty"`
+	CreatedOn      time.Time            `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn      time.Time            `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// DailyWindow is the part of every day from From to To, both "15:04" times.
+// A window that ends before it starts runs past midnight.
+type DailyWindow struct {
+	From string `json:"from,omitempty" bson:"from,omitempty" validate:"required"`
+	To   string `json:"to,omitempty" bson:"to,omitempty" validate:"required"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PurchaseOrderStatusOrdered           = "ordered"
+	PurchaseOrderStatusPartiallyReceived = "partially-received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+// PurchaseOrder is ingredients ordered from a supplier. Goods can arrive in
+// several receipts; each one adds to the Received quantity of its lines and
+// to the stock of their ingredients. The order is received once every line
+// is, and can be cancelled while it is not.
+type PurchaseOrder struct {
+	ID         primitive.ObjectID  `json:"_id,omitempty" bson:"_id,omitempty"`
+	SupplierID primitive.ObjectID  `json:"supplierID,omitempty" bson:"supplierID,omitempty" validate:"required"`
+	Status     string              `json:"status,omitempty" bson:"status,omitempty"`
+	Lines      []PurchaseOrderLine `json:"lines,omitempty" bson:"lines,omitempty" validate:"required,min=1,dive"`
+	Total      float64             `json:"total,omitempty" bson:"total,omitempty"`
+	ExpectedOn time.Time           `json:"expectedOn,omitempty" bson:"expectedOn,omitempty"`
+	Receipts   []GoodsReceipt      `json:"receipts,omitempty" bson:"receipts,omitempty"`
+	CreatedBy  primitive.ObjectID  `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedOn  time.Time           `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn  time.Time           `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+// PurchaseOrderLine is a quantity of an ingredient, in its unit, ordered at
+// UnitCost a unit. The ingredient's name and unit are copied when ordering.
+type PurchaseOrderLine struct {
+	IngredientID   primitive.ObjectID `json:"ingredientID,omitempty" bson:"ingredientID,omitempty" validate:"required"`
+	IngredientName string             `json:"ingredientName,omitempty" bson:"ingredientName,omitempty"`
+	Unit           string             `json:"unit,omitempty" bson:"unit,omitempty"`
+	Quantity       float64            `json:"quantity,omitempty" bson:"quantity,omitempty" validate:"required,gt=0"`
+	UnitCost       float64            `json:"unitCost,omitempty" bson:"unitCost,omitempty" validate:"gte=0"`
+	Amount         float64            `json:"amount,omitempty" bson:"amount,omitempty"`
+	Received       float64            `json:"received,omitempty" bson:"received,omitempty"`
+}
+
+// GoodsReceipt is a delivery against a purchase order. A line's UnitCost
+// is what the ingredient was invoiced at, when it differs from the order.
+type GoodsReceipt struct {
+	Lines      []GoodsReceiptLine `json:"lines,omitempty" bson:"lines,omitempty" validate:"required,min=1,dive"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty" validate:"max=200"`
+	ReceivedBy primitive.ObjectID `json:"receivedBy,omitempty" bson:"receivedBy,omitempty"`
+	ReceivedOn time.Time          `json:"receivedOn,omitempty" bson:"receivedOn,omitempty"`
+}
+
+type GoodsReceiptLine struct {
+	IngredientID primitive.ObjectID `json:"ingredientID,omitempty" bson:"ingredientID,omitempty" validate:"required"`
+	Quantity     float64            `json:"quantity,omitempty" bson:"quantity,omitempty" validate:"required,gt=0"`
+	UnitCost     float64            `json:"unitCost,omitempty" bson:"unitCost,omitempty" validate:"gte=0"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SalesSummary adds up the business of one Date, a day in the server's time
+// zone running From midnight To the next. Sales are counted from the invoices
+// issued that day:
+//   - GrossSales is what the lines cost before Discounts
+//   - NetSales is the subtotal after discounts, net of tax
+//   - Total is what the guests were billed
+//
+// Checks is the number of orders billed, however their bills were split, and
+// Covers the guests of those orders. Payments are counted on the day they
+// were received, net of change, and credit notes on the day they were
+// approved; Refunds is the part of them handed back to the guests. Closed
+// summaries are those of Z-reports.
+type SalesSummary struct {
+	Date            string        `json:"date" bson:"date"`
+	Closed          bool          `json:"closed" bson:"closed"`
+	From            time.Time     `json:"from" bson:"from"`
+	To              time.Time     `json:"to" bson:"to"`
+	Invoices        int           `json:"invoices" bson:"invoices"`
+	Checks          int           `json:"checks" bson:"checks"`
+	Covers          int           `json:"covers" bson:"covers"`
+	GrossSales      float64       `json:"grossSales" bson:"grossSales"`
+	Discounts       float64       `json:"discounts" bson:"discounts"`
+	NetSales        float64       `json:"netSales" bson:"netSales"`
+	Taxes           []InvoiceTax  `json:"taxes" bson:"taxes"`
+	TaxTotal        float64       `json:"taxTotal" bson:"taxTotal"`
+	ServiceCharge   float64       `json:"serviceCharge" bson:"serviceCharge"`
+	Rounding        float64       `json:"rounding" bson:"rounding"`
+	Total           float64       `json:"total" bson:"total"`
+	AverageCheck    float64       `json:"averageCheck" bson:"averageCheck"`
+	AveragePerCover float64       `json:"averagePerCover" bson:"averagePerCover"`
+	Payments        []TenderTotal `json:"payments" bson:"payments"`
+	Collected       float64       `json:"collected" bson:"collected"`
+	CreditNotes     int           `json:"creditNotes" bson:"creditNotes"`
+	Credited        float64       `json:"credited" bson:"credited"`
+	Refunds         []TenderTotal `json:"refunds" bson:"refunds"`
+	Refunded        float64       `json:"refunded" bson:"refunded"`
+	NetCollected    float64       `json:"netCollected" bson:"netCollected"`
+}
+
+// FoodSales is how many of a food were sold and for how much
+type FoodSales struct {
+	FoodID   primitive.ObjectID `json:"foodID" bson:"_id"`
+	Quantity int                `json:"quantity" bson:"quantity"`
+	Revenue  float64            `json:"revenue" bson:"revenue"`
+}
+
+// CategorySales is how many items of the foods on menus of a Category were
+// sold, for how much and on how many Orders
+type CategorySales struct {
+	Category string  `json:"category" bson:"category"`
+	Quantity int     `json:"quantity" bson:"quantity"`
+	Revenue  float64 `json:"revenue" bson:"revenue"`
+	Orders   int     `json:"orders" bson:"orders"`
+}
+
+// OrderVolume is how many Orders had items ordered, and how many Items, in
+// one Hour of a Weekday, counted from 0 for Sunday
+type OrderVolume struct {
+	Weekday int `json:"weekday" bson:"weekday"`
+	Hour    int `json:"hour" bson:"hour"`
+	Orders  int `json:"orders" bson:"orders"`
+	Items   int `json:"items" bson:"items"`
+}
+
+// TenderTotal is what Count payments or refunds by Method came to
+type TenderTotal struct {
+	Method string  `json:"method" bson:"method"`
+	Count  int     `json:"count" bson:"count"`
+	Amount float64 `json:"amount" bson:"amount"`
+}
+
+// ZReport is the sales summary of a day frozen when the day was closed, so
+// its numbers no longer change. Z-reports are numbered in their own
+// sequence and a day is closed only once.
+type ZReport struct {
+	ID           primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Number       int64              `json:"number,omitempty" bson:"number,omitempty"`
+	SalesSummary `bson:",inline"`
+	ClosedBy     primitive.ObjectID `json:"closedBy,omitempty" bson:"closedBy,omitempty"`
+	ClosedOn     time.Time          `json:"closedOn,omitempty" bson:"closedOn,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReservationStatusBooked    = "booked"
+	ReservationStatusCancelled = "cancelled"
+)
+
+// ReservationDefaultDuration is how many minutes a table is held when a
+// reservation does not say
+const ReservationDefaultDuration = 90
+
+type Reservation struct {
+	ID            primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	TableID       primitive.ObjectID `json:"tableID,omitempty" bson:"tableID,omitempty" validate:"required"`
+	CustomerName  string             `json:"customerName,omitempty" bson:"customerName,omitempty" validate:"required"`
+	CustomerPhone string             `json:"customerPhone,omitempty" bson:"customerPhone,omitempty" validate:"required,numeric"`
+	PartySize     int                `json:"partySize,omitempty" bson:"partySize,omitempty" validate:"required,gt=0"`
+	At            time.Time          `json:"at,omitempty" bson:"at,omitempty" validate:"required"`
+	// Duration is in minutes
+	Duration  int       `json:"duration,omitempty" bson:"duration,omitempty" validate:"gte=0"`
+	EndsAt    time.Time `json:"endsAt,omitempty" bson:"endsAt,omitempty"`
+	Status    string    `json:"status,omitempty" bson:"status,omitempty"`
+	CreatedOn time.Time `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn time.Time `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Supplier is a business the restaurant buys ingredients from
+type Supplier struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name,omitempty" bson:"name,omitempty" validate:"required,max=100"`
+	ContactName string             `json:"contactName,omitempty" bson:"contactName,omitempty" validate:"max=100"`
+	Phone       string             `json:"phone,omitempty" bson:"phone,omitempty" validate:"max=20"`
+	Email       string             `json:"email,omitempty" bson:"email,omitempty" validate:"omitempty,email"`
+	Address     string             `json:"address,omitempty" bson:"address,omitempty" validate:"max=200"`
+	CreatedOn   time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn   time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
@@ -6,10 +6,22 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	TableStatusFree          = "free"
+	TableStatusSeated        = "seated"
+	TableStatusOrdering      = "ordering"
+	TableStatusAwaitingBill  = "awaiting-bill"
+	TableStatusNeedsCleaning = "needs-cleaning"
+)
+
 type Table struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Number    int                `json:"number,omitempty" bson:"number,omitempty" validate:"required"`
-	Guests    int                `json:"guests,omitempty" bson:"guests,omitempty" validate:"required"`
-	CreatedOn time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
-	UpdatedOn time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+	ID     primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Number int                `json:"number,omitempty" bson:"number,omitempty" validate:"required"`
+	Guests int                `json:"guests,omitempty" bson:"guests,omitempty" validate:"required"`
+	// Status is kept up to date by the order and invoice flows. Tables
+	// without one are free.
+	Status          string    `json:"status,omitempty" bson:"status,omitempty"`
+	StatusChangedOn time.Time `json:"statusChangedOn,omitempty" bson:"statusChangedOn,omitempty"`
+	CreatedOn       time.Time `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn       time.Time `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaxCategoryDefault applies to foods that do not name a tax category
+const TaxCategoryDefault = "default"
+
+// TaxRate is the tax charged on foods of one tax category. A rate can be
+// made of several components, e.g. GST 5% is CGST 2.5% plus SGST 2.5%.
+type TaxRate struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name       string             `json:"name,omitempty" bson:"name,omitempty" validate:"required"`
+	Category   string             `json:"category,omitempty" bson:"category,omitempty" validate:"required"`
+	Inclusive  bool               `json:"inclusive" bson:"inclusive"`
+	Components []TaxComponent     `json:"components,omitempty" bson:"components,omitempty" validate:"required,min=1,dive"`
+	CreatedOn  time.Time          `json:"createdOn,omitempty" bson:"createdOn,omitempty"`
+	UpdatedOn  time.Time          `json:"updatedOn,omitempty" bson:"updatedOn,omitempty"`
+}
+
+type TaxComponent struct {
+	Name string  `json:"name,omitempty" bson:"name,omitempty" validate:"required"`
+	Rate float64 `json:"rate,omitempty" bson:"rate,omitempty" validate:"gt=0,lte=100"`
+}
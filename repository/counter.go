@@ -0,0 +1,12 @@
+package repository
+
+import "context"
+
+type CounterRepository interface {
+	// Next atomically increments the named counter and returns its new
+	// value, starting from 1
+	Next(ctx context.Context, name string) (int64, error)
+	// Release gives value back when nothing was stored under it, as long
+	// as no later value has been handed out yet
+	Release(ctx context.Context, name string, value int64) error
+}
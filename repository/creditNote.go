@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreditNoteRepository interface {
+	// List returns credit notes, only those of invoiceID and with the given
+	// status when they are set
+	List(ctx context.Context, invoiceID primitive.ObjectID, status string, skip int64, limit int64) ([]models.CreditNote, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.CreditNote, error)
+	// ListByInvoice returns every credit note of the invoice, oldest first
+	ListByInvoice(ctx context.Context, invoiceID primitive.ObjectID) ([]models.CreditNote, error)
+	// ListReviewed returns the credit notes given status from from until to
+	ListReviewed(ctx context.Context, status string, from time.Time, to time.Time) ([]models.CreditNote, error)
+	Create(ctx context.Context, creditNote *models.CreditNote) error
+	// Review sets the status and reviewer of a pending credit note,
+	// returning ErrConflict if it was already reviewed
+	Review(ctx context.Context, id primitive.ObjectID, creditNote models.CreditNote) (models.CreditNote, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FoodRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Food, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Food, error)
+	GetMany(ctx context.Context, ids []primitive.ObjectID) ([]models.Food, error)
+	ListByMenus(ctx context.Context, menuIDs []primitive.ObjectID) ([]models.Food, error)
+	Exists(ctx context.Context, id primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, food *models.Food) error
+	// Update sets the non-empty fields of food
+	Update(ctx context.Context, id primitive.ObjectID, food models.Food) error
+	// SetAvailability 86es or un-86es the food and sets its remaining
+	// portions, nil to stop counting them
+	SetAvailability(ctx context.Context, id primitive.ObjectID, soldOut bool, remainingPortions *int) error
+	// TakePortions takes quantity off the food's remaining portions,
+	// returning ErrConflict if it is sold out or has fewer left
+	TakePortions(ctx context.Context, id primitive.ObjectID, quantity int) error
+	// ReturnPortions gives back portions taken for an order that was not
+	// placed
+	ReturnPortions(ctx context.Context, id primitive.ObjectID, quantity int) error
+}
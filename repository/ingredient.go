@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IngredientRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Ingredient, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Ingredient, error)
+	Create(ctx context.Context, ingredient *models.Ingredient) error
+	// Update sets the name, unit and low stock threshold; the stock only
+	// changes through AdjustStock
+	Update(ctx context.Context, id primitive.ObjectID, ingredient models.Ingredient) error
+	// SetCost records what one unit cost when it was last received and who
+	// it came from
+	SetCost(ctx context.Context, id primitive.ObjectID, costPrice float64, supplierID primitive.ObjectID) error
+	// AdjustStock adds quantity, which may be negative, to the stock
+	AdjustStock(ctx context.Context, id primitive.ObjectID, quantity float64) error
+	// ListLowStock returns the ingredients whose stock is at or below their
+	// low stock threshold
+	ListLowStock(ctx context.Context) ([]models.Ingredient, error)
+}
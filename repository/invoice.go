@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InvoiceRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Invoice, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Invoice, error)
+	GetByNumber(ctx context.Context, number string) (models.Invoice, error)
+	// ExistsByOrder reports whether the order has been billed, in one invoice
+	// or split into several
+	ExistsByOrder(ctx context.Context, orderID primitive.ObjectID) (bool, error)
+	ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.Invoice, error)
+	// ListBySplitGroup returns the parts of a split bill in order
+	ListBySplitGroup(ctx context.Context, splitGroupID primitive.ObjectID) ([]models.Invoice, error)
+	// ListIssued returns the invoices issued from from until to
+	ListIssued(ctx context.Context, from time.Time, to time.Time) ([]models.Invoice, error)
+	Create(ctx context.Context, invoice *models.Invoice) error
+	// CreateNumbered numbers the invoices in turn from the named counter,
+	// making each number from its sequence with number, and stores them in
+	// one step: either all of them are stored or none is and the counter is
+	// left as it was, so the sequence has no gaps. It returns ErrConflict if
+	// an invoice already has one of the numbers.
+	CreateNumbered(ctx context.Context, counter string, number func(sequence int64) string, invoices []models.Invoice) error
+	UpdateDueDate(ctx context.Context, id primitive.ObjectID, dueDate time.Time) error
+	// UpdateSettlement replaces the payment method, status, amounts paid,
+	// credited and refunded and the balance
+	UpdateSettlement(ctx context.Context, id primitive.ObjectID, invoice models.Invoice) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type KitchenTicketRepository interface {
+	// List returns the tickets in the given statuses, oldest first. An empty
+	// station matches every station.
+	List(ctx context.Context, station string, statuses []string) ([]models.KitchenTicket, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.KitchenTicket, error)
+	ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.KitchenTicket, error)
+	CreateMany(ctx context.Context, tickets []models.KitchenTicket) error
+	// UpdateStatus sets the non-empty fields of ticket and returns the result,
+	// or ErrConflict if the ticket's status is not one of from
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from []string, ticket models.KitchenTicket) (models.KitchenTicket, error)
+	// UpdateByOrderItem gives the order item's queued and in-progress tickets
+	// the food, station, quantity, modifiers and note of ticket, empty ones
+	// included, and returns them
+	UpdateByOrderItem(ctx context.Context, orderItemID primitive.ObjectID, ticket models.KitchenTicket) ([]models.KitchenTicket, error)
+	// CancelByOrder cancels the order's unfinished tickets and returns them
+	CancelByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.KitchenTicket, error)
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+type counterRepository struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func (c *counterRepository) Next(ctx context.Context, name string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[name]++
+	return c.values[name], nil
+}
+
+func (c *counterRepository) Release(ctx context.Context, name string, value int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values[name] == value {
+		c.values[name]--
+	}
+	return nil
+}
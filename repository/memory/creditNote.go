@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type creditNoteRepository struct {
+	creditNotes *collection[models.CreditNote]
+}
+
+func (c *creditNoteRepository) List(ctx context.Context, invoiceID primitive.ObjectID, status string, skip int64, limit int64) ([]models.CreditNote, error) {
+	return c.creditNotes.find(func(creditNote models.CreditNote) bool {
+		return (invoiceID.IsZero() || creditNote.InvoiceID == invoiceID) && (status == "" || creditNote.Status == status)
+	}, skip, limit), nil
+}
+
+func (c *creditNoteRepository) Get(ctx context.Context, id primitive.ObjectID) (models.CreditNote, error) {
+	creditNote, ok := c.creditNotes.get(id)
+	if !ok {
+		return creditNote, repository.ErrNotFound
+	}
+	return creditNote, nil
+}
+
+func (c *creditNoteRepository) ListByInvoice(ctx context.Context, invoiceID primitive.ObjectID) ([]models.CreditNote, error) {
+	return c.creditNotes.find(func(creditNote models.CreditNote) bool { return creditNote.InvoiceID == invoiceID }, 0, 0), nil
+}
+
+func (c *creditNoteRepository) ListReviewed(ctx context.Context, status string, from time.Time, to time.Time) ([]models.CreditNote, error) {
+	return c.creditNotes.find(func(creditNote models.CreditNote) bool {
+		return creditNote.Status == status && !creditNote.ReviewedOn.Before(from) && creditNote.ReviewedOn.Before(to)
+	}, 0, 0), nil
+}
+
+func (c *creditNoteRepository) Create(ctx context.Context, creditNote *models.CreditNote) error {
+	if creditNote.ID.IsZero() {
+		creditNote.ID = primitive.NewObjectID()
+	}
+	c.creditNotes.insert(creditNote.ID, *creditNote)
+	return nil
+}
+
+func (c *creditNoteRepository) Review(ctx context.Context, id primitive.ObjectID, creditNote models.CreditNote) (models.CreditNote, error) {
+	if _, ok := c.creditNotes.get(id); !ok {
+		return creditNote, repository.ErrNotFound
+	}
+	updated := c.creditNotes.update(id, func(current *models.CreditNote) bool {
+		if current.Status != models.CreditNoteStatusPending {
+			return false
+		}
+		current.Status = creditNote.Status
+		current.ReviewedBy = creditNote.ReviewedBy
+		current.ReviewedOn = creditNote.ReviewedOn
+		current.UpdatedOn = creditNote.UpdatedOn
+		return true
+	})
+	if !updated {
+		return creditNote, repository.ErrConflict
+	}
+	return c.Get(ctx, id)
+}
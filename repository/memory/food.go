@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type foodRepository struct {
+	foods *collection[models.Food]
+}
+
+func (f *foodRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Food, error) {
+	return f.foods.find(nil, skip, limit), nil
+}
+
+func (f *foodRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Food, error) {
+	food, ok := f.foods.get(id)
+	if !ok {
+		return food, repository.ErrNotFound
+	}
+	return food, nil
+}
+
+func (f *foodRepository) GetMany(ctx context.Context, ids []primitive.ObjectID) ([]models.Food, error) {
+	wanted := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return f.foods.find(func(food models.Food) bool { return wanted[food.ID] }, 0, 0), nil
+}
+
+func (f *foodRepository) ListByMenus(ctx context.Context, menuIDs []primitive.ObjectID) ([]models.Food, error) {
+	wanted := map[primitive.ObjectID]bool{}
+	for _, id := range menuIDs {
+		wanted[id] = true
+	}
+	return f.foods.find(func(food models.Food) bool { return wanted[food.MenuID] }, 0, 0), nil
+}
+
+func (f *foodRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	_, ok := f.foods.get(id)
+	return ok, nil
+}
+
+func (f *foodRepository) Create(ctx context.Context, food *models.Food) error {
+	if food.ID.IsZero() {
+		food.ID = primitive.NewObjectID()
+	}
+	f.foods.insert(food.ID, *food)
+	return nil
+}
+
+func (f *foodRepository) Update(ctx context.Context, id primitive.ObjectID, food models.Food) error {
+	if !f.foods.update(id, func(current *models.Food) bool { set(current, food); return true }) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (f *foodRepository) SetAvailability(ctx context.Context, id primitive.ObjectID, soldOut bool, remainingPortions *int) error {
+	updated := f.foods.update(id, func(current *models.Food) bool {
+		current.SoldOut = soldOut
+		current.RemainingPortions = remainingPortions
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (f *foodRepository) TakePortions(ctx context.Context, id primitive.ObjectID, quantity int) error {
+	if _, ok := f.foods.get(id); !ok {
+		return repository.ErrNotFound
+	}
+	updated := f.foods.update(id, func(current *models.Food) bool {
+		if current.SoldOut || current.RemainingPortions == nil || *current.RemainingPortions < quantity {
+			return false
+		}
+		remaining := *current.RemainingPortions - quantity
+		current.RemainingPortions = &remaining
+		return true
+	})
+	if !updated {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (f *foodRepository) ReturnPortions(ctx context.Context, id primitive.ObjectID, quantity int) error {
+	f.foods.update(id, func(current *models.Food) bool {
+		if current.RemainingPortions == nil {
+			return false
+		}
+		remaining := *current.RemainingPortions + quantity
+		current.RemainingPortions = &remaining
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ingredientRepository struct {
+	ingredients *collection[models.Ingredient]
+}
+
+func (i *ingredientRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Ingredient, error) {
+	return i.ingredients.find(nil, skip, limit), nil
+}
+
+func (i *ingredientRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Ingredient, error) {
+	ingredient, ok := i.ingredients.get(id)
+	if !ok {
+		return ingredient, repository.ErrNotFound
+	}
+	return ingredient, nil
+}
+
+func (i *ingredientRepository) Create(ctx context.Context, ingredient *models.Ingredient) error {
+	if ingredient.ID.IsZero() {
+		ingredient.ID = primitive.NewObjectID()
+	}
+	i.ingredients.insert(ingredient.ID, *ingredient)
+	return nil
+}
+
+func (i *ingredientRepository) Update(ctx context.Context, id primitive.ObjectID, ingredient models.Ingredient) error {
+	updated := i.ingredients.update(id, func(current *models.Ingredient) bool {
+		current.Name = ingredient.Name
+		current.Unit = ingredient.Unit
+		current.LowStockThreshold = ingredient.LowStockThreshold
+		current.UpdatedOn = ingredient.UpdatedOn
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (i *ingredientRepository) SetCost(ctx context.Context, id primitive.ObjectID, costPrice float64, supplierID primitive.ObjectID) error {
+	updated := i.ingredients.update(id, func(current *models.Ingredient) bool {
+		current.CostPrice = costPrice
+		current.SupplierID = supplierID
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (i *ingredientRepository) AdjustStock(ctx context.Context, id primitive.ObjectID, quantity float64) error {
+	if !i.ingredients.update(id, func(current *models.Ingredient) bool { current.Stock += quantity; return true }) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (i *ingredientRepository) ListLowStock(ctx context.Context) ([]models.Ingredient, error) {
+	return i.ingredients.find(func(ingredient models.Ingredient) bool { return ingredient.Stock <= ingredient.LowStockThreshold }, 0, 0), nil
+}
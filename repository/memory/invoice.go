@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type invoiceRepository struct {
+	invoices *collection[models.Invoice]
+	counters *counterRepository
+}
+
+func (i *invoiceRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Invoice, error) {
+	return i.invoices.find(nil, skip, limit), nil
+}
+
+func (i *invoiceRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Invoice, error) {
+	invoice, ok := i.invoices.get(id)
+	if !ok {
+		return invoice, repository.ErrNotFound
+	}
+	return invoice, nil
+}
+
+func (i *invoiceRepository) GetByNumber(ctx context.Context, number string) (models.Invoice, error) {
+	invoice, ok := i.invoices.findOne(func(invoice models.Invoice) bool { return invoice.Number == number })
+	if !ok {
+		return invoice, repository.ErrNotFound
+	}
+	return invoice, nil
+}
+
+func (i *invoiceRepository) ExistsByOrder(ctx context.Context, orderID primitive.ObjectID) (bool, error) {
+	_, ok := i.invoices.findOne(func(invoice models.Invoice) bool { return invoice.OrderID == orderID })
+	return ok, nil
+}
+
+func (i *invoiceRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.Invoice, error) {
+	return i.invoices.find(func(invoice models.Invoice) bool { return invoice.OrderID == orderID }, 0, 0), nil
+}
+
+func (i *invoiceRepository) ListBySplitGroup(ctx context.Context, splitGroupID primitive.ObjectID) ([]models.Invoice, error) {
+	invoices := i.invoices.find(func(invoice models.Invoice) bool { return invoice.SplitGroupID == splitGroupID }, 0, 0)
+	sort.SliceStable(invoices, func(a, b int) bool { return invoices[a].SplitPart < invoices[b].SplitPart })
+	return invoices, nil
+}
+
+func (i *invoiceRepository) ListIssued(ctx context.Context, from time.Time, to time.Time) ([]models.Invoice, error) {
+	return i.invoices.find(func(invoice models.Invoice) bool {
+		return !invoice.CreatedOn.Before(from) && invoice.CreatedOn.Before(to)
+	}, 0, 0), nil
+}
+
+func (i *invoiceRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	if invoice.ID.IsZero() {
+		invoice.ID = primitive.NewObjectID()
+	}
+	i.invoices.insert(invoice.ID, *invoice)
+	return nil
+}
+
+func (i *invoiceRepository) CreateNumbered(ctx context.Context, counter string, number func(sequence int64) string, invoices []models.Invoice) error {
+	// Holding the counter until the invoices are stored makes it one step
+	i.counters.mu.Lock()
+	defer i.counters.mu.Unlock()
+
+	sequence := i.counters.values[counter]
+	for n := range invoices {
+		invoices[n].Number = number(sequence + int64(n) + 1)
+		if _, taken := i.invoices.findOne(func(invoice models.Invoice) bool { return invoice.Number == invoices[n].Number }); taken {
+			return repository.ErrConflict
+		}
+	}
+	for n := range invoices {
+		i.Create(ctx, &invoices[n])
+	}
+	i.counters.values[counter] = sequence + int64(len(invoices))
+	return nil
+}
+
+func (i *invoiceRepository) UpdateDueDate(ctx context.Context, id primitive.ObjectID, dueDate time.Time) error {
+	updated := i.invoices.update(id, func(current *models.Invoice) bool {
+		current.PaymentDueDate = dueDate
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (i *invoiceRepository) UpdateSettlement(ctx context.Context, id primitive.ObjectID, invoice models.Invoice) error {
+	updated := i.invoices.update(id, func(current *models.Invoice) bool {
+		current.PaymentMethod = invoice.PaymentMethod
+		current.PaymentStatus = invoice.PaymentStatus
+		current.AmountPaid = invoice.AmountPaid
+		current.Credited = invoice.Credited
+		current.Refunded = invoice.Refunded
+		current.Balance = invoice.Balance
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
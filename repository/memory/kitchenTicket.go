@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type kitchenTicketRepository struct {
+	tickets *collection[models.KitchenTicket]
+}
+
+func (k *kitchenTicketRepository) List(ctx context.Context, station string, statuses []string) ([]models.KitchenTicket, error) {
+	return k.tickets.find(func(ticket models.KitchenTicket) bool {
+		return (station == "" || ticket.Station == station) && contains(statuses, ticket.Status)
+	}, 0, 0), nil
+}
+
+func (k *kitchenTicketRepository) Get(ctx context.Context, id primitive.ObjectID) (models.KitchenTicket, error) {
+	ticket, ok := k.tickets.get(id)
+	if !ok {
+		return ticket, repository.ErrNotFound
+	}
+	return ticket, nil
+}
+
+func (k *kitchenTicketRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.KitchenTicket, error) {
+	return k.tickets.find(func(ticket models.KitchenTicket) bool { return ticket.OrderID == orderID }, 0, 0), nil
+}
+
+func (k *kitchenTicketRepository) CreateMany(ctx context.Context, tickets []models.KitchenTicket) error {
+	for i := range tickets {
+		if tickets[i].ID.IsZero() {
+			tickets[i].ID = primitive.NewObjectID()
+		}
+		k.tickets.insert(tickets[i].ID, tickets[i])
+	}
+	return nil
+}
+
+func (k *kitchenTicketRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from []string, ticket models.KitchenTicket) (models.KitchenTicket, error) {
+	if _, ok := k.tickets.get(id); !ok {
+		return ticket, repository.ErrNotFound
+	}
+	updated := k.tickets.update(id, func(current *models.KitchenTicket) bool {
+		if !contains(from, current.Status) {
+			return false
+		}
+		set(current, ticket)
+		return true
+	})
+	if !updated {
+		return ticket, repository.ErrConflict
+	}
+	return k.Get(ctx, id)
+}
+
+func (k *kitchenTicketRepository) CancelByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.KitchenTicket, error) {
+	open := []string{models.TicketStatusQueued, models.TicketStatusInProgress}
+	tickets := k.tickets.find(func(ticket models.KitchenTicket) bool {
+		return ticket.OrderID == orderID && contains(open, ticket.Status)
+	}, 0, 0)
+
+	cancelled := []models.KitchenTicket{}
+	for _, ticket := range tickets {
+		k.tickets.update(ticket.ID, func(current *models.KitchenTicket) bool {
+			current.Status = models.TicketStatusCancelled
+			current.UpdatedOn = time.Now()
+			return true
+		})
+		ticket, _ = k.tickets.get(ticket.ID)
+		cancelled = append(cancelled, ticket)
+	}
+	return cancelled, nil
+}
+
+func (k *kitchenTicketRepository) UpdateByOrderItem(ctx context.Context, orderItemID primitive.ObjectID, ticket models.KitchenTicket) ([]models.KitchenTicket, error) {
+	open := []string{models.TicketStatusQueued, models.TicketStatusInProgress}
+	tickets := k.tickets.find(func(current models.KitchenTicket) bool {
+		return current.OrderItemID == orderItemID && contains(open, current.Status)
+	}, 0, 0)
+
+	updated := []models.KitchenTicket{}
+	for _, current := range tickets {
+		k.tickets.update(current.ID, func(current *models.KitchenTicket) bool {
+			current.FoodID = ticket.FoodID
+			current.FoodName = ticket.FoodName
+			current.Station = ticket.Station
+			current.Quantity = ticket.Quantity
+			current.Modifiers = ticket.Modifiers
+			current.Note = ticket.Note
+			current.UpdatedOn = time.Now()
+			return true
+		})
+		current, _ = k.tickets.get(current.ID)
+		updated = append(updated, current)
+	}
+	return updated, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
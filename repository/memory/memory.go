@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NewRepositories returns empty repositories that keep everything in memory.
+// They behave like the MongoDB ones and are meant for tests and local runs.
+func NewRepositories() *repository.Repositories {
+	foods := newCollection[models.Food]()
+	orders := newCollection[models.Order]()
+	tables := newCollection[models.Table]()
+	menus := newCollection[models.Menu]()
+	counters := &counterRepository{values: map[string]int64{}}
+	return &repository.Repositories{
+		Foods:          &foodRepository{foods: foods},
+		Menus:          &menuRepository{menus: menus},
+		Tables:         &tableRepository{tables: tables},
+		Orders:         &orderRepository{orders: orders},
+		OrderItems:     &orderItemRepository{orderItems: newCollection[models.OrderItem](), foods: foods, orders: orders, tables: tables, menus: menus},
+		Invoices:       &invoiceRepository{invoices: newCollection[models.Invoice](), counters: counters},
+		TaxRates:       &taxRateRepository{taxRates: newCollection[models.TaxRate]()},
+		Users:          &userRepository{users: newCollection[models.User]()},
+		KitchenTickets: &kitchenTicketRepository{tickets: newCollection[models.KitchenTicket]()},
+		Reservations:   &reservationRepository{reservations: newCollection[models.Reservation]()},
+		Notes:          &noteRepository{notes: newCollection[models.Note]()},
+		Payments:       &paymentRepository{payments: newCollection[models.Payment]()},
+		CreditNotes:    &creditNoteRepository{creditNotes: newCollection[models.CreditNote]()},
+		Counters:       counters,
+		Promotions:     &promotionRepository{promotions: newCollection[models.Promotion]()},
+		Ingredients:    &ingredientRepository{ingredients: newCollection[models.Ingredient]()},
+		Recipes:        &recipeRepository{recipes: newCollection[models.Recipe]()},
+		StockMovements: &stockMovementRepository{movements: newCollection[models.StockMovement]()},
+		Suppliers:      &supplierRepository{suppliers: newCollection[models.Supplier]()},
+		PurchaseOrders: &purchaseOrderRepository{purchaseOrders: newCollection[models.PurchaseOrder]()},
+		ZReports:       &zReportRepository{zReports: newCollection[models.ZReport]()},
+	}
+}
+
+// collection stores documents in insertion order. Documents are copied
+// through BSON on the way in and out, so callers never share memory with the
+// store and values round-trip exactly like they do through MongoDB.
+type collection[T any] struct {
+	mu        sync.RWMutex
+	ids       []primitive.ObjectID
+	documents map[primitive.ObjectID]T
+}
+
+func newCollection[T any]() *collection[T] {
+	return &collection[T]{documents: map[primitive.ObjectID]T{}}
+}
+
+func (c *collection[T]) insert(id primitive.ObjectID, document T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids = append(c.ids, id)
+	c.documents[id] = clone(document)
+}
+
+func (c *collection[T]) get(id primitive.ObjectID) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	document, ok := c.documents[id]
+	if !ok {
+		return document, false
+	}
+	return clone(document), true
+}
+
+// find returns the documents matching filter, skipping the first skip and
+// returning at most limit of them. A limit of 0 means no limit.
+func (c *collection[T]) find(filter func(T) bool, skip int64, limit int64) []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var documents []T
+	for _, id := range c.ids {
+		document := c.documents[id]
+		if filter != nil && !filter(document) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		documents = append(documents, clone(document))
+		if limit > 0 && int64(len(documents)) == limit {
+			break
+		}
+	}
+	return documents
+}
+
+func (c *collection[T]) findOne(filter func(T) bool) (T, bool) {
+	documents := c.find(filter, 0, 1)
+	if len(documents) == 0 {
+		var document T
+		return document, false
+	}
+	return documents[0], true
+}
+
+// update applies change to the document with the given ID. The change is
+// rejected when it returns false.
+func (c *collection[T]) update(id primitive.ObjectID, change func(*T) bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	document, ok := c.documents[id]
+	if !ok {
+		return false
+	}
+	document = clone(document)
+	if !change(&document) {
+		return false
+	}
+	c.documents[id] = clone(document)
+	return true
+}
+
+func (c *collection[T]) remove(id primitive.ObjectID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.documents[id]; !ok {
+		return false
+	}
+	delete(c.documents, id)
+	for i := range c.ids {
+		if c.ids[i] == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func clone[T any](document T) T {
+	var copied T
+	data, err := bson.Marshal(document)
+	if err != nil {
+		panic(err)
+	}
+	if err = bson.Unmarshal(data, &copied); err != nil {
+		panic(err)
+	}
+	return copied
+}
+
+// set copies the fields of patch that MongoDB's $set would write into document
+func set[T any](document *T, patch T) {
+	current := bson.M{}
+	data, err := bson.Marshal(document)
+	if err != nil {
+		panic(err)
+	}
+	if err = bson.Unmarshal(data, &current); err != nil {
+		panic(err)
+	}
+
+	fields := bson.M{}
+	data, err = bson.Marshal(patch)
+	if err != nil {
+		panic(err)
+	}
+	if err = bson.Unmarshal(data, &fields); err != nil {
+		panic(err)
+	}
+	delete(fields, "_id")
+	for key, value := range fields {
+		current[key] = value
+	}
+
+	data, err = bson.Marshal(current)
+	if err != nil {
+		panic(err)
+	}
+	var updated T
+	if err = bson.Unmarshal(data, &updated); err != nil {
+		panic(err)
+	}
+	*document = updated
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type menuRepository struct {
+	menus *collection[models.Menu]
+}
+
+func (m *menuRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Menu, error) {
+	return m.menus.find(nil, skip, limit), nil
+}
+
+func (m *menuRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Menu, error) {
+	menu, ok := m.menus.get(id)
+	if !ok {
+		return menu, repository.ErrNotFound
+	}
+	return menu, nil
+}
+
+func (m *menuRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	_, ok := m.menus.get(id)
+	return ok, nil
+}
+
+func (m *menuRepository) Create(ctx context.Context, menu *models.Menu) error {
+	if menu.ID.IsZero() {
+		menu.ID = primitive.NewObjectID()
+	}
+	m.menus.insert(menu.ID, *menu)
+	return nil
+}
+
+func (m *menuRepository) Update(ctx context.Context, id primitive.ObjectID, menu models.Menu) error {
+	updated := m.menus.update(id, func(current *models.Menu) bool {
+		current.Name = menu.Name
+		current.Category = menu.Category
+		current.StartDate = menu.StartDate
+		current.EndDate = menu.EndDate
+		current.ServingHours = menu.ServingHours
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
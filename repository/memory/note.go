@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type noteRepository struct {
+	notes *collection[models.Note]
+}
+
+func (n *noteRepository) ListByOrders(ctx context.Context, orderIDs []primitive.ObjectID) ([]models.Note, error) {
+	return n.notes.find(func(note models.Note) bool {
+		for _, orderID := range orderIDs {
+			if note.OrderID == orderID {
+				return true
+			}
+		}
+		return false
+	}, 0, 0), nil
+}
+
+func (n *noteRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Note, error) {
+	note, ok := n.notes.get(id)
+	if !ok {
+		return note, repository.ErrNotFound
+	}
+	return note, nil
+}
+
+func (n *noteRepository) Create(ctx context.Context, note *models.Note) error {
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+	n.notes.insert(note.ID, *note)
+	return nil
+}
+
+func (n *noteRepository) Update(ctx context.Context, id primitive.ObjectID, note models.Note) error {
+	updated := n.notes.update(id, func(current *models.Note) bool {
+		current.Text = note.Text
+		current.UpdatedOn = note.UpdatedOn
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (n *noteRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if !n.notes.remove(id) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type orderRepository struct {
+	orders *collection[models.Order]
+}
+
+func (o *orderRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Order, error) {
+	return o.orders.find(nil, skip, limit), nil
+}
+
+func (o *orderRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Order, error) {
+	order, ok := o.orders.get(id)
+	if !ok {
+		return order, repository.ErrNotFound
+	}
+	return order, nil
+}
+
+func (o *orderRepository) GetMany(ctx context.Context, ids []primitive.ObjectID) ([]models.Order, error) {
+	wanted := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return o.orders.find(func(order models.Order) bool { return wanted[order.ID] }, 0, 0), nil
+}
+
+func (o *orderRepository) ListOpenByTable(ctx context.Context, tableID primitive.ObjectID) ([]models.Order, error) {
+	return o.orders.find(func(order models.Order) bool {
+		switch order.Status {
+		case models.OrderStatusClosed, models.OrderStatusCancelled, models.OrderStatusVoided:
+			return false
+		}
+		return order.TableID == tableID
+	}, 0, 0), nil
+}
+
+func (o *orderRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	_, ok := o.orders.get(id)
+	return ok, nil
+}
+
+func (o *orderRepository) Create(ctx context.Context, order *models.Order) error {
+	if order.ID.IsZero() {
+		order.ID = primitive.NewObjectID()
+	}
+	o.orders.insert(order.ID, *order)
+	return nil
+}
+
+func (o *orderRepository) Update(ctx context.Context, id primitive.ObjectID, order models.Order) error {
+	updated := o.orders.update(id, func(current *models.Order) bool {
+		current.TableID = order.TableID
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (o *orderRepository) Transition(ctx context.Context, id primitive.ObjectID, change models.OrderStatusChange) error {
+	if _, ok := o.orders.get(id); !ok {
+		return repository.ErrNotFound
+	}
+	updated := o.orders.update(id, func(current *models.Order) bool {
+		status := current.Status
+		if status == "" {
+			status = models.OrderStatusPlaced
+		}
+		if status != change.From {
+			return false
+		}
+		current.Status = change.To
+		current.StatusHistory = append(current.StatusHistory, change)
+		current.UpdatedOn = change.ChangedOn
+		return true
+	})
+	if !updated {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (o *orderRepository) AddCoupon(ctx context.Context, id primitive.ObjectID, code string) error {
+	updated := o.orders.update(id, func(current *models.Order) bool {
+		if !contains(current.CouponCodes, code) {
+			current.CouponCodes = append(current.CouponCodes, code)
+		}
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (o *orderRepository) RemoveCoupon(ctx context.Context, id primitive.ObjectID, code string) error {
+	updated := o.orders.update(id, func(current *models.Order) bool {
+		codes := []string{}
+		for _, couponCode := range current.CouponCodes {
+			if couponCode != code {
+				codes = append(codes, couponCode)
+			}
+		}
+		current.CouponCodes = codes
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (o *orderRepository) MarkBilled(ctx context.Context, id primitive.ObjectID) error {
+	if _, ok := o.orders.get(id); !ok {
+		return repository.ErrNotFound
+	}
+	updated := o.orders.update(id, func(current *models.Order) bool {
+		if current.Billed {
+			return false
+		}
+		current.Billed = true
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (o *orderRepository) UnmarkBilled(ctx context.Context, id primitive.ObjectID) error {
+	updated := o.orders.update(id, func(current *models.Order) bool {
+		current.Billed = false
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type orderItemRepository struct {
+	orderItems *collection[models.OrderItem]
+	foods      *collection[models.Food]
+	orders     *collection[models.Order]
+	tables     *collection[models.Table]
+	menus      *collection[models.Menu]
+}
+
+func (o *orderItemRepository) List(ctx context.Context, skip int64, limit int64) ([]models.OrderItem, error) {
+	return o.orderItems.find(nil, skip, limit), nil
+}
+
+func (o *orderItemRepository) Get(ctx context.Context, id primitive.ObjectID) (models.OrderItem, error) {
+	orderItem, ok := o.orderItems.get(id)
+	if !ok {
+		return orderItem, repository.ErrNotFound
+	}
+	return orderItem, nil
+}
+
+func (o *orderItemRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItem, error) {
+	return o.orderItems.find(func(orderItem models.OrderItem) bool { return orderItem.OrderID == orderID }, 0, 0), nil
+}
+
+func (o *orderItemRepository) CreateMany(ctx context.Context, orderItems []models.OrderItem) ([]primitive.ObjectID, error) {
+	ids := []primitive.ObjectID{}
+	for _, orderItem := range orderItems {
+		if orderItem.ID.IsZero() {
+			orderItem.ID = primitive.NewObjectID()
+		}
+		o.orderItems.insert(orderItem.ID, orderItem)
+		ids = append(ids, orderItem.ID)
+	}
+	return ids, nil
+}
+
+func (o *orderItemRepository) Update(ctx context.Context, id primitive.ObjectID, orderItem models.OrderItem) error {
+	updated := o.orderItems.update(id, func(current *models.OrderItem) bool {
+		set(current, orderItem)
+		current.Modifiers = orderItem.Modifiers
+		current.Note = orderItem.Note
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (o *orderItemRepository) SummaryByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItemsSummary, error) {
+	orderItems, _ := o.ListByOrder(ctx, orderID)
+	if len(orderItems) == 0 {
+		return nil, nil
+	}
+
+	order, _ := o.orders.get(orderID)
+	table, _ := o.tables.get(order.TableID)
+	summary := models.OrderItemsSummary{
+		ID: models.OrderItemsSummaryKey{
+			OrderID:     order.ID,
+			TableID:     order.TableID,
+			TableNumber: table.Number,
+		},
+		TableNumber: table.Number,
+		OrderItems:  []models.OrderItemDetail{},
+	}
+	for _, orderItem := range orderItems {
+		food, _ := o.foods.get(orderItem.FoodID)
+		price := orderItemPrice(orderItem)
+		summary.OrderItems = append(summary.OrderItems, models.OrderItemDetail{
+			ID:          orderItem.ID,
+			FoodID:      orderItem.FoodID,
+			FoodName:    food.Name,
+			FoodImage:   food.FoodImage,
+			TableNumber: table.Number,
+			TableID:     order.TableID,
+			Price:       price,
+			OrderID:     order.ID,
+			Quantity:    orderItem.Quantity,
+			Note:        orderItem.Note,
+		})
+		summary.PaymentDue += price * float64(orderItem.Quantity)
+		summary.TotalCount++
+	}
+	return []models.OrderItemsSummary{summary}, nil
+}
+
+func (o *orderItemRepository) SalesByFood(ctx context.Context, from time.Time, to time.Time) ([]models.FoodSales, error) {
+	sales := []models.FoodSales{}
+	index := map[primitive.ObjectID]int{}
+	for _, orderItem := range o.soldBetween(from, to) {
+		i, ok := index[orderItem.FoodID]
+		if !ok {
+			i = len(sales)
+			index[orderItem.FoodID] = i
+			sales = append(sales, models.FoodSales{FoodID: orderItem.FoodID})
+		}
+		sales[i].Quantity += orderItem.Quantity
+		sales[i].Revenue += orderItemPrice(orderItem) * float64(orderItem.Quantity)
+	}
+	return sales, nil
+}
+
+func (o *orderItemRepository) SalesByCategory(ctx context.Context, from time.Time, to time.Time) ([]models.CategorySales, error) {
+	sales := []models.CategorySales{}
+	index := map[string]int{}
+	orders := map[string]map[primitive.ObjectID]bool{}
+	for _, orderItem := range o.soldBetween(from, to) {
+		category := ""
+		if food, ok := o.foods.get(orderItem.FoodID); ok {
+			if menu, ok := o.menus.get(food.MenuID); ok {
+				category = menu.Category
+			}
+		}
+		i, ok := index[category]
+		if !ok {
+			i = len(sales)
+			index[category] = i
+			orders[category] = map[primitive.ObjectID]bool{}
+			sales = append(sales, models.CategorySales{Category: category})
+		}
+		sales[i].Quantity += orderItem.Quantity
+		sales[i].Revenue += orderItemPrice(orderItem) * float64(orderItem.Quantity)
+		if !orders[category][orderItem.OrderID] {
+			orders[category][orderItem.OrderID] = true
+			sales[i].Orders++
+		}
+	}
+	return sales, nil
+}
+
+func (o *orderItemRepository) OrderVolume(ctx context.Context, from time.Time, to time.Time) ([]models.OrderVolume, error) {
+	volumes := []models.OrderVolume{}
+	index := map[[2]int]int{}
+	orders := map[[2]int]map[primitive.ObjectID]bool{}
+	for _, orderItem := range o.soldBetween(from, to) {
+		at := orderItem.CreatedOn.In(from.Location())
+		key := [2]int{int(at.Weekday()), at.Hour()}
+		i, ok := index[key]
+		if !ok {
+			i = len(volumes)
+			index[key] = i
+			orders[key] = map[primitive.ObjectID]bool{}
+			volumes = append(volumes, models.OrderVolume{Weekday: key[0], Hour: key[1]})
+		}
+		volumes[i].Items += orderItem.Quantity
+		if !orders[key][orderItem.OrderID] {
+			orders[key][orderItem.OrderID] = true
+			volumes[i].Orders++
+		}
+	}
+	return volumes, nil
+}
+
+// soldBetween returns the items ordered from from until to, leaving out
+// those of cancelled and voided orders
+func (o *orderItemRepository) soldBetween(from time.Time, to time.Time) []models.OrderItem {
+	return o.orderItems.find(func(orderItem models.OrderItem) bool {
+		if orderItem.CreatedOn.Before(from) || !orderItem.CreatedOn.Before(to) {
+			return false
+		}
+		order, ok := o.orders.get(orderItem.OrderID)
+		return ok && order.Status != models.OrderStatusCancelled && order.Status != models.OrderStatusVoided
+	}, 0, 0)
+}
+
+// orderItemPrice is the price of one unit of the item with its modifiers
+func orderItemPrice(orderItem models.OrderItem) float64 {
+	price := orderItem.UnitPrice
+	for _, modifier := range orderItem.Modifiers {
+		price += modifier.Price
+	}
+	return price
+}
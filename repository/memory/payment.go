@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type paymentRepository struct {
+	payments *collection[models.Payment]
+}
+
+func (p *paymentRepository) ListByInvoice(ctx context.Context, invoiceID primitive.ObjectID) ([]models.Payment, error) {
+	return p.payments.find(func(payment models.Payment) bool { return payment.InvoiceID == invoiceID }, 0, 0), nil
+}
+
+func (p *paymentRepository) ListReceived(ctx context.Context, from time.Time, to time.Time) ([]models.Payment, error) {
+	return p.payments.find(func(payment models.Payment) bool {
+		return !payment.CreatedOn.Before(from) && payment.CreatedOn.Before(to)
+	}, 0, 0), nil
+}
+
+func (p *paymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	p.payments.insert(payment.ID, *payment)
+	return nil
+}
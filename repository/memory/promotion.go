@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type promotionRepository struct {
+	promotions *collection[models.Promotion]
+}
+
+func (p *promotionRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Promotion, error) {
+	return p.promotions.find(nil, skip, limit), nil
+}
+
+func (p *promotionRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Promotion, error) {
+	promotion, ok := p.promotions.get(id)
+	if !ok {
+		return promotion, repository.ErrNotFound
+	}
+	return promotion, nil
+}
+
+func (p *promotionRepository) GetByCoupon(ctx context.Context, code string) (models.Promotion, error) {
+	promotion, ok := p.promotions.findOne(func(promotion models.Promotion) bool { return promotion.CouponCode == code })
+	if !ok {
+		return promotion, repository.ErrNotFound
+	}
+	return promotion, nil
+}
+
+func (p *promotionRepository) ListActive(ctx context.Context, from time.Time, to time.Time) ([]models.Promotion, error) {
+	return p.promotions.find(func(promotion models.Promotion) bool {
+		return (promotion.StartDate.IsZero() || !promotion.StartDate.After(to)) &&
+			(promotion.EndDate.IsZero() || !promotion.EndDate.Before(from)) &&
+			(promotion.UsageLimit == 0 || promotion.UsedCount < promotion.UsageLimit)
+	}, 0, 0), nil
+}
+
+func (p *promotionRepository) Create(ctx context.Context, promotion *models.Promotion) error {
+	if promotion.ID.IsZero() {
+		promotion.ID = primitive.NewObjectID()
+	}
+	p.promotions.insert(promotion.ID, *promotion)
+	return nil
+}
+
+func (p *promotionRepository) Update(ctx context.Context, id primitive.ObjectID, promotion models.Promotion) error {
+	if !p.promotions.update(id, func(current *models.Promotion) bool { set(current, promotion); return true }) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (p *promotionRepository) Redeem(ctx context.Context, id primitive.ObjectID) error {
+	if _, ok := p.promotions.get(id); !ok {
+		return repository.ErrNotFound
+	}
+	updated := p.promotions.update(id, func(current *models.Promotion) bool {
+		if current.UsageLimit > 0 && current.UsedCount >= current.UsageLimit {
+			return false
+		}
+		current.UsedCount++
+		return true
+	})
+	if !updated {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (p *promotionRepository) Unredeem(ctx context.Context, id primitive.ObjectID) error {
+	p.promotions.update(id, func(current *models.Promotion) bool {
+		if current.UsedCount == 0 {
+			return false
+		}
+		current.UsedCount--
+		return true
+	})
+	return nil
+}
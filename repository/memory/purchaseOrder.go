@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type purchaseOrderRepository struct {
+	purchaseOrders *collection[models.PurchaseOrder]
+}
+
+func (p *purchaseOrderRepository) List(ctx context.Context, skip int64, limit int64) ([]models.PurchaseOrder, error) {
+	return p.purchaseOrders.find(nil, skip, limit), nil
+}
+
+func (p *purchaseOrderRepository) Get(ctx context.Context, id primitive.ObjectID) (models.PurchaseOrder, error) {
+	purchaseOrder, ok := p.purchaseOrders.get(id)
+	if !ok {
+		return purchaseOrder, repository.ErrNotFound
+	}
+	return purchaseOrder, nil
+}
+
+func (p *purchaseOrderRepository) ListOpen(ctx context.Context) ([]models.PurchaseOrder, error) {
+	return p.purchaseOrders.find(func(purchaseOrder models.PurchaseOrder) bool {
+		return purchaseOrder.Status == models.PurchaseOrderStatusOrdered || purchaseOrder.Status == models.PurchaseOrderStatusPartiallyReceived
+	}, 0, 0), nil
+}
+
+func (p *purchaseOrderRepository) Create(ctx context.Context, purchaseOrder *models.PurchaseOrder) error {
+	if purchaseOrder.ID.IsZero() {
+		purchaseOrder.ID = primitive.NewObjectID()
+	}
+	p.purchaseOrders.insert(purchaseOrder.ID, *purchaseOrder)
+	return nil
+}
+
+func (p *purchaseOrderRepository) Update(ctx context.Context, id primitive.ObjectID, updatedOn time.Time, purchaseOrder models.PurchaseOrder) error {
+	if _, ok := p.purchaseOrders.get(id); !ok {
+		return repository.ErrNotFound
+	}
+	updated := p.purchaseOrders.update(id, func(current *models.PurchaseOrder) bool {
+		if !current.UpdatedOn.Equal(updatedOn) {
+			return false
+		}
+		current.Status = purchaseOrder.Status
+		current.Lines = purchaseOrder.Lines
+		current.Receipts = purchaseOrder.Receipts
+		current.UpdatedOn = purchaseOrder.UpdatedOn
+		return true
+	})
+	if !updated {
+		return repository.ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type recipeRepository struct {
+	recipes *collection[models.Recipe]
+}
+
+func (r *recipeRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Recipe, error) {
+	return r.recipes.find(nil, skip, limit), nil
+}
+
+func (r *recipeRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Recipe, error) {
+	recipe, ok := r.recipes.get(id)
+	if !ok {
+		return recipe, repository.ErrNotFound
+	}
+	return recipe, nil
+}
+
+func (r *recipeRepository) ListByFoods(ctx context.Context, foodIDs []primitive.ObjectID) ([]models.Recipe, error) {
+	wanted := map[primitive.ObjectID]bool{}
+	for _, id := range foodIDs {
+		wanted[id] = true
+	}
+	return r.recipes.find(func(recipe models.Recipe) bool { return wanted[recipe.FoodID] }, 0, 0), nil
+}
+
+func (r *recipeRepository) Create(ctx context.Context, recipe *models.Recipe) error {
+	if _, found := r.recipes.findOne(func(existing models.Recipe) bool { return existing.FoodID == recipe.FoodID }); found {
+		return repository.ErrConflict
+	}
+	if recipe.ID.IsZero() {
+		recipe.ID = primitive.NewObjectID()
+	}
+	r.recipes.insert(recipe.ID, *recipe)
+	return nil
+}
+
+func (r *recipeRepository) Update(ctx context.Context, id primitive.ObjectID, recipe models.Recipe) error {
+	updated := r.recipes.update(id, func(current *models.Recipe) bool {
+		current.Ingredients = recipe.Ingredients
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
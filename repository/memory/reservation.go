@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reservationRepository struct {
+	// booking makes checking for overlaps and inserting one step
+	booking      sync.Mutex
+	reservations *collection[models.Reservation]
+}
+
+func (re *reservationRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Reservation, error) {
+	return re.reservations.find(nil, skip, limit), nil
+}
+
+func (re *reservationRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Reservation, error) {
+	reservation, ok := re.reservations.get(id)
+	if !ok {
+		return reservation, repository.ErrNotFound
+	}
+	return reservation, nil
+}
+
+func (re *reservationRepository) Overlapping(ctx context.Context, from time.Time, to time.Time) ([]models.Reservation, error) {
+	return re.reservations.find(overlapping(from, to), 0, 0), nil
+}
+
+func (re *reservationRepository) Create(ctx context.Context, reservation *models.Reservation) error {
+	re.booking.Lock()
+	defer re.booking.Unlock()
+
+	overlaps := overlapping(reservation.At, reservation.EndsAt)
+	_, taken := re.reservations.findOne(func(existing models.Reservation) bool {
+		return existing.TableID == reservation.TableID && overlaps(existing)
+	})
+	if taken {
+		return repository.ErrConflict
+	}
+
+	if reservation.ID.IsZero() {
+		reservation.ID = primitive.NewObjectID()
+	}
+	re.reservations.insert(reservation.ID, *reservation)
+	return nil
+}
+
+func (re *reservationRepository) Cancel(ctx context.Context, id primitive.ObjectID) error {
+	updated := re.reservations.update(id, func(current *models.Reservation) bool {
+		current.Status = models.ReservationStatusCancelled
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func overlapping(from time.Time, to time.Time) func(models.Reservation) bool {
+	return func(reservation models.Reservation) bool {
+		return reservation.Status == models.ReservationStatusBooked && reservation.At.Before(to) && reservation.EndsAt.After(from)
+	}
+}
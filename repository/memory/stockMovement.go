@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type stockMovementRepository struct {
+	movements *collection[models.StockMovement]
+}
+
+func (s *stockMovementRepository) ListByIngredient(ctx context.Context, ingredientID primitive.ObjectID, skip int64, limit int64) ([]models.StockMovement, error) {
+	return s.movements.find(func(movement models.StockMovement) bool { return movement.IngredientID == ingredientID }, skip, limit), nil
+}
+
+func (s *stockMovementRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.StockMovement, error) {
+	return s.movements.find(func(movement models.StockMovement) bool { return movement.OrderID == orderID }, 0, 0), nil
+}
+
+func (s *stockMovementRepository) Consumption(ctx context.Context, from time.Time, to time.Time) (map[primitive.ObjectID]float64, error) {
+	consumption := map[primitive.ObjectID]float64{}
+	for _, movement := range s.movements.find(nil, 0, 0) {
+		if movement.Reason != models.StockMovementSale && movement.Reason != models.StockMovementVoid {
+			continue
+		}
+		if movement.CreatedOn.Before(from) || !movement.CreatedOn.Before(to) {
+			continue
+		}
+		consumption[movement.IngredientID] -= movement.Quantity
+	}
+	return consumption, nil
+}
+
+func (s *stockMovementRepository) CreateMany(ctx context.Context, movements []models.StockMovement) error {
+	for _, movement := range movements {
+		if movement.ID.IsZero() {
+			movement.ID = primitive.NewObjectID()
+		}
+		s.movements.insert(movement.ID, movement)
+	}
+	return nil
+}
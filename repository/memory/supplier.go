@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type supplierRepository struct {
+	suppliers *collection[models.Supplier]
+}
+
+func (s *supplierRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Supplier, error) {
+	return s.suppliers.find(nil, skip, limit), nil
+}
+
+func (s *supplierRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Supplier, error) {
+	supplier, ok := s.suppliers.get(id)
+	if !ok {
+		return supplier, repository.ErrNotFound
+	}
+	return supplier, nil
+}
+
+func (s *supplierRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	_, ok := s.suppliers.get(id)
+	return ok, nil
+}
+
+func (s *supplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	if supplier.ID.IsZero() {
+		supplier.ID = primitive.NewObjectID()
+	}
+	s.suppliers.insert(supplier.ID, *supplier)
+	return nil
+}
+
+func (s *supplierRepository) Update(ctx context.Context, id primitive.ObjectID, supplier models.Supplier) error {
+	if !s.suppliers.update(id, func(current *models.Supplier) bool { set(current, supplier); return true }) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
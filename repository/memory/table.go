@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type tableRepository struct {
+	tables *collection[models.Table]
+}
+
+func (t *tableRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Table, error) {
+	return t.tables.find(nil, skip, limit), nil
+}
+
+func (t *tableRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Table, error) {
+	table, ok := t.tables.get(id)
+	if !ok {
+		return table, repository.ErrNotFound
+	}
+	return table, nil
+}
+
+func (t *tableRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	_, ok := t.tables.get(id)
+	return ok, nil
+}
+
+func (t *tableRepository) Create(ctx context.Context, table *models.Table) error {
+	if table.ID.IsZero() {
+		table.ID = primitive.NewObjectID()
+	}
+	t.tables.insert(table.ID, *table)
+	return nil
+}
+
+func (t *tableRepository) Update(ctx context.Context, id primitive.ObjectID, table models.Table) error {
+	if !t.tables.update(id, func(current *models.Table) bool { set(current, table); return true }) {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (t *tableRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	updated := t.tables.update(id, func(current *models.Table) bool {
+		current.Status = status
+		current.StatusChangedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
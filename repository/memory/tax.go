@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type taxRateRepository struct {
+	taxRates *collection[models.TaxRate]
+}
+
+func (t *taxRateRepository) List(ctx context.Context) ([]models.TaxRate, error) {
+	return t.taxRates.find(nil, 0, 0), nil
+}
+
+func (t *taxRateRepository) Get(ctx context.Context, id primitive.ObjectID) (models.TaxRate, error) {
+	taxRate, ok := t.taxRates.get(id)
+	if !ok {
+		return taxRate, repository.ErrNotFound
+	}
+	return taxRate, nil
+}
+
+func (t *taxRateRepository) CategoryTaken(ctx context.Context, category string, exceptID primitive.ObjectID) (bool, error) {
+	_, ok := t.taxRates.findOne(func(taxRate models.TaxRate) bool {
+		return taxRate.Category == category && taxRate.ID != exceptID
+	})
+	return ok, nil
+}
+
+func (t *taxRateRepository) Create(ctx context.Context, taxRate *models.TaxRate) error {
+	if taxRate.ID.IsZero() {
+		taxRate.ID = primitive.NewObjectID()
+	}
+	t.taxRates.insert(taxRate.ID, *taxRate)
+	return nil
+}
+
+func (t *taxRateRepository) Update(ctx context.Context, id primitive.ObjectID, taxRate models.TaxRate) error {
+	updated := t.taxRates.update(id, func(current *models.TaxRate) bool {
+		current.Name = taxRate.Name
+		current.Category = taxRate.Category
+		current.Inclusive = taxRate.Inclusive
+		current.Components = taxRate.Components
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
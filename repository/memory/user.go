@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type userRepository struct {
+	users *collection[models.User]
+}
+
+func (u *userRepository) List(ctx context.Context, skip int64, limit int64) ([]models.User, error) {
+	return u.users.find(nil, skip, limit), nil
+}
+
+func (u *userRepository) Get(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	user, ok := u.users.get(id)
+	if !ok {
+		return user, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (u *userRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	user, ok := u.users.findOne(func(user models.User) bool { return user.Email == email })
+	if !ok {
+		return user, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (u *userRepository) EmailOrPhoneTaken(ctx context.Context, email string, phone string) (bool, error) {
+	_, ok := u.users.findOne(func(user models.User) bool { return user.Email == email || user.Phone == phone })
+	return ok, nil
+}
+
+func (u *userRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(u.users.find(nil, 0, 0))), nil
+}
+
+func (u *userRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	return int64(len(u.users.find(func(user models.User) bool { return user.Role == role }, 0, 0))), nil
+}
+
+func (u *userRepository) Create(ctx context.Context, user *models.User) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	u.users.insert(user.ID, *user)
+	return nil
+}
+
+func (u *userRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	updated := u.users.update(id, func(current *models.User) bool {
+		current.Role = role
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (u *userRepository) AssignMissingRoles(ctx context.Context, role string) error {
+	for _, user := range u.users.find(func(user models.User) bool { return user.Role == "" }, 0, 0) {
+		u.users.update(user.ID, func(current *models.User) bool {
+			if current.Role != "" {
+				return false
+			}
+			current.Role = role
+			current.UpdatedOn = time.Now()
+			return true
+		})
+	}
+	return nil
+}
+
+func (u *userRepository) UpdateTokens(ctx context.Context, id primitive.ObjectID, token string, refreshToken string) error {
+	updated := u.users.update(id, func(current *models.User) bool {
+		current.Token = token
+		current.RefreshToken = refreshToken
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (u *userRepository) RotateTokens(ctx context.Context, id primitive.ObjectID, usedRefreshToken string, token string, refreshToken string) error {
+	updated := u.users.update(id, func(current *models.User) bool {
+		if current.RefreshToken != usedRefreshToken {
+			return false
+		}
+		current.Token = token
+		current.RefreshToken = refreshToken
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (u *userRepository) RevokeTokens(ctx context.Context, id primitive.ObjectID) error {
+	updated := u.users.update(id, func(current *models.User) bool {
+		current.TokenVersion++
+		current.Token = ""
+		current.RefreshToken = ""
+		current.UpdatedOn = time.Now()
+		return true
+	})
+	if !updated {
+		return repository.ErrNotFound
+	}
+	return nil
+}
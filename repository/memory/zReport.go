@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type zReportRepository struct {
+	closing  sync.Mutex
+	zReports *collection[models.ZReport]
+}
+
+func (z *zReportRepository) List(ctx context.Context, skip int64, limit int64) ([]models.ZReport, error) {
+	zReports := z.zReports.find(nil, 0, 0)
+	sort.SliceStable(zReports, func(i, j int) bool { return zReports[i].Date > zReports[j].Date })
+	if skip >= int64(len(zReports)) {
+		return nil, nil
+	}
+	zReports = zReports[skip:]
+	if limit > 0 && limit < int64(len(zReports)) {
+		zReports = zReports[:limit]
+	}
+	return zReports, nil
+}
+
+func (z *zReportRepository) GetByDate(ctx context.Context, date string) (models.ZReport, error) {
+	zReport, ok := z.zReports.findOne(func(zReport models.ZReport) bool { return zReport.Date == date })
+	if !ok {
+		return zReport, repository.ErrNotFound
+	}
+	return zReport, nil
+}
+
+func (z *zReportRepository) Create(ctx context.Context, zReport *models.ZReport) error {
+	z.closing.Lock()
+	defer z.closing.Unlock()
+	if _, found := z.zReports.findOne(func(existing models.ZReport) bool { return existing.Date == zReport.Date }); found {
+		return repository.ErrConflict
+	}
+	if zReport.ID.IsZero() {
+		zReport.ID = primitive.NewObjectID()
+	}
+	z.zReports.insert(zReport.ID, *zReport)
+	return nil
+}
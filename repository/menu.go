@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MenuRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Menu, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Menu, error)
+	Exists(ctx context.Context, id primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, menu *models.Menu) error
+	// Update replaces the name, category, dates and serving hours of the menu
+	Update(ctx context.Context, id primitive.ObjectID, menu models.Menu) error
+}
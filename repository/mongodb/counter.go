@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type counterRepository struct {
+	collection *mongo.Collection
+}
+
+type counter struct {
+	Name  string `bson:"_id"`
+	Value int64  `bson:"value"`
+}
+
+func (c *counterRepository) Next(ctx context.Context, name string) (int64, error) {
+	return nextValue(ctx, c.collection, name)
+}
+
+// nextValue increments the named counter in collection and returns its new
+// value
+func nextValue(ctx context.Context, collection *mongo.Collection, name string) (int64, error) {
+	var next counter
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": name}, bson.M{"$inc": bson.M{"value": 1}}, opts).Decode(&next)
+	if err != nil {
+		return 0, err
+	}
+	return next.Value, nil
+}
+
+func (c *counterRepository) Release(ctx context.Context, name string, value int64) error {
+	_, err := c.collection.UpdateOne(ctx, bson.M{"_id": name, "value": value}, bson.M{"$inc": bson.M{"value": -1}})
+	return err
+}
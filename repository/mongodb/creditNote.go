@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type creditNoteRepository struct {
+	collection *mongo.Collection
+}
+
+func (c *creditNoteRepository) List(ctx context.Context, invoiceID primitive.ObjectID, status string, skip int64, limit int64) ([]models.CreditNote, error) {
+	query := bson.M{}
+	if !invoiceID.IsZero() {
+		query["invoiceID"] = invoiceID
+	}
+	if status != "" {
+		query["status"] = status
+	}
+	return findAll[models.CreditNote](ctx, c.collection, query, page(skip, limit))
+}
+
+func (c *creditNoteRepository) Get(ctx context.Context, id primitive.ObjectID) (models.CreditNote, error) {
+	return findOne[models.CreditNote](ctx, c.collection, bson.M{"_id": id})
+}
+
+func (c *creditNoteRepository) ListByInvoice(ctx context.Context, invoiceID primitive.ObjectID) ([]models.CreditNote, error) {
+	return findAll[models.CreditNote](ctx, c.collection, bson.M{"invoiceID": invoiceID}, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (c *creditNoteRepository) ListReviewed(ctx context.Context, status string, from time.Time, to time.Time) ([]models.CreditNote, error) {
+	query := bson.M{
+		"status":     status,
+		"reviewedOn": bson.M{"$gte": from, "$lt": to},
+	}
+	return findAll[models.CreditNote](ctx, c.collection, query)
+}
+
+func (c *creditNoteRepository) Create(ctx context.Context, creditNote *models.CreditNote) error {
+	result, err := c.collection.InsertOne(ctx, creditNote)
+	if err != nil {
+		return err
+	}
+	creditNote.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (c *creditNoteRepository) Review(ctx context.Context, id primitive.ObjectID, creditNote models.CreditNote) (models.CreditNote, error) {
+	var updated models.CreditNote
+	query := bson.M{
+		"_id":    id,
+		"status": models.CreditNoteStatusPending,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     creditNote.Status,
+			"reviewedBy": creditNote.ReviewedBy,
+			"reviewedOn": creditNote.ReviewedOn,
+			"updatedOn":  creditNote.UpdatedOn,
+		},
+	}
+	err := c.collection.FindOneAndUpdate(ctx, query, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		found, err := exists(ctx, c.collection, bson.M{"_id": id})
+		if err != nil {
+			return updated, err
+		}
+		if !found {
+			return updated, repository.ErrNotFound
+		}
+		return updated, repository.ErrConflict
+	}
+	return updated, err
+}
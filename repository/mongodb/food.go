@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type foodRepository struct {
+	collection *mongo.Collection
+}
+
+func (f *foodRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Food, error) {
+	return findAll[models.Food](ctx, f.collection, bson.M{}, page(skip, limit))
+}
+
+func (f *foodRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Food, error) {
+	return findOne[models.Food](ctx, f.collection, bson.M{"_id": id})
+}
+
+func (f *foodRepository) GetMany(ctx context.Context, ids []primitive.ObjectID) ([]models.Food, error) {
+	return findAll[models.Food](ctx, f.collection, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (f *foodRepository) ListByMenus(ctx context.Context, menuIDs []primitive.ObjectID) ([]models.Food, error) {
+	return findAll[models.Food](ctx, f.collection, bson.M{"menuID": bson.M{"$in": menuIDs}})
+}
+
+func (f *foodRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	return exists(ctx, f.collection, bson.M{"_id": id})
+}
+
+func (f *foodRepository) Create(ctx context.Context, food *models.Food) error {
+	result, err := f.collection.InsertOne(ctx, food)
+	if err != nil {
+		return err
+	}
+	food.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (f *foodRepository) Update(ctx context.Context, id primitive.ObjectID, food models.Food) error {
+	return updateOne(ctx, f.collection, bson.M{"_id": id}, bson.M{"$set": food})
+}
+
+func (f *foodRepository) SetAvailability(ctx context.Context, id primitive.ObjectID, soldOut bool, remainingPortions *int) error {
+	update := bson.M{
+		"$set": bson.M{
+			"soldOut":   soldOut,
+			"updatedOn": time.Now(),
+		},
+	}
+	if remainingPortions != nil {
+		update["$set"].(bson.M)["remainingPortions"] = *remainingPortions
+	} else {
+		update["$unset"] = bson.M{"remainingPortions": ""}
+	}
+	return updateOne(ctx, f.collection, bson.M{"_id": id}, update)
+}
+
+func (f *foodRepository) TakePortions(ctx context.Context, id primitive.ObjectID, quantity int) error {
+	// Only take portions that are there, so concurrent orders cannot
+	// drive the count below 0
+	query := bson.M{
+		"_id":               id,
+		"soldOut":           bson.M{"$ne": true},
+		"remainingPortions": bson.M{"$gte": quantity},
+	}
+	err := updateOne(ctx, f.collection, query, bson.M{"$inc": bson.M{"remainingPortions": -quantity}})
+	if err == repository.ErrNotFound {
+		found, err := exists(ctx, f.collection, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if found {
+			return repository.ErrConflict
+		}
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+func (f *foodRepository) ReturnPortions(ctx context.Context, id primitive.ObjectID, quantity int) error {
+	query := bson.M{
+		"_id":               id,
+		"remainingPortions": bson.M{"$exists": true},
+	}
+	err := updateOne(ctx, f.collection, query, bson.M{"$inc": bson.M{"remainingPortions": quantity}})
+	if err == repository.ErrNotFound {
+		// Counting stopped in the meantime, there is nothing to give back to
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ingredientRepository struct {
+	collection *mongo.Collection
+}
+
+func (i *ingredientRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Ingredient, error) {
+	return findAll[models.Ingredient](ctx, i.collection, bson.M{}, page(skip, limit))
+}
+
+func (i *ingredientRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Ingredient, error) {
+	return findOne[models.Ingredient](ctx, i.collection, bson.M{"_id": id})
+}
+
+func (i *ingredientRepository) Create(ctx context.Context, ingredient *models.Ingredient) error {
+	result, err := i.collection.InsertOne(ctx, ingredient)
+	if err != nil {
+		return err
+	}
+	ingredient.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (i *ingredientRepository) Update(ctx context.Context, id primitive.ObjectID, ingredient models.Ingredient) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":              ingredient.Name,
+			"unit":              ingredient.Unit,
+			"lowStockThreshold": ingredient.LowStockThreshold,
+			"updatedOn":         ingredient.UpdatedOn,
+		},
+	}
+	return updateOne(ctx, i.collection, bson.M{"_id": id}, update)
+}
+
+func (i *ingredientRepository) SetCost(ctx context.Context, id primitive.ObjectID, costPrice float64, supplierID primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"costPrice":  costPrice,
+			"supplierID": supplierID,
+		},
+	}
+	return updateOne(ctx, i.collection, bson.M{"_id": id}, update)
+}
+
+func (i *ingredientRepository) AdjustStock(ctx context.Context, id primitive.ObjectID, quantity float64) error {
+	return updateOne(ctx, i.collection, bson.M{"_id": id}, bson.M{"$inc": bson.M{"stock": quantity}})
+}
+
+func (i *ingredientRepository) ListLowStock(ctx context.Context) ([]models.Ingredient, error) {
+	query := bson.M{"$expr": bson.M{"$lte": []interface{}{"$stock", "$lowStockThreshold"}}}
+	return findAll[models.Ingredient](ctx, i.collection, query)
+}
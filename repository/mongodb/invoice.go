@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type invoiceRepository struct {
+	collection *mongo.Collection
+	counters   *mongo.Collection
+}
+
+func (i *invoiceRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Invoice, error) {
+	return findAll[models.Invoice](ctx, i.collection, bson.M{}, page(skip, limit))
+}
+
+func (i *invoiceRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Invoice, error) {
+	return findOne[models.Invoice](ctx, i.collection, bson.M{"_id": id})
+}
+
+func (i *invoiceRepository) GetByNumber(ctx context.Context, number string) (models.Invoice, error) {
+	return findOne[models.Invoice](ctx, i.collection, bson.M{"number": number})
+}
+
+func (i *invoiceRepository) ExistsByOrder(ctx context.Context, orderID primitive.ObjectID) (bool, error) {
+	return exists(ctx, i.collection, bson.M{"orderID": orderID})
+}
+
+func (i *invoiceRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.Invoice, error) {
+	return findAll[models.Invoice](ctx, i.collection, bson.M{"orderID": orderID})
+}
+
+func (i *invoiceRepository) ListBySplitGroup(ctx context.Context, splitGroupID primitive.ObjectID) ([]models.Invoice, error) {
+	return findAll[models.Invoice](ctx, i.collection, bson.M{"splitGroupID": splitGroupID}, options.Find().SetSort(bson.D{{Key: "splitPart", Value: 1}}))
+}
+
+func (i *invoiceRepository) ListIssued(ctx context.Context, from time.Time, to time.Time) ([]models.Invoice, error) {
+	return findAll[models.Invoice](ctx, i.collection, bson.M{"createdOn": bson.M{"$gte": from, "$lt": to}})
+}
+
+func (i *invoiceRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	result, err := i.collection.InsertOne(ctx, invoice)
+	if err != nil {
+		return err
+	}
+	invoice.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (i *invoiceRepository) CreateNumbered(ctx context.Context, counter string, number func(sequence int64) string, invoices []models.Invoice) error {
+	// The counter and the invoices are written in one transaction, so a
+	// failed insert hands no number out. Transactions need MongoDB to run
+	// as a replica set.
+	session, err := i.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		for n := range invoices {
+			sequence, err := nextValue(sessionContext, i.counters, counter)
+			if err != nil {
+				return nil, err
+			}
+			invoices[n].Number = number(sequence)
+			taken, err := exists(sessionContext, i.collection, bson.M{"number": invoices[n].Number})
+			if err != nil {
+				return nil, err
+			}
+			if taken {
+				return nil, repository.ErrConflict
+			}
+			result, err := i.collection.InsertOne(sessionContext, invoices[n])
+			if err != nil {
+				return nil, err
+			}
+			invoices[n].ID = result.InsertedID.(primitive.ObjectID)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (i *invoiceRepository) UpdateDueDate(ctx context.Context, id primitive.ObjectID, dueDate time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"paymentDueDate": dueDate,
+			"updatedOn":      time.Now(),
+		},
+	}
+	return updateOne(ctx, i.collection, bson.M{"_id": id}, update)
+}
+
+func (i *invoiceRepository) UpdateSettlement(ctx context.Context, id primitive.ObjectID, invoice models.Invoice) error {
+	update := bson.M{
+		"$set": bson.M{
+			"paymentMethod": invoice.PaymentMethod,
+			"paymentStatus": invoice.PaymentStatus,
+			"amountPaid":    invoice.AmountPaid,
+			"credited":      invoice.Credited,
+			"refunded":      invoice.Refunded,
+			"balance":       invoice.Balance,
+			"updatedOn":     time.Now(),
+		},
+	}
+	return updateOne(ctx, i.collection, bson.M{"_id": id}, update)
+}
@@ -0,0 +1,127 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type kitchenTicketRepository struct {
+	collection *mongo.Collection
+}
+
+func (k *kitchenTicketRepository) List(ctx context.Context, station string, statuses []string) ([]models.KitchenTicket, error) {
+	query := bson.M{"status": bson.M{"$in": statuses}}
+	if station != "" {
+		query["station"] = station
+	}
+	return findAll[models.KitchenTicket](ctx, k.collection, query, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (k *kitchenTicketRepository) Get(ctx context.Context, id primitive.ObjectID) (models.KitchenTicket, error) {
+	return findOne[models.KitchenTicket](ctx, k.collection, bson.M{"_id": id})
+}
+
+func (k *kitchenTicketRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.KitchenTicket, error) {
+	return findAll[models.KitchenTicket](ctx, k.collection, bson.M{"orderID": orderID})
+}
+
+func (k *kitchenTicketRepository) CreateMany(ctx context.Context, tickets []models.KitchenTicket) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+	ticketsToBeInserted := []interface{}{}
+	for i := range tickets {
+		if tickets[i].ID.IsZero() {
+			tickets[i].ID = primitive.NewObjectID()
+		}
+		ticketsToBeInserted = append(ticketsToBeInserted, tickets[i])
+	}
+	_, err := k.collection.InsertMany(ctx, ticketsToBeInserted)
+	return err
+}
+
+func (k *kitchenTicketRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from []string, ticket models.KitchenTicket) (models.KitchenTicket, error) {
+	var updated models.KitchenTicket
+	query := bson.M{
+		"_id":    id,
+		"status": bson.M{"$in": from},
+	}
+	err := k.collection.FindOneAndUpdate(ctx, query, bson.M{"$set": ticket}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		found, err := exists(ctx, k.collection, bson.M{"_id": id})
+		if err != nil {
+			return updated, err
+		}
+		if !found {
+			return updated, repository.ErrNotFound
+		}
+		return updated, repository.ErrConflict
+	}
+	return updated, err
+}
+
+func (k *kitchenTicketRepository) UpdateByOrderItem(ctx context.Context, orderItemID primitive.ObjectID, ticket models.KitchenTicket) ([]models.KitchenTicket, error) {
+	query := bson.M{
+		"orderItemID": orderItemID,
+		"status":      bson.M{"$in": []string{models.TicketStatusQueued, models.TicketStatusInProgress}},
+	}
+	tickets, err := findAll[models.KitchenTicket](ctx, k.collection, query)
+	if err != nil || len(tickets) == 0 {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{}
+	for _, current := range tickets {
+		ids = append(ids, current.ID)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"foodID":    ticket.FoodID,
+			"foodName":  ticket.FoodName,
+			"station":   ticket.Station,
+			"quantity":  ticket.Quantity,
+			"modifiers": ticket.Modifiers,
+			"note":      ticket.Note,
+			"updatedOn": time.Now(),
+		},
+	}
+	_, err = k.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return nil, err
+	}
+	return findAll[models.KitchenTicket](ctx, k.collection, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (k *kitchenTicketRepository) CancelByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.KitchenTicket, error) {
+	query := bson.M{
+		"orderID": orderID,
+		"status":  bson.M{"$in": []string{models.TicketStatusQueued, models.TicketStatusInProgress}},
+	}
+	tickets, err := findAll[models.KitchenTicket](ctx, k.collection, query)
+	if err != nil || len(tickets) == 0 {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{}
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.ID)
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":    models.TicketStatusCancelled,
+			"updatedOn": time.Now(),
+		},
+	}
+	_, err = k.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return nil, err
+	}
+	return findAll[models.KitchenTicket](ctx, k.collection, bson.M{"_id": bson.M{"$in": ids}})
+}
@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type menuRepository struct {
+	collection *mongo.Collection
+}
+
+func (m *menuRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Menu, error) {
+	return findAll[models.Menu](ctx, m.collection, bson.M{}, page(skip, limit))
+}
+
+func (m *menuRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Menu, error) {
+	return findOne[models.Menu](ctx, m.collection, bson.M{"_id": id})
+}
+
+func (m *menuRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	return exists(ctx, m.collection, bson.M{"_id": id})
+}
+
+func (m *menuRepository) Create(ctx context.Context, menu *models.Menu) error {
+	result, err := m.collection.InsertOne(ctx, menu)
+	if err != nil {
+		return err
+	}
+	menu.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (m *menuRepository) Update(ctx context.Context, id primitive.ObjectID, menu models.Menu) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":         menu.Name,
+			"category":     menu.Category,
+			"startDate":    menu.StartDate,
+			"endDate":      menu.EndDate,
+			"servingHours": menu.ServingHours,
+			"updatedOn":    time.Now(),
+		},
+	}
+	return updateOne(ctx, m.collection, bson.M{"_id": id}, update)
+}
@@ -0,0 +1,82 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/database"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewRepositories returns repositories backed by the collections of the
+// restaurant-management database
+func NewRepositories(client *mongo.Client) *repository.Repositories {
+	return &repository.Repositories{
+		Foods:          &foodRepository{collection: database.OpenCollection(client, "food")},
+		Menus:          &menuRepository{collection: database.OpenCollection(client, "menu")},
+		Tables:         &tableRepository{collection: database.OpenCollection(client, "table")},
+		Orders:         &orderRepository{collection: database.OpenCollection(client, "order")},
+		OrderItems:     &orderItemRepository{collection: database.OpenCollection(client, "orderItem")},
+		Invoices:       &invoiceRepository{collection: database.OpenCollection(client, "invoice"), counters: database.OpenCollection(client, "counter")},
+		TaxRates:       &taxRateRepository{collection: database.OpenCollection(client, "taxRate")},
+		Users:          &userRepository{collection: database.OpenCollection(client, "users")},
+		KitchenTickets: &kitchenTicketRepository{collection: database.OpenCollection(client, "kitchenTicket")},
+		Reservations:   &reservationRepository{collection: database.OpenCollection(client, "reservation")},
+		Notes:          &noteRepository{collection: database.OpenCollection(client, "note")},
+		Payments:       &paymentRepository{collection: database.OpenCollection(client, "payment")},
+		CreditNotes:    &creditNoteRepository{collection: database.OpenCollection(client, "creditNote")},
+		Counters:       &counterRepository{collection: database.OpenCollection(client, "counter")},
+		Promotions:     &promotionRepository{collection: database.OpenCollection(client, "promotion")},
+		Ingredients:    &ingredientRepository{collection: database.OpenCollection(client, "ingredient")},
+		Recipes:        &recipeRepository{collection: database.OpenCollection(client, "recipe")},
+		StockMovements: &stockMovementRepository{collection: database.OpenCollection(client, "stockMovement")},
+		Suppliers:      &supplierRepository{collection: database.OpenCollection(client, "supplier")},
+		PurchaseOrders: &purchaseOrderRepository{collection: database.OpenCollection(client, "purchaseOrder")},
+		ZReports:       &zReportRepository{collection: database.OpenCollection(client, "zReport")},
+	}
+}
+
+func findAll[T any](ctx context.Context, collection *mongo.Collection, filter interface{}, opts ...*options.FindOptions) ([]T, error) {
+	cursor, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var documents []T
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func findOne[T any](ctx context.Context, collection *mongo.Collection, filter interface{}) (T, error) {
+	var document T
+	err := collection.FindOne(ctx, filter).Decode(&document)
+	if err == mongo.ErrNoDocuments {
+		return document, repository.ErrNotFound
+	}
+	return document, err
+}
+
+func exists(ctx context.Context, collection *mongo.Collection, filter interface{}) (bool, error) {
+	count, err := collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func updateOne(ctx context.Context, collection *mongo.Collection, filter interface{}, update interface{}) error {
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func page(skip int64, limit int64) *options.FindOptions {
+	return options.Find().SetLimit(limit).SetSkip(skip)
+}
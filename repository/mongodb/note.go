@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type noteRepository struct {
+	collection *mongo.Collection
+}
+
+func (n *noteRepository) ListByOrders(ctx context.Context, orderIDs []primitive.ObjectID) ([]models.Note, error) {
+	query := bson.M{"orderID": bson.M{"$in": orderIDs}}
+	return findAll[models.Note](ctx, n.collection, query, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (n *noteRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Note, error) {
+	return findOne[models.Note](ctx, n.collection, bson.M{"_id": id})
+}
+
+func (n *noteRepository) Create(ctx context.Context, note *models.Note) error {
+	result, err := n.collection.InsertOne(ctx, note)
+	if err != nil {
+		return err
+	}
+	note.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (n *noteRepository) Update(ctx context.Context, id primitive.ObjectID, note models.Note) error {
+	update := bson.M{
+		"$set": bson.M{
+			"text":      note.Text,
+			"updatedOn": note.UpdatedOn,
+		},
+	}
+	return updateOne(ctx, n.collection, bson.M{"_id": id}, update)
+}
+
+func (n *noteRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := n.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type orderRepository struct {
+	collection *mongo.Collection
+}
+
+func (o *orderRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Order, error) {
+	return findAll[models.Order](ctx, o.collection, bson.M{}, page(skip, limit))
+}
+
+func (o *orderRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Order, error) {
+	return findOne[models.Order](ctx, o.collection, bson.M{"_id": id})
+}
+
+func (o *orderRepository) GetMany(ctx context.Context, ids []primitive.ObjectID) ([]models.Order, error) {
+	return findAll[models.Order](ctx, o.collection, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (o *orderRepository) ListOpenByTable(ctx context.Context, tableID primitive.ObjectID) ([]models.Order, error) {
+	query := bson.M{
+		"tableID": tableID,
+		"status":  bson.M{"$nin": []string{models.OrderStatusClosed, models.OrderStatusCancelled, models.OrderStatusVoided}},
+	}
+	return findAll[models.Order](ctx, o.collection, query)
+}
+
+func (o *orderRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	return exists(ctx, o.collection, bson.M{"_id": id})
+}
+
+func (o *orderRepository) Create(ctx context.Context, order *models.Order) error {
+	result, err := o.collection.InsertOne(ctx, order)
+	if err != nil {
+		return err
+	}
+	order.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (o *orderRepository) Update(ctx context.Context, id primitive.ObjectID, order models.Order) error {
+	update := bson.M{
+		"$set": bson.M{
+			"tableID":   order.TableID,
+			"updatedOn": time.Now(),
+		},
+	}
+	return updateOne(ctx, o.collection, bson.M{"_id": id}, update)
+}
+
+func (o *orderRepository) Transition(ctx context.Context, id primitive.ObjectID, change models.OrderStatusChange) error {
+	// Only update if the status is still the one the change was checked against
+	query := bson.M{
+		"_id":    id,
+		"status": change.From,
+	}
+	if change.From == models.OrderStatusPlaced {
+		// Orders created before statuses existed have no status at all
+		query["status"] = bson.M{"$in": []interface{}{models.OrderStatusPlaced, nil}}
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":    change.To,
+			"updatedOn": change.ChangedOn,
+		},
+		"$push": bson.M{
+			"statusHistory": change,
+		},
+	}
+	err := updateOne(ctx, o.collection, query, update)
+	if err == repository.ErrNotFound {
+		return repository.ErrConflict
+	}
+	return err
+}
+
+func (o *orderRepository) AddCoupon(ctx context.Context, id primitive.ObjectID, code string) error {
+	update := bson.M{
+		"$addToSet": bson.M{"couponCodes": code},
+		"$set":      bson.M{"updatedOn": time.Now()},
+	}
+	return updateOne(ctx, o.collection, bson.M{"_id": id}, update)
+}
+
+func (o *orderRepository) RemoveCoupon(ctx context.Context, id primitive.ObjectID, code string) error {
+	update := bson.M{
+		"$pull": bson.M{"couponCodes": code},
+		"$set":  bson.M{"updatedOn": time.Now()},
+	}
+	return updateOne(ctx, o.collection, bson.M{"_id": id}, update)
+}
+
+func (o *orderRepository) MarkBilled(ctx context.Context, id primitive.ObjectID) error {
+	// Only update if no other request has billed the order in the meantime
+	query := bson.M{
+		"_id":    id,
+		"billed": bson.M{"$ne": true},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"billed":    true,
+			"updatedOn": time.Now(),
+		},
+	}
+	err := updateOne(ctx, o.collection, query, update)
+	if err == repository.ErrNotFound {
+		return repository.ErrConflict
+	}
+	return err
+}
+
+func (o *orderRepository) UnmarkBilled(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$unset": bson.M{"billed": ""},
+		"$set":   bson.M{"updatedOn": time.Now()},
+	}
+	return updateOne(ctx, o.collection, bson.M{"_id": id}, update)
+}
@@ -0,0 +1,387 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type orderItemRepository struct {
+	collection *mongo.Collection
+}
+
+func (o *orderItemRepository) List(ctx context.Context, skip int64, limit int64) ([]models.OrderItem, error) {
+	return findAll[models.OrderItem](ctx, o.collection, bson.M{}, page(skip, limit))
+}
+
+func (o *orderItemRepository) Get(ctx context.Context, id primitive.ObjectID) (models.OrderItem, error) {
+	return findOne[models.OrderItem](ctx, o.collection, bson.M{"_id": id})
+}
+
+func (o *orderItemRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItem, error) {
+	return findAll[models.OrderItem](ctx, o.collection, bson.M{"orderID": orderID})
+}
+
+func (o *orderItemRepository) CreateMany(ctx context.Context, orderItems []models.OrderItem) ([]primitive.ObjectID, error) {
+	orderItemsToBeInserted := []interface{}{}
+	for _, orderItem := range orderItems {
+		orderItemsToBeInserted = append(orderItemsToBeInserted, orderItem)
+	}
+	result, err := o.collection.InsertMany(ctx, orderItemsToBeInserted)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{}
+	for _, id := range result.InsertedIDs {
+		ids = append(ids, id.(primitive.ObjectID))
+	}
+	return ids, nil
+}
+
+func (o *orderItemRepository) Update(ctx context.Context, id primitive.ObjectID, orderItem models.OrderItem) error {
+	update := bson.M{"$set": orderItem}
+	unset := bson.M{}
+	if len(orderItem.Modifiers) == 0 {
+		unset["modifiers"] = ""
+	}
+	if orderItem.Note == "" {
+		unset["note"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return updateOne(ctx, o.collection, bson.M{"_id": id}, update)
+}
+
+func (o *orderItemRepository) SummaryByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItemsSummary, error) {
+	match := bson.M{
+		"$match": bson.M{
+			"orderID": orderID,
+		},
+	}
+	lookupFood := bson.M{
+		"$lookup": bson.M{
+			"from":         "food",
+			"localField":   "foodID",
+			"foreignField": "_id",
+			"as":           "food",
+		},
+	}
+	unwindFood := bson.M{
+		"$unwind": bson.M{
+			"path":                       "$food",
+			"preserveNullAndEmptyArrays": true,
+		},
+	}
+
+	lookupOrder := bson.M{
+		"$lookup": bson.M{
+			"from":         "order",
+			"localField":   "orderID",
+			"foreignField": "_id",
+			"as":           "order",
+		},
+	}
+
+	unwindOrder := bson.M{
+		"$unwind": bson.M{
+			"path":                       "$order",
+			"preserveNullAndEmptyArrays": true,
+		},
+	}
+
+	lookupTable := bson.M{
+		"$lookup": bson.M{
+			"from":         "table",
+			"localField":   "order.tableID",
+			"foreignField": "_id",
+			"as":           "table",
+		},
+	}
+
+	unwindTable := bson.M{
+		"$unwind": bson.M{
+			"path":                       "$table",
+			"preserveNullAndEmptyArrays": true,
+		},
+	}
+
+	project := bson.M{
+		"$project": bson.M{
+			"foodID":      1,
+			"foodName":    "$food.name",
+			"foodImage":   "$food.foodImage",
+			"tableNumber": "$table.number",
+			"tableID":     "$order.tableID",
+			"price":       bson.M{"$add": []interface{}{"$unitPrice", bson.M{"$sum": "$modifiers.price"}}},
+			"orderID":     "$order._id",
+			"quantity":    1,
+			"note":        1,
+		},
+	}
+
+	group := bson.M{
+		"$group": bson.M{
+			"_id": bson.M{
+				"orderID":     "$orderID",
+				"tableID":     "$tableID",
+				"tableNumber": "$tableNumber",
+			},
+			"paymentDue": bson.M{
+				"$sum": bson.M{
+					"$multiply": []interface{}{"$price", "$quantity"},
+				},
+			},
+			"totalCount": bson.M{
+				"$sum": 1,
+			},
+			"orderItems": bson.M{
+				"$push": "$$ROOT",
+			},
+		},
+	}
+
+	project2 := bson.M{
+		"$project": bson.M{
+			"_id":         1,
+			"paymentDue":  1,
+			"orderItems":  1,
+			"tableNumber": "$_id.tableNumber",
+			"totalCount":  1,
+		},
+	}
+
+	pipeline := []bson.M{
+		match,
+		lookupFood,
+		unwindFood,
+		lookupOrder,
+		unwindOrder,
+		lookupTable,
+		unwindTable,
+		project,
+		group,
+		project2,
+	}
+	cursor, err := o.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []models.OrderItemsSummary
+	if err = cursor.All(ctx, &summaries); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+func (o *orderItemRepository) SalesByFood(ctx context.Context, from time.Time, to time.Time) ([]models.FoodSales, error) {
+	match := bson.M{
+		"$match": bson.M{
+			"createdOn": bson.M{"$gte": from, "$lt": to},
+		},
+	}
+	lookupOrder := bson.M{
+		"$lookup": bson.M{
+			"from":         "order",
+			"localField":   "orderID",
+			"foreignField": "_id",
+			"as":           "order",
+		},
+	}
+	unwindOrder := bson.M{
+		"$unwind": "$order",
+	}
+	matchSold := bson.M{
+		"$match": bson.M{
+			"order.status": bson.M{"$nin": []string{models.OrderStatusCancelled, models.OrderStatusVoided}},
+		},
+	}
+	group := bson.M{
+		"$group": bson.M{
+			"_id":      "$foodID",
+			"quantity": bson.M{"$sum": "$quantity"},
+			"revenue": bson.M{
+				"$sum": bson.M{
+					"$multiply": []interface{}{
+						bson.M{"$add": []interface{}{"$unitPrice", bson.M{"$sum": "$modifiers.price"}}},
+						"$quantity",
+					},
+				},
+			},
+		},
+	}
+
+	cursor, err := o.collection.Aggregate(ctx, []bson.M{match, lookupOrder, unwindOrder, matchSold, group})
+	if err != nil {
+		return nil, err
+	}
+
+	var sales []models.FoodSales
+	if err = cursor.All(ctx, &sales); err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+func (o *orderItemRepository) SalesByCategory(ctx context.Context, from time.Time, to time.Time) ([]models.CategorySales, error) {
+	match := bson.M{
+		"$match": bson.M{
+			"createdOn": bson.M{"$gte": from, "$lt": to},
+		},
+	}
+	lookupOrder := bson.M{
+		"$lookup": bson.M{
+			"from":         "order",
+			"localField":   "orderID",
+			"foreignField": "_id",
+			"as":           "order",
+		},
+	}
+	unwindOrder := bson.M{
+		"$unwind": "$order",
+	}
+	matchSold := bson.M{
+		"$match": bson.M{
+			"order.status": bson.M{"$nin": []string{models.OrderStatusCancelled, models.OrderStatusVoided}},
+		},
+	}
+	lookupFood := bson.M{
+		"$lookup": bson.M{
+			"from":         "food",
+			"localField":   "foodID",
+			"foreignField": "_id",
+			"as":           "food",
+		},
+	}
+	unwindFood := bson.M{
+		"$unwind": bson.M{
+			"path":                       "$food",
+			"preserveNullAndEmptyArrays": true,
+		},
+	}
+	lookupMenu := bson.M{
+		"$lookup": bson.M{
+			"from":         "menu",
+			"localField":   "food.menuID",
+			"foreignField": "_id",
+			"as":           "menu",
+		},
+	}
+	unwindMenu := bson.M{
+		"$unwind": bson.M{
+			"path":                       "$menu",
+			"preserveNullAndEmptyArrays": true,
+		},
+	}
+	group := bson.M{
+		"$group": bson.M{
+			"_id":      bson.M{"$ifNull": []interface{}{"$menu.category", ""}},
+			"quantity": bson.M{"$sum": "$quantity"},
+			"revenue": bson.M{
+				"$sum": bson.M{
+					"$multiply": []interface{}{
+						bson.M{"$add": []interface{}{"$unitPrice", bson.M{"$sum": "$modifiers.price"}}},
+						"$quantity",
+					},
+				},
+			},
+			"orders": bson.M{"$addToSet": "$orderID"},
+		},
+	}
+	project := bson.M{
+		"$project": bson.M{
+			"_id":      0,
+			"category": "$_id",
+			"quantity": 1,
+			"revenue":  1,
+			"orders":   bson.M{"$size": "$orders"},
+		},
+	}
+
+	pipeline := []bson.M{
+		match,
+		lookupOrder,
+		unwindOrder,
+		matchSold,
+		lookupFood,
+		unwindFood,
+		lookupMenu,
+		unwindMenu,
+		group,
+		project,
+	}
+	cursor, err := o.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var sales []models.CategorySales
+	if err = cursor.All(ctx, &sales); err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+func (o *orderItemRepository) OrderVolume(ctx context.Context, from time.Time, to time.Time) ([]models.OrderVolume, error) {
+	// MongoDB takes the time zone as an offset like +05:30
+	timezone := from.Format("-07:00")
+	match := bson.M{
+		"$match": bson.M{
+			"createdOn": bson.M{"$gte": from, "$lt": to},
+		},
+	}
+	lookupOrder := bson.M{
+		"$lookup": bson.M{
+			"from":         "order",
+			"localField":   "orderID",
+			"foreignField": "_id",
+			"as":           "order",
+		},
+	}
+	unwindOrder := bson.M{
+		"$unwind": "$order",
+	}
+	matchSold := bson.M{
+		"$match": bson.M{
+			"order.status": bson.M{"$nin": []string{models.OrderStatusCancelled, models.OrderStatusVoided}},
+		},
+	}
+	group := bson.M{
+		"$group": bson.M{
+			"_id": bson.M{
+				// $dayOfWeek counts from 1 for Sunday
+				"weekday": bson.M{"$subtract": []interface{}{bson.M{"$dayOfWeek": bson.M{"date": "$createdOn", "timezone": timezone}}, 1}},
+				"hour":    bson.M{"$hour": bson.M{"date": "$createdOn", "timezone": timezone}},
+			},
+			"orders": bson.M{"$addToSet": "$orderID"},
+			"items":  bson.M{"$sum": "$quantity"},
+		},
+	}
+	project := bson.M{
+		"$project": bson.M{
+			"_id":     0,
+			"weekday": "$_id.weekday",
+			"hour":    "$_id.hour",
+			"orders":  bson.M{"$size": "$orders"},
+			"items":   1,
+		},
+	}
+
+	cursor, err := o.collection.Aggregate(ctx, []bson.M{match, lookupOrder, unwindOrder, matchSold, group, project})
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []models.OrderVolume
+	if err = cursor.All(ctx, &volumes); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
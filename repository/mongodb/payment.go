@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type paymentRepository struct {
+	collection *mongo.Collection
+}
+
+func (p *paymentRepository) ListByInvoice(ctx context.Context, invoiceID primitive.ObjectID) ([]models.Payment, error) {
+	return findAll[models.Payment](ctx, p.collection, bson.M{"invoiceID": invoiceID}, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (p *paymentRepository) ListReceived(ctx context.Context, from time.Time, to time.Time) ([]models.Payment, error) {
+	return findAll[models.Payment](ctx, p.collection, bson.M{"createdOn": bson.M{"$gte": from, "$lt": to}})
+}
+
+func (p *paymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	result, err := p.collection.InsertOne(ctx, payment)
+	if err != nil {
+		return err
+	}
+	payment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type promotionRepository struct {
+	collection *mongo.Collection
+}
+
+// underUsageLimit matches promotions without a usage limit or used fewer
+// times than it
+var underUsageLimit = bson.M{
+	"$or": []bson.M{
+		{"usageLimit": bson.M{"$exists": false}},
+		{"$expr": bson.M{"$lt": []interface{}{bson.M{"$ifNull": []interface{}{"$usedCount", 0}}, "$usageLimit"}}},
+	},
+}
+
+func (p *promotionRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Promotion, error) {
+	return findAll[models.Promotion](ctx, p.collection, bson.M{}, page(skip, limit))
+}
+
+func (p *promotionRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Promotion, error) {
+	return findOne[models.Promotion](ctx, p.collection, bson.M{"_id": id})
+}
+
+func (p *promotionRepository) GetByCoupon(ctx context.Context, code string) (models.Promotion, error) {
+	return findOne[models.Promotion](ctx, p.collection, bson.M{"couponCode": code})
+}
+
+func (p *promotionRepository) ListActive(ctx context.Context, from time.Time, to time.Time) ([]models.Promotion, error) {
+	query := bson.M{
+		"$and": []bson.M{
+			{"$or": []bson.M{{"startDate": bson.M{"$exists": false}}, {"startDate": bson.M{"$lte": to}}}},
+			{"$or": []bson.M{{"endDate": bson.M{"$exists": false}}, {"endDate": bson.M{"$gte": from}}}},
+			underUsageLimit,
+		},
+	}
+	return findAll[models.Promotion](ctx, p.collection, query, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (p *promotionRepository) Create(ctx context.Context, promotion *models.Promotion) error {
+	result, err := p.collection.InsertOne(ctx, promotion)
+	if err != nil {
+		return err
+	}
+	promotion.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (p *promotionRepository) Update(ctx context.Context, id primitive.ObjectID, promotion models.Promotion) error {
+	return updateOne(ctx, p.collection, bson.M{"_id": id}, bson.M{"$set": promotion})
+}
+
+func (p *promotionRepository) Redeem(ctx context.Context, id primitive.ObjectID) error {
+	query := bson.M{
+		"_id":  id,
+		"$and": []bson.M{underUsageLimit},
+	}
+	err := updateOne(ctx, p.collection, query, bson.M{"$inc": bson.M{"usedCount": 1}})
+	if err == repository.ErrNotFound {
+		found, err := exists(ctx, p.collection, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if found {
+			return repository.ErrConflict
+		}
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+func (p *promotionRepository) Unredeem(ctx context.Context, id primitive.ObjectID) error {
+	query := bson.M{
+		"_id":       id,
+		"usedCount": bson.M{"$gt": 0},
+	}
+	err := updateOne(ctx, p.collection, query, bson.M{"$inc": bson.M{"usedCount": -1}})
+	if err == repository.ErrNotFound {
+		// The promotion is gone, there is nothing to give back to
+		return nil
+	}
+	return err
+}
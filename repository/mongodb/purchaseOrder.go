@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type purchaseOrderRepository struct {
+	collection *mongo.Collection
+}
+
+func (p *purchaseOrderRepository) List(ctx context.Context, skip int64, limit int64) ([]models.PurchaseOrder, error) {
+	return findAll[models.PurchaseOrder](ctx, p.collection, bson.M{}, page(skip, limit))
+}
+
+func (p *purchaseOrderRepository) Get(ctx context.Context, id primitive.ObjectID) (models.PurchaseOrder, error) {
+	return findOne[models.PurchaseOrder](ctx, p.collection, bson.M{"_id": id})
+}
+
+func (p *purchaseOrderRepository) ListOpen(ctx context.Context) ([]models.PurchaseOrder, error) {
+	query := bson.M{"status": bson.M{"$in": []string{models.PurchaseOrderStatusOrdered, models.PurchaseOrderStatusPartiallyReceived}}}
+	return findAll[models.PurchaseOrder](ctx, p.collection, query)
+}
+
+func (p *purchaseOrderRepository) Create(ctx context.Context, purchaseOrder *models.PurchaseOrder) error {
+	result, err := p.collection.InsertOne(ctx, purchaseOrder)
+	if err != nil {
+		return err
+	}
+	purchaseOrder.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (p *purchaseOrderRepository) Update(ctx context.Context, id primitive.ObjectID, updatedOn time.Time, purchaseOrder models.PurchaseOrder) error {
+	// Only update if nobody changed the purchase order since it was read
+	query := bson.M{
+		"_id":       id,
+		"updatedOn": updatedOn,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":    purchaseOrder.Status,
+			"lines":     purchaseOrder.Lines,
+			"receipts":  purchaseOrder.Receipts,
+			"updatedOn": purchaseOrder.UpdatedOn,
+		},
+	}
+	err := updateOne(ctx, p.collection, query, update)
+	if err == repository.ErrNotFound {
+		found, err := exists(ctx, p.collection, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if found {
+			return repository.ErrConflict
+		}
+		return repository.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type recipeRepository struct {
+	collection *mongo.Collection
+}
+
+func (r *recipeRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Recipe, error) {
+	return findAll[models.Recipe](ctx, r.collection, bson.M{}, page(skip, limit))
+}
+
+func (r *recipeRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Recipe, error) {
+	return findOne[models.Recipe](ctx, r.collection, bson.M{"_id": id})
+}
+
+func (r *recipeRepository) ListByFoods(ctx context.Context, foodIDs []primitive.ObjectID) ([]models.Recipe, error) {
+	return findAll[models.Recipe](ctx, r.collection, bson.M{"foodID": bson.M{"$in": foodIDs}})
+}
+
+func (r *recipeRepository) Create(ctx context.Context, recipe *models.Recipe) error {
+	found, err := exists(ctx, r.collection, bson.M{"foodID": recipe.FoodID})
+	if err != nil {
+		return err
+	}
+	if found {
+		return repository.ErrConflict
+	}
+	result, err := r.collection.InsertOne(ctx, recipe)
+	if err != nil {
+		return err
+	}
+	recipe.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *recipeRepository) Update(ctx context.Context, id primitive.ObjectID, recipe models.Recipe) error {
+	update := bson.M{
+		"$set": bson.M{
+			"ingredients": recipe.Ingredients,
+			"updatedOn":   time.Now(),
+		},
+	}
+	return updateOne(ctx, r.collection, bson.M{"_id": id}, update)
+}
@@ -0,0 +1,82 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type reservationRepository struct {
+	collection *mongo.Collection
+}
+
+func (re *reservationRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Reservation, error) {
+	return findAll[models.Reservation](ctx, re.collection, bson.M{}, page(skip, limit))
+}
+
+func (re *reservationRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Reservation, error) {
+	return findOne[models.Reservation](ctx, re.collection, bson.M{"_id": id})
+}
+
+func (re *reservationRepository) Overlapping(ctx context.Context, from time.Time, to time.Time) ([]models.Reservation, error) {
+	return findAll[models.Reservation](ctx, re.collection, overlapping(from, to))
+}
+
+func (re *reservationRepository) Create(ctx context.Context, reservation *models.Reservation) error {
+	query := overlapping(reservation.At, reservation.EndsAt)
+	query["tableID"] = reservation.TableID
+	taken, err := exists(ctx, re.collection, query)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return repository.ErrConflict
+	}
+
+	if reservation.ID.IsZero() {
+		reservation.ID = primitive.NewObjectID()
+	}
+	_, err = re.collection.InsertOne(ctx, reservation)
+	if err != nil {
+		return err
+	}
+
+	// Two bookings can pass the check above at the same time. Of the
+	// overlapping ones the oldest ID wins and the others take themselves back.
+	query["_id"] = bson.M{"$lt": reservation.ID}
+	taken, err = exists(ctx, re.collection, query)
+	if err != nil {
+		return err
+	}
+	if taken {
+		_, err = re.collection.DeleteOne(ctx, bson.M{"_id": reservation.ID})
+		if err != nil {
+			return err
+		}
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (re *reservationRepository) Cancel(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":    models.ReservationStatusCancelled,
+			"updatedOn": time.Now(),
+		},
+	}
+	return updateOne(ctx, re.collection, bson.M{"_id": id}, update)
+}
+
+func overlapping(from time.Time, to time.Time) bson.M {
+	return bson.M{
+		"status": models.ReservationStatusBooked,
+		"at":     bson.M{"$lt": to},
+		"endsAt": bson.M{"$gt": from},
+	}
+}
@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type stockMovementRepository struct {
+	collection *mongo.Collection
+}
+
+func (s *stockMovementRepository) ListByIngredient(ctx context.Context, ingredientID primitive.ObjectID, skip int64, limit int64) ([]models.StockMovement, error) {
+	return findAll[models.StockMovement](ctx, s.collection, bson.M{"ingredientID": ingredientID}, page(skip, limit).SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (s *stockMovementRepository) ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.StockMovement, error) {
+	return findAll[models.StockMovement](ctx, s.collection, bson.M{"orderID": orderID}, options.Find().SetSort(bson.D{{Key: "createdOn", Value: 1}}))
+}
+
+func (s *stockMovementRepository) Consumption(ctx context.Context, from time.Time, to time.Time) (map[primitive.ObjectID]float64, error) {
+	match := bson.M{
+		"$match": bson.M{
+			"reason":    bson.M{"$in": []string{models.StockMovementSale, models.StockMovementVoid}},
+			"createdOn": bson.M{"$gte": from, "$lt": to},
+		},
+	}
+	group := bson.M{
+		"$group": bson.M{
+			"_id":      "$ingredientID",
+			"quantity": bson.M{"$sum": "$quantity"},
+		},
+	}
+	cursor, err := s.collection.Aggregate(ctx, []bson.M{match, group})
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []struct {
+		IngredientID primitive.ObjectID `bson:"_id"`
+		Quantity     float64            `bson:"quantity"`
+	}
+	if err = cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+
+	// Sales take stock out, so what was used is the opposite of their total
+	consumption := map[primitive.ObjectID]float64{}
+	for _, total := range totals {
+		consumption[total.IngredientID] = -total.Quantity
+	}
+	return consumption, nil
+}
+
+func (s *stockMovementRepository) CreateMany(ctx context.Context, movements []models.StockMovement) error {
+	documents := []interface{}{}
+	for _, movement := range movements {
+		documents = append(documents, movement)
+	}
+	_, err := s.collection.InsertMany(ctx, documents)
+	return err
+}
@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type supplierRepository struct {
+	collection *mongo.Collection
+}
+
+func (s *supplierRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Supplier, error) {
+	return findAll[models.Supplier](ctx, s.collection, bson.M{}, page(skip, limit))
+}
+
+func (s *supplierRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Supplier, error) {
+	return findOne[models.Supplier](ctx, s.collection, bson.M{"_id": id})
+}
+
+func (s *supplierRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	return exists(ctx, s.collection, bson.M{"_id": id})
+}
+
+func (s *supplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	result, err := s.collection.InsertOne(ctx, supplier)
+	if err != nil {
+		return err
+	}
+	supplier.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (s *supplierRepository) Update(ctx context.Context, id primitive.ObjectID, supplier models.Supplier) error {
+	return updateOne(ctx, s.collection, bson.M{"_id": id}, bson.M{"$set": supplier})
+}
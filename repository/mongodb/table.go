@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type tableRepository struct {
+	collection *mongo.Collection
+}
+
+func (t *tableRepository) List(ctx context.Context, skip int64, limit int64) ([]models.Table, error) {
+	return findAll[models.Table](ctx, t.collection, bson.M{}, page(skip, limit))
+}
+
+func (t *tableRepository) Get(ctx context.Context, id primitive.ObjectID) (models.Table, error) {
+	return findOne[models.Table](ctx, t.collection, bson.M{"_id": id})
+}
+
+func (t *tableRepository) Exists(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	return exists(ctx, t.collection, bson.M{"_id": id})
+}
+
+func (t *tableRepository) Create(ctx context.Context, table *models.Table) error {
+	result, err := t.collection.InsertOne(ctx, table)
+	if err != nil {
+		return err
+	}
+	table.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (t *tableRepository) Update(ctx context.Context, id primitive.ObjectID, table models.Table) error {
+	return updateOne(ctx, t.collection, bson.M{"_id": id}, bson.M{"$set": table})
+}
+
+func (t *tableRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":          status,
+			"statusChangedOn": time.Now(),
+		},
+	}
+	return updateOne(ctx, t.collection, bson.M{"_id": id}, update)
+}
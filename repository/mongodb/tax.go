@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type taxRateRepository struct {
+	collection *mongo.Collection
+}
+
+func (t *taxRateRepository) List(ctx context.Context) ([]models.TaxRate, error) {
+	return findAll[models.TaxRate](ctx, t.collection, bson.M{})
+}
+
+func (t *taxRateRepository) Get(ctx context.Context, id primitive.ObjectID) (models.TaxRate, error) {
+	return findOne[models.TaxRate](ctx, t.collection, bson.M{"_id": id})
+}
+
+func (t *taxRateRepository) CategoryTaken(ctx context.Context, category string, exceptID primitive.ObjectID) (bool, error) {
+	return exists(ctx, t.collection, bson.M{"category": category, "_id": bson.M{"$ne": exceptID}})
+}
+
+func (t *taxRateRepository) Create(ctx context.Context, taxRate *models.TaxRate) error {
+	result, err := t.collection.InsertOne(ctx, taxRate)
+	if err != nil {
+		return err
+	}
+	taxRate.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (t *taxRateRepository) Update(ctx context.Context, id primitive.ObjectID, taxRate models.TaxRate) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":       taxRate.Name,
+			"category":   taxRate.Category,
+			"inclusive":  taxRate.Inclusive,
+			"components": taxRate.Components,
+			"updatedOn":  time.Now(),
+		},
+	}
+	return updateOne(ctx, t.collection, bson.M{"_id": id}, update)
+}
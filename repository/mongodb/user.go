@@ -0,0 +1,124 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type userRepository struct {
+	collection *mongo.Collection
+}
+
+func (u *userRepository) List(ctx context.Context, skip int64, limit int64) ([]models.User, error) {
+	return findAll[models.User](ctx, u.collection, bson.M{}, page(skip, limit))
+}
+
+func (u *userRepository) Get(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	return findOne[models.User](ctx, u.collection, bson.M{"_id": id})
+}
+
+func (u *userRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	return findOne[models.User](ctx, u.collection, bson.M{"email": email})
+}
+
+func (u *userRepository) EmailOrPhoneTaken(ctx context.Context, email string, phone string) (bool, error) {
+	query := bson.M{
+		"$or": []bson.M{
+			{
+				"email": email,
+			},
+			{
+				"phone": phone,
+			},
+		},
+	}
+	return exists(ctx, u.collection, query)
+}
+
+func (u *userRepository) Count(ctx context.Context) (int64, error) {
+	return u.collection.CountDocuments(ctx, bson.M{})
+}
+
+func (u *userRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	return u.collection.CountDocuments(ctx, bson.M{"role": role})
+}
+
+func (u *userRepository) Create(ctx context.Context, user *models.User) error {
+	result, err := u.collection.InsertOne(ctx, user)
+	if err != nil {
+		return err
+	}
+	user.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (u *userRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"role":      role,
+			"updatedOn": time.Now(),
+		},
+	}
+	return updateOne(ctx, u.collection, bson.M{"_id": id}, update)
+}
+
+func (u *userRepository) AssignMissingRoles(ctx context.Context, role string) error {
+	query := bson.M{"role": bson.M{"$in": []interface{}{nil, ""}}}
+	update := bson.M{
+		"$set": bson.M{
+			"role":      role,
+			"updatedOn": time.Now(),
+		},
+	}
+	_, err := u.collection.UpdateMany(ctx, query, update)
+	return err
+}
+
+func (u *userRepository) UpdateTokens(ctx context.Context, id primitive.ObjectID, token string, refreshToken string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"token":        token,
+			"refreshToken": refreshToken,
+			"updatedOn":    time.Now(),
+		},
+	}
+	return updateOne(ctx, u.collection, bson.M{"_id": id}, update)
+}
+
+func (u *userRepository) RotateTokens(ctx context.Context, id primitive.ObjectID, usedRefreshToken string, token string, refreshToken string) error {
+	// Checking the used token in the same write keeps two refreshes with
+	// it from both succeeding
+	query := bson.M{
+		"_id":          id,
+		"refreshToken": usedRefreshToken,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"token":        token,
+			"refreshToken": refreshToken,
+			"updatedOn":    time.Now(),
+		},
+	}
+	return updateOne(ctx, u.collection, query, update)
+}
+
+func (u *userRepository) RevokeTokens(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$inc": bson.M{
+			"tokenVersion": 1,
+		},
+		"$unset": bson.M{
+			"token":        "",
+			"refreshToken": "",
+		},
+		"$set": bson.M{
+			"updatedOn": time.Now(),
+		},
+	}
+	return updateOne(ctx, u.collection, bson.M{"_id": id}, update)
+}
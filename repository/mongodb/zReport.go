@@ -0,0 +1,40 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type zReportRepository struct {
+	collection *mongo.Collection
+}
+
+func (z *zReportRepository) List(ctx context.Context, skip int64, limit int64) ([]models.ZReport, error) {
+	return findAll[models.ZReport](ctx, z.collection, bson.M{}, page(skip, limit).SetSort(bson.D{{Key: "date", Value: -1}}))
+}
+
+func (z *zReportRepository) GetByDate(ctx context.Context, date string) (models.ZReport, error) {
+	return findOne[models.ZReport](ctx, z.collection, bson.M{"date": date})
+}
+
+// Create only inserts when no report of the day exists, so two closes of the
+// same day cannot both succeed
+func (z *zReportRepository) Create(ctx context.Context, zReport *models.ZReport) error {
+	if zReport.ID.IsZero() {
+		zReport.ID = primitive.NewObjectID()
+	}
+	result, err := z.collection.UpdateOne(ctx, bson.M{"date": zReport.Date}, bson.M{"$setOnInsert": zReport}, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+	if result.UpsertedCount == 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NoteRepository interface {
+	// ListByOrders returns the notes of the given orders, oldest first
+	ListByOrders(ctx context.Context, orderIDs []primitive.ObjectID) ([]models.Note, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Note, error)
+	Create(ctx context.Context, note *models.Note) error
+	// Update replaces the text of the note
+	Update(ctx context.Context, id primitive.ObjectID, note models.Note) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Order, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Order, error)
+	GetMany(ctx context.Context, ids []primitive.ObjectID) ([]models.Order, error)
+	// ListOpenByTable returns the table's orders that are not closed,
+	// cancelled or voided
+	ListOpenByTable(ctx context.Context, tableID primitive.ObjectID) ([]models.Order, error)
+	Exists(ctx context.Context, id primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, order *models.Order) error
+	// Update moves the order to another table
+	Update(ctx context.Context, id primitive.ObjectID, order models.Order) error
+	// Transition records the status change, returning ErrConflict if the
+	// order's status is no longer change.From
+	Transition(ctx context.Context, id primitive.ObjectID, change models.OrderStatusChange) error
+	// AddCoupon enters a coupon code on the order, once
+	AddCoupon(ctx context.Context, id primitive.ObjectID, code string) error
+	RemoveCoupon(ctx context.Context, id primitive.ObjectID, code string) error
+	// MarkBilled marks the order billed, returning ErrConflict if it
+	// already is
+	MarkBilled(ctx context.Context, id primitive.ObjectID) error
+	// UnmarkBilled takes the mark off again when the bill is not issued
+	UnmarkBilled(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrderItemRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.OrderItem, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.OrderItem, error)
+	ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItem, error)
+	// CreateMany inserts the items and returns their new IDs in the same order
+	CreateMany(ctx context.Context, orderItems []models.OrderItem) ([]primitive.ObjectID, error)
+	// Update sets the non-empty fields of orderItem, and its modifiers and
+	// note even when empty so they can be taken off
+	Update(ctx context.Context, id primitive.ObjectID, orderItem models.OrderItem) error
+	// SummaryByOrder joins the order's items with their food and table
+	SummaryByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.OrderItemsSummary, error)
+	// SalesByFood adds up, per food, the items ordered from from until to at
+	// the prices they were ordered at. Items of cancelled and voided orders
+	// were never sold and are left out.
+	SalesByFood(ctx context.Context, from time.Time, to time.Time) ([]models.FoodSales, error)
+	// SalesByCategory joins the items sold from from until to with their food
+	// and its menu and adds them up per menu category. Foods on no menu
+	// have no category.
+	SalesByCategory(ctx context.Context, from time.Time, to time.Time) ([]models.CategorySales, error)
+	// OrderVolume counts the items sold from from until to, and the orders
+	// they were on, per weekday and hour in the time zone of from
+	OrderVolume(ctx context.Context, from time.Time, to time.Time) ([]models.OrderVolume, error)
+}
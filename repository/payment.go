@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PaymentRepository interface {
+	// ListByInvoice returns the payments of the invoice, oldest first
+	ListByInvoice(ctx context.Context, invoiceID primitive.ObjectID) ([]models.Payment, error)
+	// ListReceived returns the payments received from from until to
+	ListReceived(ctx context.Context, from time.Time, to time.Time) ([]models.Payment, error)
+	Create(ctx context.Context, payment *models.Payment) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromotionRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Promotion, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Promotion, error)
+	GetByCoupon(ctx context.Context, code string) (models.Promotion, error)
+	// ListActive returns the promotions running at any time from from to to
+	// that are not used up, oldest first
+	ListActive(ctx context.Context, from time.Time, to time.Time) ([]models.Promotion, error)
+	Create(ctx context.Context, promotion *models.Promotion) error
+	// Update sets the non-empty fields of promotion
+	Update(ctx context.Context, id primitive.ObjectID, promotion models.Promotion) error
+	// Redeem counts one use of the promotion, returning ErrConflict if it
+	// has reached its usage limit
+	Redeem(ctx context.Context, id primitive.ObjectID) error
+	// Unredeem gives back a use counted by Redeem for a bill that was not
+	// issued after all
+	Unredeem(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PurchaseOrderRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.PurchaseOrder, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.PurchaseOrder, error)
+	// ListOpen returns the purchase orders still waiting for goods
+	ListOpen(ctx context.Context) ([]models.PurchaseOrder, error)
+	Create(ctx context.Context, purchaseOrder *models.PurchaseOrder) error
+	// Update stores the status, lines and receipts of purchaseOrder,
+	// returning ErrConflict if the purchase order was changed after
+	// updatedOn
+	Update(ctx context.Context, id primitive.ObjectID, updatedOn time.Time, purchaseOrder models.PurchaseOrder) error
+}
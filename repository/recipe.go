@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecipeRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Recipe, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Recipe, error)
+	ListByFoods(ctx context.Context, foodIDs []primitive.ObjectID) ([]models.Recipe, error)
+	// Create returns ErrConflict if the food already has a recipe
+	Create(ctx context.Context, recipe *models.Recipe) error
+	// Update replaces the ingredients of the recipe
+	Update(ctx context.Context, id primitive.ObjectID, recipe models.Recipe) error
+}
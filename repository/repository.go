@@ -0,0 +1,36 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when no document matches the given ID or filter
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a conditional write lost to a concurrent change
+	ErrConflict = errors.New("conflict")
+)
+
+// Repositories groups the storage of every entity. Handlers only talk to
+// storage through these interfaces, so the backend can be MongoDB or memory.
+type Repositories struct {
+	Foods          FoodRepository
+	Menus          MenuRepository
+	Tables         TableRepository
+	Orders         OrderRepository
+	OrderItems     OrderItemRepository
+	Invoices       InvoiceRepository
+	TaxRates       TaxRateRepository
+	Users          UserRepository
+	KitchenTickets KitchenTicketRepository
+	Reservations   ReservationRepository
+	Notes          NoteRepository
+	Payments       PaymentRepository
+	CreditNotes    CreditNoteRepository
+	Counters       CounterRepository
+	Promotions     PromotionRepository
+	Ingredients    IngredientRepository
+	Recipes        RecipeRepository
+	StockMovements StockMovementRepository
+	Suppliers      SupplierRepository
+	PurchaseOrders PurchaseOrderRepository
+	ZReports       ZReportRepository
+}
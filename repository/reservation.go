@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReservationRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Reservation, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Reservation, error)
+	// Overlapping returns the booked reservations that hold any table
+	// between from and to
+	Overlapping(ctx context.Context, from time.Time, to time.Time) ([]models.Reservation, error)
+	// Create books the table, returning ErrConflict if another booked
+	// reservation holds it for any part of the same time
+	Create(ctx context.Context, reservation *models.Reservation) error
+	Cancel(ctx context.Context, id primitive.ObjectID) error
+}
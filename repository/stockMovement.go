@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StockMovementRepository interface {
+	// ListByIngredient returns the movements of the ingredient, oldest first
+	ListByIngredient(ctx context.Context, ingredientID primitive.ObjectID, skip int64, limit int64) ([]models.StockMovement, error)
+	// ListByOrder returns the movements recorded against the order, oldest first
+	ListByOrder(ctx context.Context, orderID primitive.ObjectID) ([]models.StockMovement, error)
+	// Consumption returns how much of each ingredient was sold from from
+	// until to, net of what voided orders put back
+	Consumption(ctx context.Context, from time.Time, to time.Time) (map[primitive.ObjectID]float64, error)
+	CreateMany(ctx context.Context, movements []models.StockMovement) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SupplierRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Supplier, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Supplier, error)
+	Exists(ctx context.Context, id primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, supplier *models.Supplier) error
+	// Update sets the non-empty fields of supplier
+	Update(ctx context.Context, id primitive.ObjectID, supplier models.Supplier) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TableRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.Table, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.Table, error)
+	Exists(ctx context.Context, id primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, table *models.Table) error
+	// Update sets the non-empty fields of table
+	Update(ctx context.Context, id primitive.ObjectID, table models.Table) error
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, status string) error
+}
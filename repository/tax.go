@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaxRateRepository interface {
+	List(ctx context.Context) ([]models.TaxRate, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.TaxRate, error)
+	// CategoryTaken reports whether a rate other than exceptID uses the category
+	CategoryTaken(ctx context.Context, category string, exceptID primitive.ObjectID) (bool, error)
+	Create(ctx context.Context, taxRate *models.TaxRate) error
+	Update(ctx context.Context, id primitive.ObjectID, taxRate models.TaxRate) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserRepository interface {
+	List(ctx context.Context, skip int64, limit int64) ([]models.User, error)
+	Get(ctx context.Context, id primitive.ObjectID) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	EmailOrPhoneTaken(ctx context.Context, email string, phone string) (bool, error)
+	// Count returns how many users have signed up
+	Count(ctx context.Context) (int64, error)
+	// CountByRole returns how many users have the role
+	CountByRole(ctx context.Context, role string) (int64, error)
+	Create(ctx context.Context, user *models.User) error
+	UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error
+	// AssignMissingRoles gives the role to every user that has none
+	AssignMissingRoles(ctx context.Context, role string) error
+	UpdateTokens(ctx context.Context, id primitive.ObjectID, token string, refreshToken string) error
+	// RotateTokens replaces the tokens only while usedRefreshToken is still
+	// the user's latest refresh token, returning ErrNotFound otherwise
+	RotateTokens(ctx context.Context, id primitive.ObjectID, usedRefreshToken string, token string, refreshToken string) error
+	// RevokeTokens bumps the token version so every token issued before is rejected
+	RevokeTokens(ctx context.Context, id primitive.ObjectID) error
+}
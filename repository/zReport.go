@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+)
+
+type ZReportRepository interface {
+	// List returns the Z-reports, the latest day first
+	List(ctx context.Context, skip int64, limit int64) ([]models.ZReport, error)
+	// GetByDate returns the Z-report of a day, like 2026-10-18
+	GetByDate(ctx context.Context, date string) (models.ZReport, error)
+	// Create stores the Z-report, returning ErrConflict if its day is
+	// already closed
+	Create(ctx context.Context, zReport *models.ZReport) error
+}
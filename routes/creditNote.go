@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func CreditNoteRoutes(router *mux.Router) {
+	creditNoteGroup := router.PathPrefix("/creditNotes").Subrouter()
+	creditNoteGroup.Use(middleware.Authentication)
+	creditNoteGroup.HandleFunc("", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetCreditNotes)).Methods("GET")
+	creditNoteGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetCreditNote)).Methods("GET")
+	creditNoteGroup.HandleFunc("", middleware.Authorize(helpers.PermissionRefundInvoice, controllers.CreateCreditNote)).Methods("POST")
+	creditNoteGroup.HandleFunc("/{id}/approve", middleware.Authorize(helpers.PermissionApproveRefund, controllers.ApproveCreditNote)).Methods("POST")
+	creditNoteGroup.HandleFunc("/{id}/reject", middleware.Authorize(helpers.PermissionApproveRefund, controllers.RejectCreditNote)).Methods("POST")
+}
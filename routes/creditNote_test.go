@@ -0,0 +1,136 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) raiseCreditNote(token string, creditNote models.CreditNote) models.CreditNote {
+	s.t.Helper()
+	if creditNote.Reason == "" {
+		creditNote.Reason = models.RefundReasonQuality
+	}
+	recorder := s.do(http.MethodPost, "/creditNotes", token, creditNote)
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return decode[models.CreditNote](s.t, recorder)
+}
+
+func (s *testServer) approveCreditNote(token string, id primitive.ObjectID) {
+	s.t.Helper()
+	expectStatus(s.t, s.do(http.MethodPost, "/creditNotes/"+id.Hex()+"/approve", token, nil), http.StatusOK)
+}
+
+func TestCreditNotesAdjustInvoice(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	manager := s.login(models.RoleManager)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "CGST", Rate: 2.5}, models.TaxComponent{Name: "SGST", Rate: 2.5})
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	naan := s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+	invoice := s.createInvoice(cashier, order.ID)
+
+	partial := s.raiseCreditNote(cashier, models.CreditNote{
+		InvoiceID: invoice.ID,
+		Reason:    models.RefundReasonWrongItem,
+		Lines:     []models.CreditNoteLine{{OrderItemID: naan.ID, Quantity: 1}},
+	})
+	if partial.Number != "CN/000001" || partial.Status != models.CreditNoteStatusPending || partial.Total != 42 || len(partial.Taxes) != 2 {
+		t.Fatalf("unexpected credit note %+v", partial)
+	}
+	pending, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if pending.Balance != 388.5 {
+		t.Fatalf("a pending credit note must not change the invoice, got balance %v", pending.Balance)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes/"+partial.ID.Hex()+"/approve", cashier, nil), http.StatusForbidden)
+	s.approveCreditNote(manager, partial.ID)
+	credited, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if credited.Credited != 42 || credited.Balance != 346.5 || credited.PaymentStatus != models.PaymentStatusUnpaid {
+		t.Fatalf("unexpected invoice %+v", credited)
+	}
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes/"+partial.ID.Hex()+"/approve", manager, nil), http.StatusConflict)
+
+	rest := s.raiseCreditNote(cashier, models.CreditNote{InvoiceID: invoice.ID})
+	if rest.Number != "CN/000002" || rest.Total != 346.5 || len(rest.Lines) != 2 || rest.Lines[1].Quantity != 2 {
+		t.Fatalf("unexpected credit note %+v", rest)
+	}
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes", cashier, models.CreditNote{InvoiceID: invoice.ID, Reason: models.RefundReasonOther}), http.StatusConflict)
+
+	s.approveCreditNote(manager, rest.ID)
+	cleared, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if cleared.Credited != 388.5 || cleared.Balance != 0 || cleared.PaymentStatus != models.PaymentStatusPaid {
+		t.Fatalf("a fully credited invoice owes nothing, got %+v", cleared)
+	}
+
+	recorder := s.do(http.MethodGet, "/creditNotes?invoiceID="+invoice.ID.Hex(), cashier, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if creditNotes := decode[[]models.CreditNote](t, recorder); len(creditNotes) != 2 {
+		t.Fatalf("expected 2 credit notes, got %d", len(creditNotes))
+	}
+}
+
+func TestRefundAfterPayment(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	manager := s.login(models.RoleManager)
+	table := s.createTable(3, 4)
+	order := s.createOrder(table.ID, models.OrderStatusServed)
+	naan := s.createOrderItem(order.ID, s.createFood("Naan", 40), 2)
+	invoice := s.createInvoice(cashier, order.ID)
+
+	// Nothing can be handed back before it is paid
+	unpaid := models.CreditNote{InvoiceID: invoice.ID, Reason: models.RefundReasonQuality, RefundMethod: models.PaymentMethodCash}
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes", cashier, unpaid), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes", s.login(models.RoleWaiter), unpaid), http.StatusForbidden)
+	s.pay(cashier, invoice.ID, models.PaymentMethodCash, 80)
+
+	// Someone else sits down before the refund goes through
+	s.repos.Tables.UpdateStatus(context.Background(), table.ID, models.TableStatusSeated)
+	refund := s.raiseCreditNote(cashier, models.CreditNote{
+		InvoiceID:    invoice.ID,
+		Lines:        []models.CreditNoteLine{{OrderItemID: naan.ID, Quantity: 1}},
+		RefundMethod: models.PaymentMethodCash,
+	})
+	s.approveCreditNote(manager, refund.ID)
+	refunded, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if refunded.Refunded != 40 || refunded.AmountPaid != 40 || refunded.Balance != 0 || refunded.PaymentStatus != models.PaymentStatusPaid {
+		t.Fatalf("unexpected invoice %+v", refunded)
+	}
+	if status := s.tableStatus(table.ID); status != models.TableStatusSeated {
+		t.Fatalf("a refund must not free the table again, got %q", status)
+	}
+}
+
+func TestRejectedCreditNoteReleasesQuantity(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	manager := s.login(models.RoleManager)
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	thali := s.createOrderItem(order.ID, s.createFood("Thali", 100), 3)
+	invoice := s.createInvoice(cashier, order.ID)
+	one := []models.CreditNoteLine{{OrderItemID: thali.ID, Quantity: 1}}
+
+	rejected := s.raiseCreditNote(cashier, models.CreditNote{InvoiceID: invoice.ID, Lines: []models.CreditNoteLine{{OrderItemID: thali.ID, Quantity: 3}}})
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes", cashier, models.CreditNote{InvoiceID: invoice.ID, Reason: models.RefundReasonQuality, Lines: one}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes/"+rejected.ID.Hex()+"/reject", manager, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes/"+rejected.ID.Hex()+"/approve", manager, nil), http.StatusConflict)
+
+	// Three thirds of a line add up to the whole line
+	total := 0.0
+	for i := 0; i < 3; i++ {
+		creditNote := s.raiseCreditNote(cashier, models.CreditNote{InvoiceID: invoice.ID, Lines: []models.CreditNoteLine{{OrderItemID: thali.ID, Quantity: 1}}})
+		total += creditNote.Lines[0].Amount
+	}
+	if total != 300 {
+		t.Fatalf("expected credits of 300, got %v", total)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes", cashier, models.CreditNote{InvoiceID: invoice.ID, Reason: "changed-mind"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/creditNotes", cashier, models.CreditNote{InvoiceID: primitive.NewObjectID(), Reason: models.RefundReasonOther}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/creditNotes/"+primitive.NewObjectID().Hex(), cashier, nil), http.StatusNotFound)
+}
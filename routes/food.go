@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -11,6 +12,7 @@ func FoodRoutes(router *mux.Router) {
 	foodGroup.Use(middleware.Authentication)
 	foodGroup.HandleFunc("", controllers.GetFoods).Methods("GET")
 	foodGroup.HandleFunc("/{id}", controllers.GetFood).Methods("GET")
-	foodGroup.HandleFunc("", controllers.CreateFood).Methods("POST")
-	foodGroup.HandleFunc("/{id}", controllers.UpdateFood).Methods("PATCH")
+	foodGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageMenu, controllers.CreateFood)).Methods("POST")
+	foodGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageMenu, controllers.UpdateFood)).Methods("PATCH")
+	foodGroup.HandleFunc("/{id}/availability", middleware.Authorize(helpers.PermissionCookOrders, controllers.SetFoodAvailability)).Methods("POST")
 }
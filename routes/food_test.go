@@ -0,0 +1,259 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetFoods(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	s.createFood("Paneer Tikka", 250)
+	s.createFood("Dal Makhani", 220)
+	s.createFood("Jeera Rice", 150)
+
+	recorder := s.do(http.MethodGet, "/foods", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if foods := decode[[]models.Food](t, recorder); len(foods) != 3 {
+		t.Fatalf("expected 3 foods, got %d", len(foods))
+	}
+
+	recorder = s.do(http.MethodGet, "/foods?limit=1&skip=1", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	foods := decode[[]models.Food](t, recorder)
+	if len(foods) != 1 || foods[0].Name != "Dal Makhani" {
+		t.Fatalf("expected only Dal Makhani, got %+v", foods)
+	}
+}
+
+func TestGetFood(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	food := s.createFood("Paneer Tikka", 250)
+
+	recorder := s.do(http.MethodGet, "/foods/"+food.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.Food](t, recorder); got.Name != "Paneer Tikka" || got.Price != 250 {
+		t.Fatalf("unexpected food %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/foods/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/foods/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestCreateFood(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+	menu := s.createMenu()
+
+	body := models.Food{Name: "Paneer Tikka", Price: 249.999, FoodImage: "paneer.png", MenuID: menu.ID}
+	recorder := s.do(http.MethodPost, "/foods", token, body)
+	expectStatus(t, recorder, http.StatusCreated)
+
+	food, err := s.repos.Foods.Get(context.Background(), insertedID(t, recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if food.Price != 250 {
+		t.Fatalf("expected price rounded to 250, got %v", food.Price)
+	}
+
+	body.MenuID = primitive.NewObjectID()
+	expectStatus(t, s.do(http.MethodPost, "/foods", token, body), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/foods", token, models.Food{Name: "X"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/foods", s.login(models.RoleWaiter), body), http.StatusForbidden)
+}
+
+func TestUpdateFood(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+	food := s.createFood("Paneer Tikka", 250)
+
+	body := models.Food{Name: "Paneer Tikka", Price: 275, FoodImage: "paneer.png", MenuID: food.MenuID}
+	expectStatus(t, s.do(http.MethodPatch, "/foods/"+food.ID.Hex(), token, body), http.StatusOK)
+
+	updated, _ := s.repos.Foods.Get(context.Background(), food.ID)
+	if updated.Price != 275 {
+		t.Fatalf("expected price 275, got %v", updated.Price)
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, "/foods/"+primitive.NewObjectID().Hex(), token, body), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, "/foods/"+food.ID.Hex(), s.login(models.RoleChef), body), http.StatusForbidden)
+}
+
+func TestFoodAvailability(t *testing.T) {
+	s := newTestServer(t)
+	chef := s.login(models.RoleChef)
+	waiter := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	biryani := s.createFood("Biryani", 300)
+	naan := s.createFood("Naan", 40)
+	order := func(items ...models.OrderItem) *httptest.ResponseRecorder {
+		return s.do(http.MethodPost, "/orderItems", waiter, controllers.OrderItemPack{TableID: table.ID, OrderItems: items})
+	}
+	portions := func(food models.Food) int {
+		stored, _ := s.repos.Foods.Get(context.Background(), food.ID)
+		if stored.RemainingPortions == nil {
+			t.Fatalf("expected %s to count portions", food.Name)
+		}
+		return *stored.RemainingPortions
+	}
+
+	three := 3
+	expectStatus(t, s.do(http.MethodPost, "/foods/"+biryani.ID.Hex()+"/availability", waiter, controllers.FoodAvailability{RemainingPortions: &three}), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodPost, "/foods/"+biryani.ID.Hex()+"/availability", chef, controllers.FoodAvailability{RemainingPortions: &three}), http.StatusOK)
+
+	expectStatus(t, order(models.OrderItem{FoodID: biryani.ID, Quantity: 2, UnitPrice: 300}), http.StatusCreated)
+	if left := portions(biryani); left != 1 {
+		t.Fatalf("expected 1 portion left, got %d", left)
+	}
+
+	recorder := order(models.OrderItem{FoodID: biryani.ID, Quantity: 1, UnitPrice: 300}, models.OrderItem{FoodID: biryani.ID, Quantity: 1, UnitPrice: 300})
+	expectStatus(t, recorder, http.StatusConflict)
+	if message := decode[string](t, recorder); message != "Only 1 Biryani left" {
+		t.Fatalf("unexpected message %q", message)
+	}
+
+	expectStatus(t, order(models.OrderItem{FoodID: biryani.ID, Quantity: 1, UnitPrice: 300}), http.StatusCreated)
+	recorder = order(models.OrderItem{FoodID: biryani.ID, Quantity: 1, UnitPrice: 300})
+	expectStatus(t, recorder, http.StatusConflict)
+	if message := decode[string](t, recorder); message != "Biryani is sold out" {
+		t.Fatalf("unexpected message %q", message)
+	}
+
+	// 86-ing a dish stops it being ordered and gives back nothing taken for
+	// the rest of the request
+	five := 5
+	s.do(http.MethodPost, "/foods/"+biryani.ID.Hex()+"/availability", chef, controllers.FoodAvailability{RemainingPortions: &five})
+	expectStatus(t, s.do(http.MethodPost, "/foods/"+naan.ID.Hex()+"/availability", chef, controllers.FoodAvailability{SoldOut: true}), http.StatusOK)
+	expectStatus(t, order(models.OrderItem{FoodID: biryani.ID, Quantity: 2, UnitPrice: 300}, models.OrderItem{FoodID: naan.ID, Quantity: 1, UnitPrice: 40}), http.StatusConflict)
+	if left := portions(biryani); left != 5 {
+		t.Fatalf("a rejected order must not take portions, got %d left", left)
+	}
+
+	active := decode[[]controllers.ActiveMenu](t, s.do(http.MethodGet, "/menus/active", waiter, nil))
+	for _, menu := range active {
+		for _, food := range menu.Foods {
+			if food.ID == naan.ID {
+				t.Fatal("sold out foods must not be listed as orderable")
+			}
+		}
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/foods/"+naan.ID.Hex()+"/availability", chef, controllers.FoodAvailability{}), http.StatusOK)
+	expectStatus(t, order(models.OrderItem{FoodID: naan.ID, Quantity: 1, UnitPrice: 40}), http.StatusCreated)
+
+	// Cancelled and voided orders give their portions back
+	manager := s.login(models.RoleManager)
+	for _, transition := range []string{"cancel", "void"} {
+		recorder = order(models.OrderItem{FoodID: biryani.ID, Quantity: 2, UnitPrice: 300})
+		expectStatus(t, recorder, http.StatusCreated)
+		if left := portions(biryani); left != 3 {
+			t.Fatalf("expected 3 portions left, got %d", left)
+		}
+		orderItem, _ := s.repos.OrderItems.Get(context.Background(), decode[[]primitive.ObjectID](t, recorder)[0])
+		if transition == "void" {
+			expectStatus(t, s.do(http.MethodPost, "/orders/"+orderItem.OrderID.Hex()+"/kitchen", waiter, nil), http.StatusOK)
+		}
+		expectStatus(t, s.do(http.MethodPost, "/orders/"+orderItem.OrderID.Hex()+"/"+transition, manager, nil), http.StatusOK)
+		if left := portions(biryani); left != 5 {
+			t.Fatalf("expected the portions back after %s, got %d left", transition, left)
+		}
+	}
+
+	// Changing an item takes or gives back the difference
+	ids := decode[[]primitive.ObjectID](t, order(models.OrderItem{FoodID: biryani.ID, Quantity: 2, UnitPrice: 300}))
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	update := func(food models.Food, quantity int) *httptest.ResponseRecorder {
+		body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: food.ID, Quantity: quantity, UnitPrice: food.Price}
+		return s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), waiter, body)
+	}
+	expectStatus(t, update(biryani, 4), http.StatusOK)
+	if left := portions(biryani); left != 1 {
+		t.Fatalf("expected 1 portion left, got %d", left)
+	}
+	recorder = update(biryani, 6)
+	expectStatus(t, recorder, http.StatusConflict)
+	if message := decode[string](t, recorder); message != "Only 1 Biryani left" {
+		t.Fatalf("unexpected message %q", message)
+	}
+	expectStatus(t, update(biryani, 1), http.StatusOK)
+	if left := portions(biryani); left != 4 {
+		t.Fatalf("expected 4 portions left, got %d", left)
+	}
+	expectStatus(t, update(naan, 1), http.StatusOK)
+	if left := portions(biryani); left != 5 {
+		t.Fatalf("expected the portions back when the item changes food, got %d left", left)
+	}
+	expectStatus(t, s.do(http.MethodPost, "/foods/"+biryani.ID.Hex()+"/availability", chef, controllers.FoodAvailability{SoldOut: true}), http.StatusOK)
+	expectStatus(t, update(biryani, 1), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/foods/"+primitive.NewObjectID().Hex()+"/availability", chef, controllers.FoodAvailability{}), http.StatusNotFound)
+}
+
+func TestFoodModifiers(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	waiter := s.login(models.RoleWaiter)
+	table := s.createTable(5, 2)
+
+	body := models.Food{Name: "Margherita", Price: 300, FoodImage: "pizza.png", MenuID: s.createMenu().ID, ModifierGroups: []models.ModifierGroup{
+		{Name: "Size", Min: 1, Max: 1, Options: []models.ModifierOption{{Name: "Small"}, {Name: "Large", Price: 80}}},
+		{Name: "Extras", Max: 2, Options: []models.ModifierOption{{Name: "Extra cheese", Price: 40}, {Name: "Jalapeno", Price: 20}, {Name: "Olives", Price: 30}}},
+	}}
+	recorder := s.do(http.MethodPost, "/foods", manager, body)
+	expectStatus(t, recorder, http.StatusCreated)
+	pizza, err := s.repos.Foods.Get(context.Background(), insertedID(t, recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broken := body
+	broken.ModifierGroups = []models.ModifierGroup{{Name: "Size", Min: 2, Max: 1, Options: body.ModifierGroups[0].Options}}
+	expectStatus(t, s.do(http.MethodPost, "/foods", manager, broken), http.StatusBadRequest)
+
+	order := func(modifiers ...models.OrderItemModifier) *httptest.ResponseRecorder {
+		item := models.OrderItem{FoodID: pizza.ID, Quantity: 2, UnitPrice: 300, Modifiers: modifiers}
+		return s.do(http.MethodPost, "/orderItems", waiter, controllers.OrderItemPack{TableID: table.ID, OrderItems: []models.OrderItem{item}})
+	}
+	recorder = order()
+	expectStatus(t, recorder, http.StatusBadRequest)
+	if message := decode[string](t, recorder); message != "Choose at least 1 Size for Margherita" {
+		t.Fatalf("unexpected message %q", message)
+	}
+	expectStatus(t, order(models.OrderItemModifier{Group: "Size", Option: "Medium"}), http.StatusBadRequest)
+	expectStatus(t, order(models.OrderItemModifier{Group: "Size", Option: "Small"}, models.OrderItemModifier{Group: "Size", Option: "Large"}), http.StatusBadRequest)
+	expectStatus(t, order(
+		models.OrderItemModifier{Group: "Size", Option: "Small"},
+		models.OrderItemModifier{Group: "Extras", Option: "Extra cheese"},
+		models.OrderItemModifier{Group: "Extras", Option: "Jalapeno"},
+		models.OrderItemModifier{Group: "Extras", Option: "Olives"},
+	), http.StatusBadRequest)
+
+	// Prices come from the food, whatever the client sends
+	recorder = order(models.OrderItemModifier{Group: "size", Option: "large"}, models.OrderItemModifier{Group: "Extras", Option: "Extra cheese", Price: 1})
+	expectStatus(t, recorder, http.StatusCreated)
+	ids := decode[[]primitive.ObjectID](t, recorder)
+	orderItem, err := s.repos.OrderItems.Get(context.Background(), ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orderItem.Modifiers) != 2 || orderItem.Modifiers[0].Group != "Size" || orderItem.Modifiers[0].Option != "Large" || orderItem.Modifiers[1].Price != 40 {
+		t.Fatalf("unexpected modifiers %+v", orderItem.Modifiers)
+	}
+
+	tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", s.login(models.RoleChef), nil))
+	if len(tickets) != 1 || len(tickets[0].Modifiers) != 2 || tickets[0].Modifiers[0] != "Size: Large" {
+		t.Fatalf("unexpected kitchen tickets %+v", tickets)
+	}
+
+	invoice := s.createInvoice(s.login(models.RoleCashier), orderItem.OrderID)
+	if len(invoice.Lines) != 1 || invoice.Lines[0].UnitPrice != 420 || invoice.Lines[0].Amount != 840 || invoice.SubTotal != 840 {
+		t.Fatalf("expected 2 x 420, got %+v", invoice.Lines)
+	}
+}
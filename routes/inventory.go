@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func InventoryRoutes(router *mux.Router) {
+	inventoryGroup := router.PathPrefix("/inventory").Subrouter()
+	inventoryGroup.Use(middleware.Authentication)
+	inventoryGroup.HandleFunc("/ingredients", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetIngredients)).Methods("GET")
+	inventoryGroup.HandleFunc("/ingredients/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetIngredient)).Methods("GET")
+	inventoryGroup.HandleFunc("/ingredients", middleware.Authorize(helpers.PermissionManageInventory, controllers.CreateIngredient)).Methods("POST")
+	inventoryGroup.HandleFunc("/ingredients/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.UpdateIngredient)).Methods("PATCH")
+	inventoryGroup.HandleFunc("/ingredients/{id}/movements", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetStockMovements)).Methods("GET")
+	inventoryGroup.HandleFunc("/ingredients/{id}/adjustments", middleware.Authorize(helpers.PermissionManageInventory, controllers.AdjustStock)).Methods("POST")
+	inventoryGroup.HandleFunc("/low-stock", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetLowStock)).Methods("GET")
+	inventoryGroup.HandleFunc("/reorder", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetReorderSuggestions)).Methods("GET")
+	inventoryGroup.HandleFunc("/recipes", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetRecipes)).Methods("GET")
+	inventoryGroup.HandleFunc("/recipes/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetRecipe)).Methods("GET")
+	inventoryGroup.HandleFunc("/recipes", middleware.Authorize(helpers.PermissionManageInventory, controllers.CreateRecipe)).Methods("POST")
+	inventoryGroup.HandleFunc("/recipes/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.UpdateRecipe)).Methods("PATCH")
+}
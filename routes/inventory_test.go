@@ -0,0 +1,227 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) createIngredient(token string, name string, unit string, stock float64, threshold float64) primitive.ObjectID {
+	s.t.Helper()
+	body := models.Ingredient{Name: name, Unit: unit, Stock: stock, LowStockThreshold: threshold}
+	recorder := s.do(http.MethodPost, "/inventory/ingredients", token, body)
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return insertedID(s.t, recorder)
+}
+
+func (s *testServer) stockOf(token string, id primitive.ObjectID) float64 {
+	s.t.Helper()
+	recorder := s.do(http.MethodGet, "/inventory/ingredients/"+id.Hex(), token, nil)
+	expectStatus(s.t, recorder, http.StatusOK)
+	return decode[models.Ingredient](s.t, recorder).Stock
+}
+
+func (s *testServer) createRecipe(token string, foodID primitive.ObjectID, ingredients ...models.RecipeIngredient) primitive.ObjectID {
+	s.t.Helper()
+	recorder := s.do(http.MethodPost, "/inventory/recipes", token, models.Recipe{FoodID: foodID, Ingredients: ingredients})
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return insertedID(s.t, recorder)
+}
+
+func TestOrdersDepleteStockByRecipe(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	waiter := s.login(models.RoleWaiter)
+	rice := s.createIngredient(manager, "Basmati rice", "g", 5000, 1000)
+	chicken := s.createIngredient(manager, "Chicken", "g", 2000, 1500)
+	biryani := s.createFood("Biryani", 300)
+	naan := s.createFood("Naan", 40)
+
+	recipe := models.Recipe{FoodID: biryani.ID, Ingredients: []models.RecipeIngredient{{IngredientID: rice, Quantity: 200}, {IngredientID: chicken, Quantity: 250}}}
+	expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", manager, recipe), http.StatusCreated)
+	expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", manager, recipe), http.StatusConflict)
+	unknown := models.Recipe{FoodID: naan.ID, Ingredients: []models.RecipeIngredient{{IngredientID: primitive.NewObjectID(), Quantity: 1}}}
+	expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", manager, unknown), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", waiter, recipe), http.StatusForbidden)
+
+	low := decode[[]models.Ingredient](t, s.do(http.MethodGet, "/inventory/low-stock", manager, nil))
+	if len(low) != 0 {
+		t.Fatalf("nothing should be low yet, got %+v", low)
+	}
+
+	ids := s.placeOrder(waiter, s.createTable(1, 4).ID,
+		models.OrderItem{FoodID: biryani.ID, Quantity: 2},
+		models.OrderItem{FoodID: naan.ID, Quantity: 4},
+	)
+	if stock := s.stockOf(manager, rice); stock != 4600 {
+		t.Fatalf("expected 4600 g of rice left, got %v", stock)
+	}
+	if stock := s.stockOf(manager, chicken); stock != 1500 {
+		t.Fatalf("expected 1500 g of chicken left, got %v", stock)
+	}
+
+	low = decode[[]models.Ingredient](t, s.do(http.MethodGet, "/inventory/low-stock", manager, nil))
+	if len(low) != 1 || low[0].ID != chicken {
+		t.Fatalf("expected chicken to be low on stock, got %+v", low)
+	}
+
+	// Cancelling the order puts the stock back
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+orderItem.OrderID.Hex()+"/cancel", waiter, nil), http.StatusOK)
+	if stock := s.stockOf(manager, chicken); stock != 2000 {
+		t.Fatalf("expected the chicken back, got %v", stock)
+	}
+
+	recorder := s.do(http.MethodPost, "/inventory/ingredients/"+chicken.Hex()+"/adjustments", manager, controllers.StockAdjustment{Quantity: -600, Note: "Spoiled"})
+	expectStatus(t, recorder, http.StatusCreated)
+	if adjusted := decode[models.Ingredient](t, recorder); adjusted.Stock != 1400 {
+		t.Fatalf("expected 1400 g after the adjustment, got %v", adjusted.Stock)
+	}
+	movements := decode[[]models.StockMovement](t, s.do(http.MethodGet, "/inventory/ingredients/"+chicken.Hex()+"/movements", manager, nil))
+	reasons := []string{}
+	for _, movement := range movements {
+		reasons = append(reasons, movement.Reason)
+	}
+	if len(reasons) != 4 || reasons[0] != models.StockMovementAdjustment || reasons[1] != models.StockMovementSale || reasons[2] != models.StockMovementVoid || reasons[3] != models.StockMovementAdjustment {
+		t.Fatalf("unexpected movements %v", reasons)
+	}
+}
+
+func TestOrderItemChangesCorrectStock(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	waiter := s.login(models.RoleWaiter)
+	rice := s.createIngredient(manager, "Basmati rice", "g", 5000, 1000)
+	biryani := s.createFood("Biryani", 300)
+	naan := s.createFood("Naan", 40)
+	recipe := models.Recipe{FoodID: biryani.ID, Ingredients: []models.RecipeIngredient{{IngredientID: rice, Quantity: 200}}}
+	expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", manager, recipe), http.StatusCreated)
+
+	ids := s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: biryani.ID, Quantity: 2})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	for _, step := range []struct {
+		food     models.Food
+		quantity int
+		stock    float64
+	}{
+		{biryani, 3, 4400},
+		{biryani, 1, 4800},
+		{naan, 2, 5000},
+		{biryani, 2, 4600},
+	} {
+		body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: step.food.ID, Quantity: step.quantity}
+		expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), waiter, body), http.StatusOK)
+		if stock := s.stockOf(manager, rice); stock != step.stock {
+			t.Fatalf("expected %v g of rice left after ordering %d %s, got %v", step.stock, step.quantity, step.food.Name, stock)
+		}
+	}
+
+	// Cancelling puts back what the item uses in the end, once
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+orderItem.OrderID.Hex()+"/cancel", waiter, nil), http.StatusOK)
+	if stock := s.stockOf(manager, rice); stock != 5000 {
+		t.Fatalf("expected the rice back, got %v", stock)
+	}
+
+	// Once cancelled, the item cannot be changed to use up stock again
+	body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: biryani.ID, Quantity: 5}
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), waiter, body), http.StatusConflict)
+	if stock := s.stockOf(manager, rice); stock != 5000 {
+		t.Fatalf("a cancelled order must move no stock, got %v", stock)
+	}
+}
+
+func TestGetIngredients(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	for _, name := range []string{"Basmati rice", "Chicken", "Paneer"} {
+		s.createIngredient(manager, name, "g", 1000, 100)
+	}
+
+	recorder := s.do(http.MethodGet, "/inventory/ingredients?limit=2", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if ingredients := decode[[]models.Ingredient](t, recorder); len(ingredients) != 2 {
+		t.Fatalf("expected 2 ingredients, got %d", len(ingredients))
+	}
+	expectStatus(t, s.do(http.MethodGet, "/inventory/ingredients", s.login(models.RoleWaiter), nil), http.StatusForbidden)
+}
+
+func TestUpdateIngredient(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	rice := s.createIngredient(manager, "Rice", "g", 5000, 1000)
+
+	// Stock only changes through movements, whatever the body says
+	body := models.Ingredient{Name: "Basmati rice", Unit: "g", Stock: 1, LowStockThreshold: 2000}
+	expectStatus(t, s.do(http.MethodPatch, "/inventory/ingredients/"+rice.Hex(), manager, body), http.StatusOK)
+	updated, _ := s.repos.Ingredients.Get(context.Background(), rice)
+	if updated.Name != "Basmati rice" || updated.LowStockThreshold != 2000 || updated.Stock != 5000 {
+		t.Fatalf("unexpected ingredient %+v", updated)
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, "/inventory/ingredients/"+rice.Hex(), manager, models.Ingredient{Unit: "g"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/inventory/ingredients/"+primitive.NewObjectID().Hex(), manager, body), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, "/inventory/ingredients/not-an-id", manager, body), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/inventory/ingredients/"+rice.Hex(), s.login(models.RoleChef), body), http.StatusForbidden)
+}
+
+func TestGetRecipes(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	rice := s.createIngredient(manager, "Basmati rice", "g", 5000, 1000)
+	for _, name := range []string{"Biryani", "Pulao", "Jeera Rice"} {
+		s.createRecipe(manager, s.createFood(name, 200).ID, models.RecipeIngredient{IngredientID: rice, Quantity: 150})
+	}
+
+	recorder := s.do(http.MethodGet, "/inventory/recipes?limit=2", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if recipes := decode[[]models.Recipe](t, recorder); len(recipes) != 2 {
+		t.Fatalf("expected 2 recipes, got %d", len(recipes))
+	}
+	expectStatus(t, s.do(http.MethodGet, "/inventory/recipes", s.login(models.RoleWaiter), nil), http.StatusForbidden)
+}
+
+func TestGetRecipe(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	rice := s.createIngredient(manager, "Basmati rice", "g", 5000, 1000)
+	biryani := s.createFood("Biryani", 300)
+	id := s.createRecipe(manager, biryani.ID, models.RecipeIngredient{IngredientID: rice, Quantity: 200})
+
+	recorder := s.do(http.MethodGet, "/inventory/recipes/"+id.Hex(), manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if recipe := decode[models.Recipe](t, recorder); recipe.FoodID != biryani.ID || len(recipe.Ingredients) != 1 || recipe.Ingredients[0].Quantity != 200 {
+		t.Fatalf("unexpected recipe %+v", recipe)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/inventory/recipes/"+primitive.NewObjectID().Hex(), manager, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/inventory/recipes/not-an-id", manager, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/inventory/recipes/"+id.Hex(), s.login(models.RoleWaiter), nil), http.StatusForbidden)
+}
+
+func TestUpdateRecipe(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	rice := s.createIngredient(manager, "Basmati rice", "g", 5000, 1000)
+	chicken := s.createIngredient(manager, "Chicken", "g", 2000, 500)
+	biryani := s.createFood("Biryani", 300)
+	id := s.createRecipe(manager, biryani.ID, models.RecipeIngredient{IngredientID: rice, Quantity: 200})
+	path := "/inventory/recipes/" + id.Hex()
+
+	// The recipe stays with its food, only the ingredients change
+	body := models.Recipe{FoodID: s.createFood("Pulao", 200).ID, Ingredients: []models.RecipeIngredient{{IngredientID: rice, Quantity: 180}, {IngredientID: chicken, Quantity: 250}}}
+	expectStatus(t, s.do(http.MethodPatch, path, manager, body), http.StatusOK)
+	updated, _ := s.repos.Recipes.Get(context.Background(), id)
+	if updated.FoodID != biryani.ID || len(updated.Ingredients) != 2 || updated.Ingredients[1].Quantity != 250 {
+		t.Fatalf("unexpected recipe %+v", updated)
+	}
+
+	unknown := models.Recipe{Ingredients: []models.RecipeIngredient{{IngredientID: primitive.NewObjectID(), Quantity: 1}}}
+	expectStatus(t, s.do(http.MethodPatch, path, manager, unknown), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, path, manager, models.Recipe{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/inventory/recipes/"+primitive.NewObjectID().Hex(), manager, body), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, path, s.login(models.RoleWaiter), body), http.StatusForbidden)
+}
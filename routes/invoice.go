@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -9,8 +10,15 @@ import (
 func InvoiceRoutes(router *mux.Router) {
 	invoiceGroup := router.PathPrefix("/invoices").Subrouter()
 	invoiceGroup.Use(middleware.Authentication)
-	invoiceGroup.HandleFunc("", controllers.GetInvoices).Methods("GET")
-	invoiceGroup.HandleFunc("/{id}", controllers.GetInvoice).Methods("GET")
-	invoiceGroup.HandleFunc("", controllers.CreateInvoice).Methods("POST")
-	invoiceGroup.HandleFunc("/{id}", controllers.UpdateInvoice).Methods("PATCH")
+	invoiceGroup.HandleFunc("", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetInvoices)).Methods("GET")
+	invoiceGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetInvoice)).Methods("GET")
+	invoiceGroup.HandleFunc("/{id}/receipt", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetInvoiceReceipt)).Methods("GET")
+	// Invoice numbers contain slashes, e.g. /invoices/number/BLR1/2026-27/000123
+	invoiceGroup.HandleFunc("/number/{number:.+}", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetInvoiceByNumber)).Methods("GET")
+	invoiceGroup.HandleFunc("/split/{id}", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetSplitInvoices)).Methods("GET")
+	invoiceGroup.HandleFunc("", middleware.Authorize(helpers.PermissionCreateInvoice, controllers.CreateInvoice)).Methods("POST")
+	invoiceGroup.HandleFunc("/split", middleware.Authorize(helpers.PermissionCreateInvoice, controllers.SplitInvoice)).Methods("POST")
+	invoiceGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionUpdateInvoice, controllers.UpdateInvoice)).Methods("PATCH")
+	invoiceGroup.HandleFunc("/{id}/payments", middleware.Authorize(helpers.PermissionReadInvoices, controllers.GetPayments)).Methods("GET")
+	invoiceGroup.HandleFunc("/{id}/payments", middleware.Authorize(helpers.PermissionUpdateInvoice, controllers.CreatePayment)).Methods("POST")
 }
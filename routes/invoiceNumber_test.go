@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInvoiceNumberPeriod(t *testing.T) {
+	march := time.Date(2027, time.March, 31, 23, 0, 0, 0, time.Local)
+	april := time.Date(2027, time.April, 1, 9, 0, 0, 0, time.Local)
+	numbering := helpers.InvoiceNumbering{Prefix: "BLR1", Reset: helpers.InvoiceNumberResetFiscalYear, FiscalYearStart: time.April}
+	if number := numbering.Format(march, 123); number != "BLR1/2026-27/000123" {
+		t.Fatalf("unexpected number %q", number)
+	}
+	if numbering.Counter(march) == numbering.Counter(april) {
+		t.Fatal("a new fiscal year must start a new sequence")
+	}
+
+	numbering.Reset = helpers.InvoiceNumberResetMonthly
+	if number := numbering.Format(april, 7); number != "BLR1/2027-04/000007" {
+		t.Fatalf("unexpected number %q", number)
+	}
+	numbering.Reset = helpers.InvoiceNumberResetNever
+	if number := numbering.Format(april, 7); number != "BLR1/000007" || numbering.Counter(march) != numbering.Counter(april) {
+		t.Fatalf("unexpected number %q", number)
+	}
+}
+
+func TestInvoicesAreNumberedInSequence(t *testing.T) {
+	t.Setenv("INVOICE_PREFIX", "BLR1")
+	t.Setenv("INVOICE_NUMBER_RESET", helpers.InvoiceNumberResetNever)
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	food := s.createFood("Naan", 40)
+
+	first := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(first.ID, food, 2)
+	invoice := s.createInvoice(token, first.ID)
+	if invoice.Number != "BLR1/000001" {
+		t.Fatalf("unexpected number %q", invoice.Number)
+	}
+
+	second := s.createOrder(s.createTable(2, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(second.ID, food, 2)
+	parts := s.splitInvoice(token, controllers.InvoiceSplit{OrderID: second.ID, Mode: controllers.SplitEvenly, Parts: 2})
+	if parts[0].Number != "BLR1/000002" || parts[1].Number != "BLR1/000003" {
+		t.Fatalf("split parts take the next numbers, got %q and %q", parts[0].Number, parts[1].Number)
+	}
+
+	recorder := s.do(http.MethodGet, "/invoices/number/BLR1/000003", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if view := decode[controllers.InvoiceViewFormat](t, recorder); view.ID != parts[1].ID.Hex() || view.Number != "BLR1/000003" {
+		t.Fatalf("unexpected invoice %+v", view)
+	}
+	expectStatus(t, s.do(http.MethodGet, "/invoices/number/BLR1/000004", token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/invoices/number/BLR1/000001", s.login(models.RoleChef), nil), http.StatusForbidden)
+}
+
+// failingInvoices cannot store invoices
+type failingInvoices struct {
+	repository.InvoiceRepository
+}
+
+func (failingInvoices) CreateNumbered(ctx context.Context, counter string, number func(sequence int64) string, invoices []models.Invoice) error {
+	return errors.New("the disk is full")
+}
+
+func TestInvoiceThatCannotBeStored(t *testing.T) {
+	t.Setenv("INVOICE_PREFIX", "BLR1")
+	t.Setenv("INVOICE_NUMBER_RESET", helpers.InvoiceNumberResetNever)
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	welcome := s.createPromotion(manager, models.Promotion{Name: "Welcome", Type: models.PromotionTypeFlat, Value: 20, CouponCode: "WELCOME20", UsageLimit: 1})
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Thali", 200), 1)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+order.ID.Hex()+"/coupons", cashier, map[string]string{"code": "WELCOME20"}), http.StatusOK)
+
+	invoices := s.repos.Invoices
+	s.repos.Invoices = failingInvoices{invoices}
+	expectStatus(t, s.do(http.MethodPost, "/invoices", cashier, models.Invoice{OrderID: order.ID}), http.StatusInternalServerError)
+	s.repos.Invoices = invoices
+	if promotion, _ := s.repos.Promotions.Get(context.Background(), welcome.ID); promotion.UsedCount != 0 {
+		t.Fatalf("the coupon must be given back, got %d uses", promotion.UsedCount)
+	}
+
+	// The number and the coupon go to the invoice that is stored
+	invoice := s.createInvoice(cashier, order.ID)
+	if invoice.Number != "BLR1/000001" || invoice.GrandTotal != 180 {
+		t.Fatalf("unexpected invoice %s for %v", invoice.Number, invoice.GrandTotal)
+	}
+}
+
+func TestConcurrentInvoicesLeaveNoGaps(t *testing.T) {
+	t.Setenv("INVOICE_PREFIX", "BLR1")
+	t.Setenv("INVOICE_NUMBER_RESET", helpers.InvoiceNumberResetNever)
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	food := s.createFood("Naan", 40)
+	orders := []models.Order{}
+	for i := 1; i <= 10; i++ {
+		order := s.createOrder(s.createTable(i, 4).ID, models.OrderStatusServed)
+		s.createOrderItem(order.ID, food, 1)
+		orders = append(orders, order)
+	}
+
+	var wg sync.WaitGroup
+	for _, order := range orders {
+		wg.Add(1)
+		go func(orderID primitive.ObjectID) {
+			defer wg.Done()
+			s.do(http.MethodPost, "/invoices", cashier, models.Invoice{OrderID: orderID})
+		}(order.ID)
+	}
+	wg.Wait()
+
+	for i := 1; i <= len(orders); i++ {
+		if _, err := s.repos.Invoices.GetByNumber(context.Background(), fmt.Sprintf("BLR1/%06d", i)); err != nil {
+			t.Fatalf("invoice %d is missing: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) splitInvoice(token string, split controllers.InvoiceSplit) []models.Invoice {
+	s.t.Helper()
+	recorder := s.do(http.MethodPost, "/invoices/split", token, split)
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return decode[[]models.Invoice](s.t, recorder)
+}
+
+func totalOf(parts []models.Invoice) float64 {
+	total := 0.0
+	for _, part := range parts {
+		total = helpers.ToFixed(total+part.GrandTotal, 2)
+	}
+	return total
+}
+
+func TestSplitAmount(t *testing.T) {
+	shares := helpers.SplitAmount(100, []float64{1, 1, 1})
+	if shares[0] != 33.34 || shares[1] != 33.33 || shares[2] != 33.33 {
+		t.Fatalf("unexpected shares %v", shares)
+	}
+	shares = helpers.SplitAmount(10, []float64{0, 0})
+	if shares[0] != 5 || shares[1] != 5 {
+		t.Fatalf("zero weights must split evenly, got %v", shares)
+	}
+}
+
+func TestSplitInvoiceEvenly(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "CGST", Rate: 2.5}, models.TaxComponent{Name: "SGST", Rate: 2.5})
+	table := s.createTable(2, 4)
+	order := s.createOrder(table.ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+
+	parts := s.splitInvoice(token, controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitEvenly, Parts: 3})
+	if len(parts) != 3 || totalOf(parts) != 388.5 {
+		t.Fatalf("parts must add up to the bill, got %d parts totalling %v", len(parts), totalOf(parts))
+	}
+	if parts[0].GrandTotal != 129.5 || parts[2].SplitPart != 3 || parts[2].SplitParts != 3 || parts[0].SplitGroupID != parts[2].SplitGroupID {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+	for _, part := range parts {
+		if len(part.Lines) != 2 || part.Lines[0].Share == 0 {
+			t.Fatalf("every part shares every line, got %+v", part.Lines)
+		}
+	}
+	if status := s.tableStatus(table.ID); status != models.TableStatusAwaitingBill {
+		t.Fatalf("expected awaiting-bill, got %q", status)
+	}
+
+	recorder := s.do(http.MethodGet, "/invoices/split/"+parts[0].SplitGroupID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if linked := decode[[]models.Invoice](t, recorder); len(linked) != 3 || linked[1].ID != parts[1].ID {
+		t.Fatalf("unexpected linked invoices %+v", linked)
+	}
+	expectStatus(t, s.do(http.MethodGet, "/invoices/split/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+
+	single := controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitEvenly, Parts: 1}
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", token, single), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", s.login(models.RoleChef), single), http.StatusForbidden)
+
+	// A bill is split once, and not at all once it has been invoiced whole
+	again := controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitEvenly, Parts: 2}
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", token, again), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/invoices", token, models.Invoice{OrderID: order.ID}), http.StatusConflict)
+	invoiced := s.createOrder(s.createTable(5, 2).ID, models.OrderStatusServed)
+	s.createOrderItem(invoiced.ID, s.createFood("Naan", 40), 4)
+	s.createInvoice(token, invoiced.ID)
+	again.OrderID = invoiced.ID
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", token, again), http.StatusConflict)
+}
+
+func TestSplitInvoiceWhenAPartCannotBeStored(t *testing.T) {
+	t.Setenv("INVOICE_PREFIX", "BLR1")
+	t.Setenv("INVOICE_NUMBER_RESET", helpers.InvoiceNumberResetNever)
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	welcome := s.createPromotion(manager, models.Promotion{Name: "Welcome", Type: models.PromotionTypeFlat, Value: 20, CouponCode: "WELCOME20", UsageLimit: 1})
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Thali", 200), 1)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+order.ID.Hex()+"/coupons", cashier, map[string]string{"code": "WELCOME20"}), http.StatusOK)
+
+	// The second part's number is already taken, so it cannot be stored
+	taken := models.Invoice{Number: "BLR1/000002"}
+	if err := s.repos.Invoices.Create(context.Background(), &taken); err != nil {
+		t.Fatal(err)
+	}
+	split := controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitEvenly, Parts: 2}
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", cashier, split), http.StatusConflict)
+
+	if parts, _ := s.repos.Invoices.ListByOrder(context.Background(), order.ID); len(parts) != 0 {
+		t.Fatalf("no part may be left behind, got %d", len(parts))
+	}
+	if promotion, _ := s.repos.Promotions.Get(context.Background(), welcome.ID); promotion.UsedCount != 0 {
+		t.Fatalf("the coupon must be given back, got %d uses", promotion.UsedCount)
+	}
+
+	// The order can still be billed, with the number the split gave back
+	invoice := s.createInvoice(cashier, order.ID)
+	if invoice.Number != "BLR1/000001" || invoice.GrandTotal != 180 {
+		t.Fatalf("unexpected invoice %s for %v", invoice.Number, invoice.GrandTotal)
+	}
+}
+
+func TestSplitInvoiceByItem(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(2, 4).ID, models.OrderStatusServed)
+	tikka := s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	naan := s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+	lassi := s.createOrderItem(order.ID, s.createFood("Lassi", 60), 1)
+
+	parts := s.splitInvoice(token, controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitByItem, Items: [][]primitive.ObjectID{{tikka.ID, lassi.ID}, {naan.ID}}})
+	if len(parts) != 2 || parts[0].GrandTotal != 310 || parts[1].GrandTotal != 120 || len(parts[1].Lines) != 1 {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+
+	split := controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitByItem}
+	for _, items := range [][][]primitive.ObjectID{
+		{{tikka.ID}, {naan.ID}},
+		{{tikka.ID, naan.ID}, {naan.ID, lassi.ID}},
+		{{tikka.ID, naan.ID, lassi.ID}, {}},
+		{{tikka.ID, naan.ID, lassi.ID}, {primitive.NewObjectID()}},
+	} {
+		split.Items = items
+		expectStatus(t, s.do(http.MethodPost, "/invoices/split", token, split), http.StatusBadRequest)
+	}
+}
+
+func TestSplitInvoiceByAmount(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "GST", Rate: 5})
+	order := s.createOrder(s.createTable(2, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Biryani", 333), 1)
+
+	parts := s.splitInvoice(token, controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitByAmount, Amounts: []float64{200, 149.65}})
+	if parts[0].GrandTotal != 200 || parts[1].GrandTotal != 149.65 {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+	for _, part := range parts {
+		charged := helpers.ToFixed(part.SubTotal+part.Taxes[0].Amount+part.ServiceCharge+part.RoundingAdjustment, 2)
+		if charged != part.GrandTotal {
+			t.Fatalf("part %d does not add up: %+v", part.SplitPart, part)
+		}
+	}
+
+	split := controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitByAmount, Amounts: []float64{200, 100}}
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", token, split), http.StatusBadRequest)
+	split.Amounts = []float64{359.65, -10}
+	expectStatus(t, s.do(http.MethodPost, "/invoices/split", token, split), http.StatusBadRequest)
+}
+
+func TestSplitInvoiceFreesTableWhenEveryPartIsPaid(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	table := s.createTable(2, 4)
+	order := s.createOrder(table.ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+	parts := s.splitInvoice(token, controllers.InvoiceSplit{OrderID: order.ID, Mode: controllers.SplitEvenly, Parts: 2})
+
+	s.pay(token, parts[0].ID, models.PaymentMethodCash, 60)
+	if status := s.tableStatus(table.ID); status != models.TableStatusAwaitingBill {
+		t.Fatalf("the table waits for every part, got %q", status)
+	}
+	s.pay(token, parts[1].ID, models.PaymentMethodCard, 60)
+	if status := s.tableStatus(table.ID); status != models.TableStatusFree {
+		t.Fatalf("expected free, got %q", status)
+	}
+}
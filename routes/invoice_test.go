@@ -0,0 +1,177 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) createTaxRate(category string, inclusive bool, components ...models.TaxComponent) models.TaxRate {
+	s.t.Helper()
+	taxRate := models.TaxRate{Name: category, Category: category, Inclusive: inclusive, Components: components}
+	if err := s.repos.TaxRates.Create(context.Background(), &taxRate); err != nil {
+		s.t.Fatal(err)
+	}
+	return taxRate
+}
+
+func (s *testServer) createInvoice(token string, orderID primitive.ObjectID) models.Invoice {
+	s.t.Helper()
+	body := models.Invoice{OrderID: orderID}
+	recorder := s.do(http.MethodPost, "/invoices", token, body)
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return decode[models.Invoice](s.t, recorder)
+}
+
+func TestCreateInvoice(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "CGST", Rate: 2.5}, models.TaxComponent{Name: "SGST", Rate: 2.5})
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+
+	invoice := s.createInvoice(token, order.ID)
+	if len(invoice.Lines) != 2 || invoice.TableNumber != 3 {
+		t.Fatalf("unexpected snapshot %+v", invoice)
+	}
+	if invoice.SubTotal != 370 || invoice.GrandTotal != 388.5 {
+		t.Fatalf("expected 370 + 18.5 tax, got %v and %v", invoice.SubTotal, invoice.GrandTotal)
+	}
+	if len(invoice.Taxes) != 2 || invoice.Taxes[0].Amount != 9.25 {
+		t.Fatalf("unexpected taxes %+v", invoice.Taxes)
+	}
+
+	if invoice.PaymentStatus != models.PaymentStatusUnpaid || invoice.Balance != 388.5 {
+		t.Fatalf("a new invoice is unpaid, got %q with balance %v", invoice.PaymentStatus, invoice.Balance)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/invoices", token, models.Invoice{OrderID: primitive.NewObjectID()}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/invoices", token, models.Invoice{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/invoices", s.login(models.RoleChef), models.Invoice{OrderID: order.ID}), http.StatusForbidden)
+
+	empty := s.createOrder(s.createTable(4, 2).ID, models.OrderStatusServed)
+	expectStatus(t, s.do(http.MethodPost, "/invoices", token, models.Invoice{OrderID: empty.ID}), http.StatusBadRequest)
+
+	// An order is billed once, and never when it was not sold
+	expectStatus(t, s.do(http.MethodPost, "/invoices", token, models.Invoice{OrderID: order.ID}), http.StatusConflict)
+	for _, status := range []string{models.OrderStatusCancelled, models.OrderStatusVoided} {
+		unsold := s.createOrder(s.createTable(5, 2).ID, status)
+		s.createOrderItem(unsold.ID, s.createFood("Naan", 40), 1)
+		expectStatus(t, s.do(http.MethodPost, "/invoices", token, models.Invoice{OrderID: unsold.ID}), http.StatusConflict)
+	}
+}
+
+func TestConcurrentInvoicesForOneOrder(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 1)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := s.do(http.MethodPost, "/invoices", token, models.Invoice{OrderID: order.ID})
+			if recorder.Code == http.StatusCreated {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	invoices, _ := s.repos.Invoices.ListByOrder(context.Background(), order.ID)
+	if created != 1 || len(invoices) != 1 {
+		t.Fatalf("expected the order to be billed once, got %d responses and %d invoices", created, len(invoices))
+	}
+}
+
+func TestCreateInvoiceInclusiveTax(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	s.createTaxRate("beverage", true, models.TaxComponent{Name: "CGST", Rate: 9}, models.TaxComponent{Name: "SGST", Rate: 9})
+	food := s.createFood("Cold Coffee", 118)
+	food.TaxCategory = "beverage"
+	s.repos.Foods.Update(context.Background(), food.ID, food)
+	order := s.createOrder(s.createTable(1, 2).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, food, 1)
+
+	invoice := s.createInvoice(token, order.ID)
+	if invoice.SubTotal != 100 || invoice.GrandTotal != 118 {
+		t.Fatalf("inclusive tax must not change the menu price, got %v and %v", invoice.SubTotal, invoice.GrandTotal)
+	}
+}
+
+func TestGetInvoice(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	food := s.createFood("Naan", 40)
+	s.createOrderItem(order.ID, food, 2)
+	invoice := s.createInvoice(token, order.ID)
+
+	// Later price changes must not alter an issued invoice
+	food.Price = 60
+	s.repos.Foods.Update(context.Background(), food.ID, food)
+	s.createOrderItem(order.ID, food, 1)
+
+	recorder := s.do(http.MethodGet, "/invoices/"+invoice.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	view := decode[controllers.InvoiceViewFormat](t, recorder)
+	if view.PaymentDue != 80.0 || len(view.Lines) != 1 || view.TableID != "3" {
+		t.Fatalf("unexpected invoice view %+v", view)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/invoices/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/invoices/not-an-id", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/invoices/"+invoice.ID.Hex(), s.login(models.RoleChef), nil), http.StatusForbidden)
+}
+
+func TestGetInvoices(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	for number := 1; number <= 3; number++ {
+		order := s.createOrder(s.createTable(number, 4).ID, models.OrderStatusServed)
+		s.createOrderItem(order.ID, s.createFood("Naan", 40), 1)
+		s.createInvoice(token, order.ID)
+	}
+
+	recorder := s.do(http.MethodGet, "/invoices?limit=2", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if invoices := decode[[]models.Invoice](t, recorder); len(invoices) != 2 {
+		t.Fatalf("expected 2 invoices, got %d", len(invoices))
+	}
+}
+
+func TestUpdateInvoice(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 1)
+	invoice := s.createInvoice(cashier, order.ID)
+
+	dueDate := time.Now().AddDate(0, 0, 7).Truncate(time.Millisecond)
+	body := models.Invoice{PaymentDueDate: dueDate}
+	expectStatus(t, s.do(http.MethodPatch, "/invoices/"+invoice.ID.Hex(), cashier, body), http.StatusOK)
+	updated, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if !updated.PaymentDueDate.Equal(dueDate) || updated.GrandTotal != invoice.GrandTotal {
+		t.Fatalf("unexpected invoice %+v", updated)
+	}
+
+	// The status follows the payments and cannot be set by hand
+	expectStatus(t, s.do(http.MethodPatch, "/invoices/"+invoice.ID.Hex(), cashier, models.Invoice{PaymentStatus: "PAID", PaymentDueDate: dueDate}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/invoices/"+invoice.ID.Hex(), cashier, models.Invoice{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/invoices/"+primitive.NewObjectID().Hex(), cashier, body), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, "/invoices/"+invoice.ID.Hex(), s.login(models.RoleWaiter), body), http.StatusForbidden)
+}
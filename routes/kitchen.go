@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func KitchenRoutes(router *mux.Router) {
+	kitchenGroup := router.PathPrefix("/kitchen").Subrouter()
+	kitchenGroup.Use(middleware.Authentication)
+	kitchenGroup.HandleFunc("/tickets", controllers.GetKitchenTickets).Methods("GET")
+	kitchenGroup.HandleFunc("/tickets/{id}/start", middleware.Authorize(helpers.PermissionCookOrders, controllers.StartKitchenTicket)).Methods("POST")
+	kitchenGroup.HandleFunc("/tickets/{id}/ready", middleware.Authorize(helpers.PermissionCookOrders, controllers.MarkKitchenTicketReady)).Methods("POST")
+	kitchenGroup.HandleFunc("/stream", controllers.StreamKitchenTickets).Methods("GET")
+}
@@ -0,0 +1,219 @@
+package routes
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// placeOrder creates order items through the API so their kitchen tickets are raised
+func (s *testServer) placeOrder(token string, tableID primitive.ObjectID, orderItems ...models.OrderItem) []primitive.ObjectID {
+	s.t.Helper()
+	recorder := s.do(http.MethodPost, "/orderItems", token, controllers.OrderItemPack{TableID: tableID, OrderItems: orderItems})
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return decode[[]primitive.ObjectID](s.t, recorder)
+}
+
+func (s *testServer) createStationFood(name string, price float64, station string) models.Food {
+	s.t.Helper()
+	food := s.createFood(name, price)
+	food.Station = station
+	if err := s.repos.Foods.Update(context.Background(), food.ID, food); err != nil {
+		s.t.Fatal(err)
+	}
+	return food
+}
+
+func TestKitchenTicketsRoutedToStations(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	chef := s.login(models.RoleChef)
+	tikka := s.createStationFood("Paneer Tikka", 250, "tandoor")
+	lassi := s.createStationFood("Sweet Lassi", 90, "bar")
+	dal := s.createFood("Dal Makhani", 220)
+	s.placeOrder(waiter, s.createTable(7, 4).ID,
+		models.OrderItem{FoodID: tikka.ID, Quantity: 2, UnitPrice: 250},
+		models.OrderItem{FoodID: lassi.ID, Quantity: 1, UnitPrice: 90},
+		models.OrderItem{FoodID: dal.ID, Quantity: 1, UnitPrice: 220},
+	)
+
+	recorder := s.do(http.MethodGet, "/kitchen/tickets", chef, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if tickets := decode[[]models.KitchenTicket](t, recorder); len(tickets) != 3 {
+		t.Fatalf("expected 3 tickets, got %d", len(tickets))
+	}
+
+	recorder = s.do(http.MethodGet, "/kitchen/tickets?station=tandoor", chef, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	tickets := decode[[]models.KitchenTicket](t, recorder)
+	if len(tickets) != 1 || tickets[0].FoodName != "Paneer Tikka" || tickets[0].Quantity != 2 || tickets[0].TableNumber != 7 {
+		t.Fatalf("unexpected tandoor board %+v", tickets)
+	}
+
+	recorder = s.do(http.MethodGet, "/kitchen/tickets?station="+models.KitchenStationDefault, chef, nil)
+	if tickets := decode[[]models.KitchenTicket](t, recorder); len(tickets) != 1 || tickets[0].FoodName != "Dal Makhani" {
+		t.Fatalf("foods without a station should go to the default station, got %+v", tickets)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/kitchen/tickets?status=burnt", chef, nil), http.StatusBadRequest)
+}
+
+func TestBumpKitchenTicket(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	chef := s.login(models.RoleChef)
+	food := s.createFood("Dal Makhani", 220)
+	s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: food.ID, Quantity: 1, UnitPrice: 220})
+	tickets, _ := s.repos.KitchenTickets.List(context.Background(), "", []string{models.TicketStatusQueued})
+	path := "/kitchen/tickets/" + tickets[0].ID.Hex()
+
+	expectStatus(t, s.do(http.MethodPost, path+"/start", waiter, nil), http.StatusForbidden)
+
+	recorder := s.do(http.MethodPost, path+"/start", chef, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if ticket := decode[models.KitchenTicket](t, recorder); ticket.Status != models.TicketStatusInProgress || ticket.StartedOn.IsZero() {
+		t.Fatalf("unexpected ticket %+v", ticket)
+	}
+	expectStatus(t, s.do(http.MethodPost, path+"/start", chef, nil), http.StatusConflict)
+
+	recorder = s.do(http.MethodPost, path+"/ready", chef, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if ticket := decode[models.KitchenTicket](t, recorder); ticket.Status != models.TicketStatusReady || ticket.ReadyOn.IsZero() {
+		t.Fatalf("unexpected ticket %+v", ticket)
+	}
+
+	// Ready tickets leave the board but can still be looked up
+	if tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", chef, nil)); len(tickets) != 0 {
+		t.Fatalf("expected an empty board, got %d tickets", len(tickets))
+	}
+	if tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets?status=ready", chef, nil)); len(tickets) != 1 {
+		t.Fatalf("expected one ready ticket, got %d", len(tickets))
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/kitchen/tickets/"+primitive.NewObjectID().Hex()+"/ready", chef, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, "/kitchen/tickets/not-an-id/ready", chef, nil), http.StatusBadRequest)
+}
+
+func TestCancelledOrderLeavesKitchenBoard(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	food := s.createFood("Dal Makhani", 220)
+	ids := s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: food.ID, Quantity: 1, UnitPrice: 220})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+orderItem.OrderID.Hex()+"/cancel", waiter, nil), http.StatusOK)
+	if tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", waiter, nil)); len(tickets) != 0 {
+		t.Fatalf("expected cancelled tickets to leave the board, got %+v", tickets)
+	}
+}
+
+func TestChangedOrderItemUpdatesKitchenTicket(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	chef := s.login(models.RoleChef)
+	dal := s.createFood("Dal Makhani", 220)
+	tikka := s.createStationFood("Paneer Tikka", 250, "tandoor")
+	ids := s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: dal.ID, Quantity: 1})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	path := "/orderItems/" + orderItem.ID.Hex()
+
+	body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: dal.ID, Quantity: 3}
+	expectStatus(t, s.do(http.MethodPatch, path, waiter, body), http.StatusOK)
+	tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", chef, nil))
+	if len(tickets) != 1 || tickets[0].Quantity != 3 {
+		t.Fatalf("expected the ticket to cook 3, got %+v", tickets)
+	}
+
+	// A different food moves the ticket to the station that cooks it
+	body.FoodID = tikka.ID
+	expectStatus(t, s.do(http.MethodPatch, path, waiter, body), http.StatusOK)
+	tickets = decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets?station=tandoor", chef, nil))
+	if len(tickets) != 1 || tickets[0].FoodName != "Paneer Tikka" || tickets[0].Quantity != 3 {
+		t.Fatalf("unexpected tandoor board %+v", tickets)
+	}
+
+	// Once cooked, only what the change adds goes to the kitchen
+	expectStatus(t, s.do(http.MethodPost, "/kitchen/tickets/"+tickets[0].ID.Hex()+"/ready", chef, nil), http.StatusOK)
+	body.Quantity = 5
+	expectStatus(t, s.do(http.MethodPatch, path, waiter, body), http.StatusOK)
+	tickets = decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", chef, nil))
+	if len(tickets) != 1 || tickets[0].Quantity != 2 || tickets[0].OrderItemID != orderItem.ID {
+		t.Fatalf("expected a new ticket for 2 more, got %+v", tickets)
+	}
+	body.Quantity = 4
+	expectStatus(t, s.do(http.MethodPatch, path, waiter, body), http.StatusOK)
+	if tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets?status=ready", chef, nil)); len(tickets) != 1 || tickets[0].Quantity != 3 {
+		t.Fatalf("a cooked ticket must be left alone, got %+v", tickets)
+	}
+}
+
+func TestStreamKitchenTickets(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	chef := s.login(models.RoleChef)
+	food := s.createStationFood("Sweet Lassi", 90, "bar")
+	other := s.createStationFood("Paneer Tikka", 250, "tandoor")
+	table := s.createTable(3, 2)
+
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/kitchen/stream?station=bar", nil)
+	request.Header.Set("token", chef)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected stream response %d %s", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+
+	s.placeOrder(waiter, table.ID, models.OrderItem{FoodID: other.ID, Quantity: 1, UnitPrice: 250})
+	s.placeOrder(waiter, table.ID, models.OrderItem{FoodID: food.ID, Quantity: 2, UnitPrice: 90})
+	tickets, _ := s.repos.KitchenTickets.List(context.Background(), "bar", []string{models.TicketStatusQueued})
+	expectStatus(t, s.do(http.MethodPost, "/kitchen/tickets/"+tickets[0].ID.Hex()+"/start", chef, nil), http.StatusOK)
+
+	// Changing the item changes its ticket on the stream too
+	body := models.OrderItem{OrderID: tickets[0].OrderID, FoodID: food.ID, Quantity: 3}
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+tickets[0].OrderItemID.Hex(), waiter, body), http.StatusOK)
+
+	reader := bufio.NewReader(response.Body)
+	for _, status := range []string{models.TicketStatusQueued, models.TicketStatusInProgress} {
+		ticket := readTicketEvent(t, reader)
+		if ticket.Station != "bar" || ticket.Status != status {
+			t.Fatalf("expected a %s bar ticket, got %+v", status, ticket)
+		}
+	}
+	if ticket := readTicketEvent(t, reader); ticket.ID != tickets[0].ID || ticket.Quantity != 3 {
+		t.Fatalf("expected the changed ticket, got %+v", ticket)
+	}
+}
+
+func readTicketEvent(t *testing.T, reader *bufio.Reader) models.KitchenTicket {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			var ticket models.KitchenTicket
+			if err := json.Unmarshal([]byte(data), &ticket); err != nil {
+				t.Fatal(err)
+			}
+			return ticket
+		}
+	}
+}
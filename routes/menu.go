@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -10,7 +11,8 @@ func MenuRoutes(router *mux.Router) {
 	menuGroup := router.PathPrefix("/menus").Subrouter()
 	menuGroup.Use(middleware.Authentication)
 	menuGroup.HandleFunc("", controllers.GetMenus).Methods("GET")
+	menuGroup.HandleFunc("/active", controllers.GetActiveMenus).Methods("GET")
 	menuGroup.HandleFunc("/{id}", controllers.GetMenu).Methods("GET")
-	menuGroup.HandleFunc("", controllers.CreateMenu).Methods("POST")
-	menuGroup.HandleFunc("/{id}", controllers.UpdateMenu).Methods("PATCH")
+	menuGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageMenu, controllers.CreateMenu)).Methods("POST")
+	menuGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageMenu, controllers.UpdateMenu)).Methods("PATCH")
 }
@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetMenus(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	s.createMenu()
+	s.createMenu()
+
+	recorder := s.do(http.MethodGet, "/menus", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if menus := decode[[]models.Menu](t, recorder); len(menus) != 2 {
+		t.Fatalf("expected 2 menus, got %d", len(menus))
+	}
+}
+
+func TestGetMenu(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	menu := s.createMenu()
+
+	recorder := s.do(http.MethodGet, "/menus/"+menu.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.Menu](t, recorder); got.Name != menu.Name {
+		t.Fatalf("unexpected menu %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/menus/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/menus/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestCreateMenu(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+
+	recorder := s.do(http.MethodPost, "/menus", token, models.Menu{Name: "Breakfast", Category: "Morning"})
+	expectStatus(t, recorder, http.StatusCreated)
+	if _, err := s.repos.Menus.Get(context.Background(), insertedID(t, recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/menus", token, models.Menu{Name: "Breakfast"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/menus", s.login(models.RoleWaiter), models.Menu{Name: "Breakfast", Category: "Morning"}), http.StatusForbidden)
+}
+
+func TestUpdateMenu(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+	menu := s.createMenu()
+
+	expectStatus(t, s.do(http.MethodPatch, "/menus/"+menu.ID.Hex(), token, models.Menu{Name: "Supper", Category: "Night"}), http.StatusOK)
+	updated, _ := s.repos.Menus.Get(context.Background(), menu.ID)
+	if updated.Name != "Supper" || updated.Category != "Night" {
+		t.Fatalf("menu not updated: %+v", updated)
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, "/menus/"+primitive.NewObjectID().Hex(), token, models.Menu{Name: "Supper", Category: "Night"}), http.StatusNotFound)
+}
+
+func TestActiveMenus(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	breakfast := insertedID(t, s.do(http.MethodPost, "/menus", manager, models.Menu{Name: "Breakfast", Category: "Morning", ServingHours: []models.DailyWindow{{From: "07:00", To: "11:00"}}}))
+	insertedID(t, s.do(http.MethodPost, "/menus", manager, models.Menu{Name: "Dinner", Category: "Evening", ServingHours: []models.DailyWindow{{From: "19:00", To: "23:00"}}}))
+	insertedID(t, s.do(http.MethodPost, "/menus", manager, models.Menu{Name: "Festival", Category: "Special", EndDate: time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC)}))
+	drinks := insertedID(t, s.do(http.MethodPost, "/menus", manager, models.Menu{Name: "Drinks", Category: "Bar"}))
+	s.repos.Foods.Create(context.Background(), &models.Food{Name: "Poha", Price: 80, FoodImage: "poha.png", MenuID: breakfast})
+	s.repos.Foods.Create(context.Background(), &models.Food{Name: "Masala Chai", Price: 30, FoodImage: "chai.png", MenuID: drinks})
+
+	recorder := s.do(http.MethodGet, "/menus/active?at=2026-10-18T08:30:00%2B05:30", s.login(models.RoleWaiter), nil)
+	expectStatus(t, recorder, http.StatusOK)
+	active := decode[[]controllers.ActiveMenu](t, recorder)
+	if len(active) != 2 || active[0].Name != "Breakfast" || active[1].Name != "Drinks" {
+		t.Fatalf("expected breakfast and drinks, got %+v", active)
+	}
+	if len(active[0].Foods) != 1 || active[0].Foods[0].Name != "Poha" {
+		t.Fatalf("unexpected breakfast foods %+v", active[0].Foods)
+	}
+
+	if active := decode[[]controllers.ActiveMenu](t, s.do(http.MethodGet, "/menus/active?at=2026-10-18T21:00:00%2B05:30", manager, nil)); len(active) != 2 || active[0].Name != "Dinner" {
+		t.Fatalf("expected dinner and drinks at night, got %+v", active)
+	}
+	expectStatus(t, s.do(http.MethodGet, "/menus/active?at=tomorrow", manager, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/menus", manager, models.Menu{Name: "Brunch", Category: "Weekend", ServingHours: []models.DailyWindow{{From: "11:00", To: "11:00"}}}), http.StatusBadRequest)
+}
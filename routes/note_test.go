@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) createNote(token string, orderID primitive.ObjectID, text string) models.Note {
+	s.t.Helper()
+	recorder := s.do(http.MethodPost, "/orders/"+orderID.Hex()+"/notes", token, models.Note{Text: text})
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return decode[models.Note](s.t, recorder)
+}
+
+func TestCreateNote(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.createUser(models.RoleWaiter)
+	token := s.tokenFor(waiter)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+
+	note := s.createNote(token, order.ID, "Birthday, bring a candle")
+	if note.OrderID != order.ID || note.AuthorID != waiter.ID || note.AuthorName != waiter.Username {
+		t.Fatalf("unexpected note %+v", note)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+order.ID.Hex()+"/notes", token, models.Note{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+primitive.NewObjectID().Hex()+"/notes", token, models.Note{Text: "x"}), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+order.ID.Hex()+"/notes", s.login(models.RoleChef), models.Note{Text: "x"}), http.StatusForbidden)
+}
+
+func TestGetNotes(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	order := s.createOrder(table.ID, models.OrderStatusPlaced)
+	other := s.createOrder(table.ID, models.OrderStatusPlaced)
+	note := s.createNote(token, order.ID, "Birthday, bring a candle")
+	s.createNote(token, order.ID, "Allergic to peanuts")
+	otherNote := s.createNote(token, other.ID, "Window seat")
+
+	recorder := s.do(http.MethodGet, "/orders/"+order.ID.Hex()+"/notes", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if notes := decode[[]models.Note](t, recorder); len(notes) != 2 || notes[0].Text != note.Text {
+		t.Fatalf("unexpected notes %+v", notes)
+	}
+
+	recorder = s.do(http.MethodGet, "/orders/"+order.ID.Hex()+"/notes/"+note.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.Note](t, recorder); got.Text != note.Text {
+		t.Fatalf("unexpected note %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/orders/"+order.ID.Hex()+"/notes/"+otherNote.ID.Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/orders/"+order.ID.Hex()+"/notes/not-an-id", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/orders/"+primitive.NewObjectID().Hex()+"/notes", token, nil), http.StatusNotFound)
+}
+
+func TestUpdateAndDeleteNote(t *testing.T) {
+	s := newTestServer(t)
+	author := s.login(models.RoleWaiter)
+	otherWaiter := s.login(models.RoleWaiter)
+	manager := s.login(models.RoleManager)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+	note := s.createNote(author, order.ID, "No onions")
+	path := "/orders/" + order.ID.Hex() + "/notes/" + note.ID.Hex()
+
+	expectStatus(t, s.do(http.MethodPatch, path, otherWaiter, models.Note{Text: "Extra onions"}), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodPatch, path, author, models.Note{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, path, author, models.Note{Text: "No onions, no garlic"}), http.StatusOK)
+	updated, _ := s.repos.Notes.Get(context.Background(), note.ID)
+	if updated.Text != "No onions, no garlic" || updated.AuthorID != note.AuthorID {
+		t.Fatalf("unexpected note %+v", updated)
+	}
+
+	expectStatus(t, s.do(http.MethodDelete, path, otherWaiter, nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodDelete, path, manager, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodDelete, path, manager, nil), http.StatusNotFound)
+}
+
+func TestNotesInOrderItemsAndKitchenTickets(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	food := s.createFood("Dal Makhani", 220)
+	ids := s.placeOrder(token, s.createTable(1, 4).ID, models.OrderItem{FoodID: food.ID, Quantity: 1, UnitPrice: 220, Note: "No cream"})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	s.createNote(token, orderItem.OrderID, "Birthday, bring a candle")
+
+	summaries := decode[[]models.OrderItemsSummary](t, s.do(http.MethodGet, "/orderItems/order/"+orderItem.OrderID.Hex(), token, nil))
+	if len(summaries) != 1 || len(summaries[0].Notes) != 1 || summaries[0].OrderItems[0].Note != "No cream" {
+		t.Fatalf("expected order and item notes in the summary, got %+v", summaries)
+	}
+
+	tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", token, nil))
+	if len(tickets) != 1 || tickets[0].Note != "No cream" || len(tickets[0].OrderNotes) != 1 || tickets[0].OrderNotes[0] != "Birthday, bring a candle" {
+		t.Fatalf("expected order and item notes on the ticket, got %+v", tickets)
+	}
+
+	// A changed note reaches the ticket, and a note can be taken off
+	body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: food.ID, Quantity: 1, Note: "Extra spicy"}
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusOK)
+	tickets = decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", token, nil))
+	if len(tickets) != 1 || tickets[0].Note != "Extra spicy" {
+		t.Fatalf("expected the changed note on the ticket, got %+v", tickets)
+	}
+	body.Note = ""
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusOK)
+	tickets = decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", token, nil))
+	if updated, _ := s.repos.OrderItems.Get(context.Background(), orderItem.ID); updated.Note != "" || tickets[0].Note != "" {
+		t.Fatalf("expected the note to be taken off, got %q on the item and %q on the ticket", updated.Note, tickets[0].Note)
+	}
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -11,6 +12,19 @@ func OrderRoutes(router *mux.Router) {
 	orderGroup.Use(middleware.Authentication)
 	orderGroup.HandleFunc("", controllers.GetOrders).Methods("GET")
 	orderGroup.HandleFunc("/{id}", controllers.GetOrder).Methods("GET")
-	orderGroup.HandleFunc("", controllers.CreateOrder).Methods("POST")
-	orderGroup.HandleFunc("/{id}", controllers.UpdateOrder).Methods("PATCH")
+	orderGroup.HandleFunc("", middleware.Authorize(helpers.PermissionTakeOrders, controllers.CreateOrder)).Methods("POST")
+	orderGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionTakeOrders, controllers.UpdateOrder)).Methods("PATCH")
+	orderGroup.HandleFunc("/{id}/kitchen", middleware.Authorize(helpers.PermissionTakeOrders, controllers.SendOrderToKitchen)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/ready", middleware.Authorize(helpers.PermissionCookOrders, controllers.MarkOrderReady)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/serve", middleware.Authorize(helpers.PermissionTakeOrders, controllers.ServeOrder)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/close", middleware.Authorize(helpers.PermissionCloseOrders, controllers.CloseOrder)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/cancel", middleware.Authorize(helpers.PermissionTakeOrders, controllers.CancelOrder)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/void", middleware.Authorize(helpers.PermissionVoidOrders, controllers.VoidOrder)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/coupons", middleware.Authorize(helpers.PermissionCreateInvoice, controllers.ApplyCoupon)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/coupons/{code}", middleware.Authorize(helpers.PermissionCreateInvoice, controllers.RemoveCoupon)).Methods("DELETE")
+	orderGroup.HandleFunc("/{id}/notes", controllers.GetNotes).Methods("GET")
+	orderGroup.HandleFunc("/{id}/notes/{noteId}", controllers.GetNote).Methods("GET")
+	orderGroup.HandleFunc("/{id}/notes", middleware.Authorize(helpers.PermissionTakeOrders, controllers.CreateNote)).Methods("POST")
+	orderGroup.HandleFunc("/{id}/notes/{noteId}", middleware.Authorize(helpers.PermissionTakeOrders, controllers.UpdateNote)).Methods("PATCH")
+	orderGroup.HandleFunc("/{id}/notes/{noteId}", middleware.Authorize(helpers.PermissionTakeOrders, controllers.DeleteNote)).Methods("DELETE")
 }
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -12,6 +13,6 @@ func OrderItemRoutes(router *mux.Router) {
 	OrderItemGroup.HandleFunc("", controllers.GetOrderItems).Methods("GET")
 	OrderItemGroup.HandleFunc("/{id}", controllers.GetOrderItem).Methods("GET")
 	OrderItemGroup.HandleFunc("/order/{orderId}", controllers.GetOrderItemsByOrder).Methods("GET")
-	OrderItemGroup.HandleFunc("", controllers.CreateOrderItem).Methods("POST")
-	OrderItemGroup.HandleFunc("/{id}", controllers.UpdateOrderItem).Methods("PATCH")
+	OrderItemGroup.HandleFunc("", middleware.Authorize(helpers.PermissionTakeOrders, controllers.CreateOrderItem)).Methods("POST")
+	OrderItemGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionTakeOrders, controllers.UpdateOrderItem)).Methods("PATCH")
 }
@@ -0,0 +1,266 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetOrderItems(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+	s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+
+	recorder := s.do(http.MethodGet, "/orderItems", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if orderItems := decode[[]models.OrderItem](t, recorder); len(orderItems) != 2 {
+		t.Fatalf("expected 2 order items, got %d", len(orderItems))
+	}
+}
+
+func TestGetOrderItem(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+	orderItem := s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+
+	recorder := s.do(http.MethodGet, "/orderItems/"+orderItem.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.OrderItem](t, recorder); got.Quantity != 3 {
+		t.Fatalf("unexpected order item %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/orderItems/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/orderItems/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestGetOrderItemsByOrder(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(5, 4)
+	order := s.createOrder(table.ID, models.OrderStatusPlaced)
+	s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+
+	recorder := s.do(http.MethodGet, "/orderItems/order/"+order.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	summaries := decode[[]models.OrderItemsSummary](t, recorder)
+	if len(summaries) != 1 {
+		t.Fatalf("expected one summary, got %d", len(summaries))
+	}
+	if summaries[0].PaymentDue != 370 || summaries[0].TotalCount != 2 || summaries[0].TableNumber != 5 {
+		t.Fatalf("unexpected summary %+v", summaries[0])
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/orderItems/order/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestCreateOrderItem(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	food := s.createFood("Naan", 40)
+
+	body := controllers.OrderItemPack{
+		TableID:    table.ID,
+		OrderItems: []models.OrderItem{{FoodID: food.ID, Quantity: 2, UnitPrice: 40}},
+	}
+	recorder := s.do(http.MethodPost, "/orderItems", token, body)
+	expectStatus(t, recorder, http.StatusCreated)
+	ids := decode[[]primitive.ObjectID](t, recorder)
+	if len(ids) != 1 {
+		t.Fatalf("expected one inserted item, got %d", len(ids))
+	}
+
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	order, err := s.repos.Orders.Get(context.Background(), orderItem.OrderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.TableID != table.ID || order.Status != models.OrderStatusPlaced {
+		t.Fatalf("unexpected order %+v", order)
+	}
+
+	body.OrderItems = []models.OrderItem{{FoodID: food.ID}}
+	expectStatus(t, s.do(http.MethodPost, "/orderItems", token, body), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/orderItems", s.login(models.RoleChef), body), http.StatusForbidden)
+}
+
+func TestUpdateOrderItem(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+	food := s.createFood("Naan", 40)
+	orderItem := s.createOrderItem(order.ID, food, 1)
+
+	body := models.OrderItem{OrderID: order.ID, FoodID: food.ID, Quantity: 4, UnitPrice: 40}
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusOK)
+	updated, _ := s.repos.OrderItems.Get(context.Background(), orderItem.ID)
+	if updated.Quantity != 4 {
+		t.Fatalf("expected quantity 4, got %d", updated.Quantity)
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+primitive.NewObjectID().Hex(), token, body), http.StatusNotFound)
+	body.OrderID = primitive.NewObjectID()
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusNotFound)
+
+	// An item cannot become a food that is not being served
+	now := time.Now()
+	later := models.Menu{
+		Name:         "Late Night",
+		Category:     "Night",
+		ServingHours: []models.DailyWindow{{From: now.Add(2 * time.Hour).Format("15:04"), To: now.Add(3 * time.Hour).Format("15:04")}},
+	}
+	s.repos.Menus.Create(context.Background(), &later)
+	maggi := models.Food{Name: "Maggi", Price: 60, FoodImage: "maggi.png", MenuID: later.ID}
+	s.repos.Foods.Create(context.Background(), &maggi)
+	body = models.OrderItem{OrderID: order.ID, FoodID: maggi.ID, Quantity: 1, UnitPrice: 60}
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusBadRequest)
+	body.FoodID = primitive.NewObjectID()
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusBadRequest)
+	if updated, _ = s.repos.OrderItems.Get(context.Background(), orderItem.ID); updated.FoodID != food.ID {
+		t.Fatal("a rejected update must leave the item alone")
+	}
+}
+
+func TestUpdateOrderItemModifiers(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	pizza := s.createFood("Margherita", 300)
+	pizza.ModifierGroups = []models.ModifierGroup{
+		{Name: "Size", Max: 1, Options: []models.ModifierOption{{Name: "Small"}, {Name: "Large", Price: 80}}},
+		{Name: "Extras", Max: 2, Options: []models.ModifierOption{{Name: "Extra cheese", Price: 40}, {Name: "Jalapeno", Price: 20}}},
+	}
+	s.repos.Foods.Update(context.Background(), pizza.ID, pizza)
+	large := models.OrderItemModifier{Group: "Size", Option: "Large"}
+	cheese := models.OrderItemModifier{Group: "Extras", Option: "Extra cheese"}
+	ids := s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: pizza.ID, Quantity: 1, Modifiers: []models.OrderItemModifier{large, cheese}})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	path := "/orderItems/" + orderItem.ID.Hex()
+
+	// The menu's prices go up after the item was ordered
+	pizza.Price = 350
+	pizza.ModifierGroups[0].Options[1].Price = 100
+	pizza.ModifierGroups[1].Options[0].Price = 50
+	pizza.ModifierGroups[1].Options[1].Price = 25
+	s.repos.Foods.Update(context.Background(), pizza.ID, pizza)
+
+	// What was ordered keeps its price, what is added is priced as it is now
+	jalapeno := models.OrderItemModifier{Group: "Extras", Option: "Jalapeno"}
+	body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: pizza.ID, Quantity: 2, Modifiers: []models.OrderItemModifier{large, cheese, jalapeno}}
+	expectStatus(t, s.do(http.MethodPatch, path, waiter, body), http.StatusOK)
+	updated, _ := s.repos.OrderItems.Get(context.Background(), orderItem.ID)
+	if updated.UnitPrice != 300 || len(updated.Modifiers) != 3 || updated.Modifiers[0].Price != 80 || updated.Modifiers[1].Price != 40 || updated.Modifiers[2].Price != 25 {
+		t.Fatalf("unexpected prices %v and %+v", updated.UnitPrice, updated.Modifiers)
+	}
+
+	// Modifiers can be taken off, on the item and its ticket
+	body.Modifiers = nil
+	expectStatus(t, s.do(http.MethodPatch, path, waiter, body), http.StatusOK)
+	updated, _ = s.repos.OrderItems.Get(context.Background(), orderItem.ID)
+	tickets := decode[[]models.KitchenTicket](t, s.do(http.MethodGet, "/kitchen/tickets", waiter, nil))
+	if len(updated.Modifiers) != 0 || len(tickets) != 1 || len(tickets[0].Modifiers) != 0 {
+		t.Fatalf("expected no modifiers, got %+v on the item and %+v on the ticket", updated.Modifiers, tickets)
+	}
+}
+
+func TestUpdateOrderItemOnceServed(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	food := s.createFood("Naan", 40)
+	for _, status := range []string{models.OrderStatusInKitchen, models.OrderStatusReady} {
+		order := s.createOrder(s.createTable(1, 4).ID, status)
+		orderItem := s.createOrderItem(order.ID, food, 1)
+		body := models.OrderItem{OrderID: order.ID, FoodID: food.ID, Quantity: 2}
+		expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusOK)
+	}
+
+	// Items are settled once the order is served, and never change after
+	// it is closed, cancelled or voided
+	for _, status := range []string{models.OrderStatusServed, models.OrderStatusClosed, models.OrderStatusCancelled, models.OrderStatusVoided} {
+		order := s.createOrder(s.createTable(2, 4).ID, status)
+		orderItem := s.createOrderItem(order.ID, food, 1)
+		body := models.OrderItem{OrderID: order.ID, FoodID: food.ID, Quantity: 2}
+		expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), token, body), http.StatusConflict)
+		if updated, _ := s.repos.OrderItems.Get(context.Background(), orderItem.ID); updated.Quantity != 1 {
+			t.Fatalf("the item of a %s order must be left alone, got quantity %d", status, updated.Quantity)
+		}
+	}
+}
+
+func TestCreateOrderItemRejectsInactiveMenu(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	now := time.Now()
+	later := models.Menu{
+		Name:         "Late Night",
+		Category:     "Night",
+		ServingHours: []models.DailyWindow{{From: now.Add(2 * time.Hour).Format("15:04"), To: now.Add(3 * time.Hour).Format("15:04")}},
+	}
+	s.repos.Menus.Create(context.Background(), &later)
+	maggi := models.Food{Name: "Maggi", Price: 60, FoodImage: "maggi.png", MenuID: later.ID}
+	s.repos.Foods.Create(context.Background(), &maggi)
+	naan := s.createFood("Naan", 40)
+
+	body := controllers.OrderItemPack{
+		TableID:    table.ID,
+		OrderItems: []models.OrderItem{{FoodID: naan.ID, Quantity: 2, UnitPrice: 40}, {FoodID: maggi.ID, Quantity: 1, UnitPrice: 60}},
+	}
+	recorder := s.do(http.MethodPost, "/orderItems", token, body)
+	expectStatus(t, recorder, http.StatusBadRequest)
+	if message := decode[string](t, recorder); !strings.Contains(message, "Maggi") {
+		t.Fatalf("expected the message to name the food, got %q", message)
+	}
+	if orders, _ := s.repos.Orders.List(context.Background(), 0, 0); len(orders) != 0 {
+		t.Fatalf("no order must be opened for a rejected request, got %d", len(orders))
+	}
+
+	body.OrderItems = []models.OrderItem{{FoodID: primitive.NewObjectID(), Quantity: 1, UnitPrice: 10}}
+	expectStatus(t, s.do(http.MethodPost, "/orderItems", token, body), http.StatusBadRequest)
+}
+
+func TestOrderItemsArePricedWhenOrdered(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	manager := s.login(models.RoleManager)
+	naan := s.createFood("Naan", 40)
+
+	ids := s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: naan.ID, Quantity: 2, UnitPrice: 1})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	if orderItem.UnitPrice != 40 {
+		t.Fatalf("expected the food's price 40, got %v", orderItem.UnitPrice)
+	}
+
+	// A price change after ordering leaves the item's price alone
+	naan.Price = 50
+	s.repos.Foods.Update(context.Background(), naan.ID, naan)
+	body := models.OrderItem{OrderID: orderItem.OrderID, FoodID: naan.ID, Quantity: 3, UnitPrice: 1}
+	expectStatus(t, s.do(http.MethodPatch, "/orderItems/"+orderItem.ID.Hex(), waiter, body), http.StatusOK)
+	updated, _ := s.repos.OrderItems.Get(context.Background(), orderItem.ID)
+	if updated.UnitPrice != 40 || updated.Quantity != 3 {
+		t.Fatalf("expected 3 at 40, got %d at %v", updated.Quantity, updated.UnitPrice)
+	}
+
+	// Promotions count if they ran when the item was ordered, not when it is billed
+	order := s.createOrder(s.createTable(2, 4).ID, models.OrderStatusServed)
+	ordered := time.Now().Add(-2 * time.Hour)
+	pastItem := models.OrderItem{OrderID: order.ID, FoodID: naan.ID, Quantity: 1, UnitPrice: 40, CreatedOn: ordered, UpdatedOn: ordered}
+	if _, err := s.repos.OrderItems.CreateMany(context.Background(), []models.OrderItem{pastItem}); err != nil {
+		t.Fatal(err)
+	}
+	s.createPromotion(manager, models.Promotion{Name: "Lunch", Type: models.PromotionTypeFlat, Value: 5, StartDate: ordered.Add(-time.Hour), EndDate: ordered.Add(time.Hour)})
+	s.createPromotion(manager, models.Promotion{Name: "Dinner", Type: models.PromotionTypeFlat, Value: 10, StartDate: time.Now().Add(-time.Minute)})
+	invoice := s.createInvoice(s.login(models.RoleCashier), order.ID)
+	if invoice.Discount != 5 || len(invoice.Promotions) != 1 || invoice.Promotions[0].Name != "Lunch" {
+		t.Fatalf("expected only the lunch promotion, got %+v", invoice.Promotions)
+	}
+}
@@ -0,0 +1,153 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetOrders(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	s.createOrder(table.ID, models.OrderStatusPlaced)
+	s.createOrder(table.ID, models.OrderStatusServed)
+
+	recorder := s.do(http.MethodGet, "/orders", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if orders := decode[[]models.Order](t, recorder); len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+
+	recorder := s.do(http.MethodGet, "/orders/"+order.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.Order](t, recorder); got.TableID != order.TableID {
+		t.Fatalf("unexpected order %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/orders/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/orders/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestCreateOrder(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+
+	recorder := s.do(http.MethodPost, "/orders", token, models.Order{TableID: table.ID})
+	expectStatus(t, recorder, http.StatusCreated)
+	order, err := s.repos.Orders.Get(context.Background(), insertedID(t, recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != models.OrderStatusPlaced || len(order.StatusHistory) != 1 {
+		t.Fatalf("expected a placed order with history, got %+v", order)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/orders", token, models.Order{TableID: primitive.NewObjectID()}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/orders", token, models.Order{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/orders", s.login(models.RoleChef), models.Order{TableID: table.ID}), http.StatusForbidden)
+}
+
+func TestUpdateOrder(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+	newTable := s.createTable(2, 4)
+
+	expectStatus(t, s.do(http.MethodPatch, "/orders/"+order.ID.Hex(), token, models.Order{TableID: newTable.ID}), http.StatusOK)
+	updated, _ := s.repos.Orders.Get(context.Background(), order.ID)
+	if updated.TableID != newTable.ID {
+		t.Fatalf("order was not moved to the new table")
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, "/orders/"+order.ID.Hex(), token, models.Order{TableID: primitive.NewObjectID()}), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, "/orders/"+primitive.NewObjectID().Hex(), token, models.Order{TableID: newTable.ID}), http.StatusNotFound)
+}
+
+func TestMoveOrderToAnotherTable(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	food := s.createFood("Naan", 40)
+	window := s.createTable(1, 4)
+	patio := s.createTable(2, 4)
+	ids := s.placeOrder(waiter, window.ID, models.OrderItem{FoodID: food.ID, Quantity: 1})
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+
+	expectStatus(t, s.do(http.MethodPatch, "/orders/"+orderItem.OrderID.Hex(), waiter, models.Order{TableID: patio.ID}), http.StatusOK)
+	if status := s.tableStatus(window.ID); status != models.TableStatusFree {
+		t.Fatalf("expected the table left behind to be free, got %q", status)
+	}
+	if status := s.tableStatus(patio.ID); status != models.TableStatusOrdering {
+		t.Fatalf("expected the new table to be ordering, got %q", status)
+	}
+
+	// A table other guests are still ordering at is not freed
+	s.placeOrder(waiter, patio.ID, models.OrderItem{FoodID: food.ID, Quantity: 2})
+	expectStatus(t, s.do(http.MethodPatch, "/orders/"+orderItem.OrderID.Hex(), waiter, models.Order{TableID: window.ID}), http.StatusOK)
+	if status := s.tableStatus(patio.ID); status != models.TableStatusOrdering {
+		t.Fatalf("expected the shared table to stay ordering, got %q", status)
+	}
+	if status := s.tableStatus(window.ID); status != models.TableStatusOrdering {
+		t.Fatalf("expected the new table to be ordering, got %q", status)
+	}
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	chef := s.login(models.RoleChef)
+	cashier := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusPlaced)
+	path := "/orders/" + order.ID.Hex()
+
+	expectStatus(t, s.do(http.MethodPost, path+"/kitchen", waiter, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, path+"/ready", waiter, nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodPost, path+"/ready", chef, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, path+"/serve", waiter, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, path+"/close", cashier, nil), http.StatusOK)
+
+	updated, _ := s.repos.Orders.Get(context.Background(), order.ID)
+	if updated.Status != models.OrderStatusClosed {
+		t.Fatalf("expected closed order, got %s", updated.Status)
+	}
+	if len(updated.StatusHistory) != 4 {
+		t.Fatalf("expected 4 transitions, got %d", len(updated.StatusHistory))
+	}
+	if last := updated.StatusHistory[3]; last.From != models.OrderStatusServed || last.To != models.OrderStatusClosed || last.ChangedBy.IsZero() {
+		t.Fatalf("unexpected last transition %+v", last)
+	}
+}
+
+func TestOrderIllegalTransitions(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	table := s.createTable(1, 4)
+
+	placed := s.createOrder(table.ID, models.OrderStatusPlaced)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+placed.ID.Hex()+"/serve", manager, nil), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+placed.ID.Hex()+"/void", manager, nil), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+placed.ID.Hex()+"/cancel", manager, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+placed.ID.Hex()+"/kitchen", manager, nil), http.StatusConflict)
+
+	inKitchen := s.createOrder(table.ID, models.OrderStatusInKitchen)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+inKitchen.ID.Hex()+"/cancel", manager, nil), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+inKitchen.ID.Hex()+"/void", s.login(models.RoleWaiter), nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+inKitchen.ID.Hex()+"/void", manager, nil), http.StatusOK)
+
+	// Orders created before statuses existed count as placed
+	legacy := s.createOrder(table.ID, "")
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+legacy.ID.Hex()+"/kitchen", manager, nil), http.StatusOK)
+
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+primitive.NewObjectID().Hex()+"/kitchen", manager, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, "/orders/not-an-id/kitchen", manager, nil), http.StatusBadRequest)
+}
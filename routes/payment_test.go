@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) pay(token string, invoiceID primitive.ObjectID, method string, amount float64) models.Payment {
+	s.t.Helper()
+	payment := models.Payment{Method: method, Amount: amount, Reference: "REF-" + method}
+	recorder := s.do(http.MethodPost, "/invoices/"+invoiceID.Hex()+"/payments", token, payment)
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return decode[models.Payment](s.t, recorder)
+}
+
+func TestPaymentsSettleInvoice(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "CGST", Rate: 2.5}, models.TaxComponent{Name: "SGST", Rate: 2.5})
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Paneer Tikka", 250), 1)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 3)
+	invoice := s.createInvoice(cashier, order.ID)
+
+	s.pay(cashier, invoice.ID, models.PaymentMethodCard, 200)
+	s.pay(cashier, invoice.ID, models.PaymentMethodVoucher, 100)
+	partial, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if partial.PaymentStatus != models.PaymentStatusPartiallyPaid || partial.Balance != 88.5 || partial.PaymentMethod != "card, voucher" {
+		t.Fatalf("unexpected invoice %+v", partial)
+	}
+
+	cash := s.pay(cashier, invoice.ID, models.PaymentMethodCash, 100)
+	if cash.ChangeGiven != 11.5 {
+		t.Fatalf("expected 11.5 change, got %v", cash.ChangeGiven)
+	}
+	paid, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if paid.PaymentStatus != models.PaymentStatusPaid || paid.AmountPaid != 388.5 || paid.Balance != 0 {
+		t.Fatalf("unexpected invoice %+v", paid)
+	}
+
+	recorder := s.do(http.MethodGet, "/invoices/"+invoice.ID.Hex()+"/payments", cashier, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if payments := decode[[]models.Payment](t, recorder); len(payments) != 3 || payments[0].Method != models.PaymentMethodCard {
+		t.Fatalf("unexpected payments %+v", payments)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/invoices/"+invoice.ID.Hex()+"/payments", cashier, models.Payment{Method: models.PaymentMethodCash, Amount: 10}), http.StatusConflict)
+}
+
+func TestCardOverpaymentLeavesInvoiceOverpaid(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 2)
+	invoice := s.createInvoice(cashier, order.ID)
+
+	payment := s.pay(cashier, invoice.ID, models.PaymentMethodUPI, 100)
+	if payment.ChangeGiven != 0 {
+		t.Fatalf("only cash gives change, got %v", payment.ChangeGiven)
+	}
+	overpaid, _ := s.repos.Invoices.Get(context.Background(), invoice.ID)
+	if overpaid.PaymentStatus != models.PaymentStatusOverpaid || overpaid.Balance != -20 {
+		t.Fatalf("unexpected invoice %+v", overpaid)
+	}
+}
+
+func TestCreatePaymentValidation(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Naan", 40), 2)
+	invoice := s.createInvoice(cashier, order.ID)
+	path := "/invoices/" + invoice.ID.Hex() + "/payments"
+
+	expectStatus(t, s.do(http.MethodPost, path, cashier, models.Payment{Method: "cheque", Amount: 10}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, path, cashier, models.Payment{Method: models.PaymentMethodCash, Amount: -10}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, path, cashier, models.Payment{Method: models.PaymentMethodVoucher, Amount: 10}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/invoices/"+primitive.NewObjectID().Hex()+"/payments", cashier, models.Payment{Method: models.PaymentMethodCash, Amount: 10}), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, path, s.login(models.RoleWaiter), models.Payment{Method: models.PaymentMethodCash, Amount: 10}), http.StatusForbidden)
+}
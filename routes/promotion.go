@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func PromotionRoutes(router *mux.Router) {
+	promotionGroup := router.PathPrefix("/promotions").Subrouter()
+	promotionGroup.Use(middleware.Authentication)
+	promotionGroup.HandleFunc("", controllers.GetPromotions).Methods("GET")
+	promotionGroup.HandleFunc("/{id}", controllers.GetPromotion).Methods("GET")
+	promotionGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManagePromotions, controllers.CreatePromotion)).Methods("POST")
+	promotionGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManagePromotions, controllers.UpdatePromotion)).Methods("PATCH")
+}
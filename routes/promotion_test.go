@@ -0,0 +1,248 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) createPromotion(token string, promotion models.Promotion) models.Promotion {
+	s.t.Helper()
+	recorder := s.do(http.MethodPost, "/promotions", token, promotion)
+	expectStatus(s.t, recorder, http.StatusCreated)
+	created, err := s.repos.Promotions.Get(context.Background(), insertedID(s.t, recorder))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return created
+}
+
+func TestPromotionsOnInvoice(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "CGST", Rate: 2.5}, models.TaxComponent{Name: "SGST", Rate: 2.5})
+	tikka := s.createFood("Paneer Tikka", 250)
+	naan := s.createFood("Naan", 40)
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, tikka, 1)
+	s.createOrderItem(order.ID, naan, 3)
+
+	s.createPromotion(manager, models.Promotion{Name: "Tikka Tuesday", Type: models.PromotionTypePercentage, Value: 10, FoodIDs: []primitive.ObjectID{tikka.ID}})
+	s.createPromotion(manager, models.Promotion{Name: "Twenty off", Type: models.PromotionTypeFlat, Value: 20})
+	s.createPromotion(manager, models.Promotion{Name: "Big spender", Type: models.PromotionTypeFlat, Value: 100, MinOrderAmount: 1000})
+	s.createPromotion(manager, models.Promotion{Name: "Last year", Type: models.PromotionTypeFlat, Value: 100, EndDate: time.Now().AddDate(-1, 0, 0)})
+
+	invoice := s.createInvoice(cashier, order.ID)
+	if invoice.Discount != 45 || len(invoice.Promotions) != 2 {
+		t.Fatalf("expected 25 + 20 off, got %v from %+v", invoice.Discount, invoice.Promotions)
+	}
+	if invoice.Promotions[0].Explanation != "10% off Paneer Tikka" || invoice.Promotions[1].Explanation != "20.00 off the order" {
+		t.Fatalf("unexpected explanations %+v", invoice.Promotions)
+	}
+	if invoice.Lines[0].Discount != 38.04 || invoice.Lines[1].Discount != 6.96 {
+		t.Fatalf("the flat amount is shared by what the lines cost, got %+v", invoice.Lines)
+	}
+	if invoice.SubTotal != 325 || invoice.GrandTotal != 341.26 {
+		t.Fatalf("tax is charged after the discount, got %v and %v", invoice.SubTotal, invoice.GrandTotal)
+	}
+}
+
+func TestBuyXGetYAndCombo(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	tikka := s.createFood("Paneer Tikka", 250)
+	naan := s.createFood("Naan", 40)
+	raita := s.createFood("Raita", 60)
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, tikka, 1)
+	s.createOrderItem(order.ID, naan, 3)
+	s.createOrderItem(order.ID, raita, 1)
+
+	s.createPromotion(manager, models.Promotion{Name: "Naan deal", Type: models.PromotionTypeBuyXGetY, BuyQuantity: 2, FreeQuantity: 1, FoodIDs: []primitive.ObjectID{naan.ID}})
+	s.createPromotion(manager, models.Promotion{Name: "Tikka meal", Type: models.PromotionTypeCombo, Value: 280, FoodIDs: []primitive.ObjectID{tikka.ID, raita.ID}})
+
+	invoice := s.createInvoice(s.login(models.RoleCashier), order.ID)
+	if len(invoice.Promotions) != 2 || invoice.Promotions[0].Amount != 40 || invoice.Promotions[1].Amount != 30 {
+		t.Fatalf("expected a free naan and 30 off the meal, got %+v", invoice.Promotions)
+	}
+	if invoice.Promotions[0].Explanation != "Buy 2 get 1 free on Naan" || invoice.Promotions[1].Explanation != "Paneer Tikka + Raita for 280.00" {
+		t.Fatalf("unexpected explanations %+v", invoice.Promotions)
+	}
+	if invoice.Lines[0].Discount != 24.19 || invoice.Lines[2].Discount != 5.81 {
+		t.Fatalf("the combo saving is shared by price, got %+v", invoice.Lines)
+	}
+	if invoice.GrandTotal != 360 {
+		t.Fatalf("expected 430 - 70, got %v", invoice.GrandTotal)
+	}
+}
+
+func TestHappyHours(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	now := time.Now()
+	s.createPromotion(manager, models.Promotion{
+		Name:       "Happy hour",
+		Type:       models.PromotionTypePercentage,
+		Value:      50,
+		HappyHours: []models.DailyWindow{{From: now.Add(-time.Hour).Format("15:04"), To: now.Add(time.Hour).Format("15:04")}},
+	})
+	s.createPromotion(manager, models.Promotion{
+		Name:       "Later",
+		Type:       models.PromotionTypePercentage,
+		Value:      10,
+		HappyHours: []models.DailyWindow{{From: now.Add(2 * time.Hour).Format("15:04"), To: now.Add(3 * time.Hour).Format("15:04")}},
+	})
+	order := s.createOrder(s.createTable(3, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Beer", 300), 2)
+
+	invoice := s.createInvoice(s.login(models.RoleCashier), order.ID)
+	if invoice.GrandTotal != 300 || len(invoice.Promotions) != 1 || !strings.Contains(invoice.Promotions[0].Explanation, "happy hour") {
+		t.Fatalf("only the running happy hour applies, got %v from %+v", invoice.GrandTotal, invoice.Promotions)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/promotions", manager, models.Promotion{
+		Name:       "Broken",
+		Type:       models.PromotionTypeFlat,
+		Value:      10,
+		HappyHours: []models.DailyWindow{{From: "25:00", To: "26:00"}},
+	}), http.StatusBadRequest)
+}
+
+func TestCoupons(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	welcome := s.createPromotion(manager, models.Promotion{Name: "Welcome", Type: models.PromotionTypePercentage, Value: 10, CouponCode: "welcome10", UsageLimit: 1})
+	if welcome.CouponCode != "WELCOME10" {
+		t.Fatalf("coupon codes are stored in capitals, got %q", welcome.CouponCode)
+	}
+	s.createPromotion(manager, models.Promotion{Name: "Expired", Type: models.PromotionTypeFlat, Value: 50, CouponCode: "OLD50", EndDate: time.Now().AddDate(0, 0, -1)})
+	expectStatus(t, s.do(http.MethodPost, "/promotions", manager, models.Promotion{Name: "Copy", Type: models.PromotionTypeFlat, Value: 5, CouponCode: "Welcome10"}), http.StatusConflict)
+
+	food := s.createFood("Thali", 200)
+	first := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(first.ID, food, 1)
+	second := s.createOrder(s.createTable(2, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(second.ID, food, 1)
+
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+first.ID.Hex()+"/coupons", cashier, map[string]string{"code": "NOPE"}), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+first.ID.Hex()+"/coupons", cashier, map[string]string{"code": "old50"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+first.ID.Hex()+"/coupons", cashier, map[string]string{"code": "welcome10"}), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+second.ID.Hex()+"/coupons", cashier, map[string]string{"code": "WELCOME10"}), http.StatusOK)
+
+	invoice := s.createInvoice(cashier, first.ID)
+	if invoice.GrandTotal != 180 || len(invoice.Promotions) != 1 || invoice.Promotions[0].Explanation != "Coupon WELCOME10: 10% off the order" {
+		t.Fatalf("unexpected invoice %v with %+v", invoice.GrandTotal, invoice.Promotions)
+	}
+	redeemed, _ := s.repos.Promotions.Get(context.Background(), welcome.ID)
+	if redeemed.UsedCount != 1 {
+		t.Fatalf("expected the coupon to be used once, got %d", redeemed.UsedCount)
+	}
+
+	// The second order entered the coupon before it ran out, so it no longer applies
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+second.ID.Hex()+"/coupons", cashier, map[string]string{"code": "WELCOME10"}), http.StatusConflict)
+	if invoice := s.createInvoice(cashier, second.ID); invoice.GrandTotal != 200 || len(invoice.Promotions) != 0 {
+		t.Fatalf("a used up coupon must not apply, got %v with %+v", invoice.GrandTotal, invoice.Promotions)
+	}
+	expectStatus(t, s.do(http.MethodDelete, "/orders/"+second.ID.Hex()+"/coupons/welcome10", cashier, nil), http.StatusOK)
+	order, _ := s.repos.Orders.Get(context.Background(), second.ID)
+	if len(order.CouponCodes) != 0 {
+		t.Fatalf("expected the coupon to be removed, got %v", order.CouponCodes)
+	}
+}
+
+// usedUpPromotions reports the promotion as used up when it is redeemed
+type usedUpPromotions struct {
+	repository.PromotionRepository
+	usedUp primitive.ObjectID
+}
+
+func (p usedUpPromotions) Redeem(ctx context.Context, id primitive.ObjectID) error {
+	if id == p.usedUp {
+		return repository.ErrConflict
+	}
+	return p.PromotionRepository.Redeem(ctx, id)
+}
+
+func TestCouponsAreRedeemedAllOrNothing(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	welcome := s.createPromotion(manager, models.Promotion{Name: "Welcome", Type: models.PromotionTypeFlat, Value: 20, CouponCode: "WELCOME20"})
+	festive := s.createPromotion(manager, models.Promotion{Name: "Festive", Type: models.PromotionTypeFlat, Value: 10, CouponCode: "FESTIVE10"})
+	order := s.createOrder(s.createTable(1, 4).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, s.createFood("Thali", 200), 1)
+	for _, code := range []string{"WELCOME20", "FESTIVE10"} {
+		expectStatus(t, s.do(http.MethodPost, "/orders/"+order.ID.Hex()+"/coupons", cashier, map[string]string{"code": code}), http.StatusOK)
+	}
+
+	// The second coupon runs out while the bill is being made
+	promotions := s.repos.Promotions
+	s.repos.Promotions = usedUpPromotions{promotions, festive.ID}
+	expectStatus(t, s.do(http.MethodPost, "/invoices", cashier, models.Invoice{OrderID: order.ID}), http.StatusConflict)
+	s.repos.Promotions = promotions
+	if promotion, _ := s.repos.Promotions.Get(context.Background(), welcome.ID); promotion.UsedCount != 0 {
+		t.Fatalf("the first coupon must be given back, got %d uses", promotion.UsedCount)
+	}
+
+	invoice := s.createInvoice(cashier, order.ID)
+	if invoice.GrandTotal != 170 || len(invoice.Promotions) != 2 {
+		t.Fatalf("unexpected invoice %v with %+v", invoice.GrandTotal, invoice.Promotions)
+	}
+}
+
+func TestGetPromotions(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	for _, name := range []string{"Weekend special", "Lunch deal", "Festive offer"} {
+		s.createPromotion(manager, models.Promotion{Name: name, Type: models.PromotionTypeFlat, Value: 10})
+	}
+
+	// Promotions are read by whoever takes the order, not only by managers
+	recorder := s.do(http.MethodGet, "/promotions?limit=2", s.login(models.RoleWaiter), nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if promotions := decode[[]models.Promotion](t, recorder); len(promotions) != 2 {
+		t.Fatalf("expected 2 promotions, got %d", len(promotions))
+	}
+	recorder = s.do(http.MethodGet, "/promotions?skip=2", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if promotions := decode[[]models.Promotion](t, recorder); len(promotions) != 1 {
+		t.Fatalf("expected 1 promotion after skipping 2, got %d", len(promotions))
+	}
+	expectStatus(t, s.do(http.MethodGet, "/promotions", "", nil), http.StatusUnauthorized)
+}
+
+func TestPromotionValidation(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	naan := s.createFood("Naan", 40)
+
+	for _, promotion := range []models.Promotion{
+		{Name: "Too much", Type: models.PromotionTypePercentage, Value: 150},
+		{Name: "Nothing", Type: models.PromotionTypeFlat},
+		{Name: "Free lunch", Type: models.PromotionTypeBuyXGetY, BuyQuantity: 2},
+		{Name: "Lonely combo", Type: models.PromotionTypeCombo, Value: 30, FoodIDs: []primitive.ObjectID{naan.ID}},
+		{Name: "Ghost", Type: models.PromotionTypeFlat, Value: 10, FoodIDs: []primitive.ObjectID{primitive.NewObjectID()}},
+		{Name: "Limited", Type: models.PromotionTypeFlat, Value: 10, UsageLimit: 5},
+		{Name: "Backwards", Type: models.PromotionTypeFlat, Value: 10, StartDate: time.Now(), EndDate: time.Now().AddDate(0, 0, -1)},
+		{Name: "Unknown", Type: "mystery", Value: 10},
+	} {
+		expectStatus(t, s.do(http.MethodPost, "/promotions", manager, promotion), http.StatusBadRequest)
+	}
+	expectStatus(t, s.do(http.MethodPost, "/promotions", s.login(models.RoleWaiter), models.Promotion{Name: "Sneaky", Type: models.PromotionTypeFlat, Value: 10}), http.StatusForbidden)
+
+	promotion := s.createPromotion(manager, models.Promotion{Name: "Naan deal", Type: models.PromotionTypeFlat, Value: 10, FoodIDs: []primitive.ObjectID{naan.ID}})
+	expectStatus(t, s.do(http.MethodPatch, "/promotions/"+promotion.ID.Hex(), manager, models.Promotion{Name: "Naan deal", Type: models.PromotionTypeFlat, Value: 15}), http.StatusOK)
+	updated := decode[models.Promotion](t, s.do(http.MethodGet, "/promotions/"+promotion.ID.Hex(), manager, nil))
+	if updated.Value != 15 || len(updated.FoodIDs) != 1 {
+		t.Fatalf("unexpected promotion %+v", updated)
+	}
+	expectStatus(t, s.do(http.MethodGet, "/promotions/"+primitive.NewObjectID().Hex(), manager, nil), http.StatusNotFound)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func PurchaseOrderRoutes(router *mux.Router) {
+	purchaseOrderGroup := router.PathPrefix("/purchaseOrders").Subrouter()
+	purchaseOrderGroup.Use(middleware.Authentication)
+	purchaseOrderGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetPurchaseOrders)).Methods("GET")
+	purchaseOrderGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetPurchaseOrder)).Methods("GET")
+	purchaseOrderGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageInventory, controllers.CreatePurchaseOrder)).Methods("POST")
+	purchaseOrderGroup.HandleFunc("/{id}/receipts", middleware.Authorize(helpers.PermissionManageInventory, controllers.ReceivePurchaseOrder)).Methods("POST")
+	purchaseOrderGroup.HandleFunc("/{id}/cancel", middleware.Authorize(helpers.PermissionManageInventory, controllers.CancelPurchaseOrder)).Methods("POST")
+}
@@ -0,0 +1,190 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) createSupplier(token string, name string) primitive.ObjectID {
+	s.t.Helper()
+	recorder := s.do(http.MethodPost, "/suppliers", token, models.Supplier{Name: name})
+	expectStatus(s.t, recorder, http.StatusCreated)
+	return insertedID(s.t, recorder)
+}
+
+func TestPurchaseOrdersReplenishStock(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	recorder := s.do(http.MethodPost, "/suppliers", manager, models.Supplier{Name: "Fresh Farms", Phone: "9800000000"})
+	expectStatus(t, recorder, http.StatusCreated)
+	supplier := insertedID(t, recorder)
+	expectStatus(t, s.do(http.MethodPost, "/suppliers", manager, models.Supplier{Name: "Bad mail", Email: "not-an-email"}), http.StatusBadRequest)
+	chicken := s.createIngredient(manager, "Chicken", "g", 2000, 1500)
+
+	order := func(lines ...models.PurchaseOrderLine) *httptest.ResponseRecorder {
+		return s.do(http.MethodPost, "/purchaseOrders", manager, models.PurchaseOrder{SupplierID: supplier, Lines: lines})
+	}
+	expectStatus(t, s.do(http.MethodPost, "/purchaseOrders", manager, models.PurchaseOrder{SupplierID: primitive.NewObjectID(), Lines: []models.PurchaseOrderLine{{IngredientID: chicken, Quantity: 1}}}), http.StatusBadRequest)
+	expectStatus(t, order(models.PurchaseOrderLine{IngredientID: primitive.NewObjectID(), Quantity: 1}), http.StatusBadRequest)
+	recorder = order(models.PurchaseOrderLine{IngredientID: chicken, Quantity: 1000, UnitCost: 0.3})
+	expectStatus(t, recorder, http.StatusCreated)
+	purchaseOrder, _ := s.repos.PurchaseOrders.Get(context.Background(), insertedID(t, recorder))
+	if purchaseOrder.Status != models.PurchaseOrderStatusOrdered || purchaseOrder.Total != 300 || purchaseOrder.Lines[0].IngredientName != "Chicken" {
+		t.Fatalf("unexpected purchase order %+v", purchaseOrder)
+	}
+
+	receive := func(quantity float64, unitCost float64) *httptest.ResponseRecorder {
+		receipt := models.GoodsReceipt{Lines: []models.GoodsReceiptLine{{IngredientID: chicken, Quantity: quantity, UnitCost: unitCost}}}
+		return s.do(http.MethodPost, "/purchaseOrders/"+purchaseOrder.ID.Hex()+"/receipts", manager, receipt)
+	}
+	recorder = receive(400, 0)
+	expectStatus(t, recorder, http.StatusCreated)
+	if received := decode[models.PurchaseOrder](t, recorder); received.Status != models.PurchaseOrderStatusPartiallyReceived || received.Lines[0].Received != 400 {
+		t.Fatalf("expected 400 g received, got %+v", received)
+	}
+	if stock := s.stockOf(manager, chicken); stock != 2400 {
+		t.Fatalf("expected 2400 g of chicken, got %v", stock)
+	}
+
+	recorder = receive(700, 0)
+	expectStatus(t, recorder, http.StatusBadRequest)
+	if message := decode[string](t, recorder); message != "Only 600 g of Chicken left to receive" {
+		t.Fatalf("unexpected message %q", message)
+	}
+
+	// The supplier invoiced the rest at a higher price
+	recorder = receive(600, 0.35)
+	expectStatus(t, recorder, http.StatusCreated)
+	if received := decode[models.PurchaseOrder](t, recorder); received.Status != models.PurchaseOrderStatusReceived || len(received.Receipts) != 2 {
+		t.Fatalf("expected the order to be received, got %+v", received)
+	}
+	ingredient, _ := s.repos.Ingredients.Get(context.Background(), chicken)
+	if ingredient.Stock != 3000 || ingredient.CostPrice != 0.35 || ingredient.SupplierID != supplier {
+		t.Fatalf("unexpected ingredient %+v", ingredient)
+	}
+	expectStatus(t, receive(1, 0), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/purchaseOrders/"+purchaseOrder.ID.Hex()+"/cancel", manager, nil), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodGet, "/purchaseOrders", s.login(models.RoleWaiter), nil), http.StatusForbidden)
+}
+
+func TestReorderSuggestions(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	waiter := s.login(models.RoleWaiter)
+	recorder := s.do(http.MethodPost, "/suppliers", manager, models.Supplier{Name: "Fresh Farms"})
+	supplier := insertedID(t, recorder)
+	chicken := s.createIngredient(manager, "Chicken", "g", 2000, 1500)
+	s.createIngredient(manager, "Rice", "g", 9000, 1000)
+	biryani := s.createFood("Biryani", 300)
+	recipe := models.Recipe{FoodID: biryani.ID, Ingredients: []models.RecipeIngredient{{IngredientID: chicken, Quantity: 250}}}
+	expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", manager, recipe), http.StatusCreated)
+	s.placeOrder(waiter, s.createTable(1, 4).ID, models.OrderItem{FoodID: biryani.ID, Quantity: 4})
+
+	// 1000 g used in the last 10 days is 100 g a day, so a week on top of
+	// the threshold needs 1500 + 700 - 1000 g
+	suggestions := decode[[]controllers.ReorderSuggestion](t, s.do(http.MethodGet, "/inventory/reorder?days=10&cover=7", manager, nil))
+	if len(suggestions) != 1 || suggestions[0].IngredientID != chicken || suggestions[0].DailyUse != 100 || suggestions[0].Quantity != 1200 {
+		t.Fatalf("unexpected suggestions %+v", suggestions)
+	}
+
+	body := models.PurchaseOrder{SupplierID: supplier, Lines: []models.PurchaseOrderLine{{IngredientID: chicken, Quantity: 1000, UnitCost: 0.3}}}
+	expectStatus(t, s.do(http.MethodPost, "/purchaseOrders", manager, body), http.StatusCreated)
+	suggestions = decode[[]controllers.ReorderSuggestion](t, s.do(http.MethodGet, "/inventory/reorder?days=10&cover=7", manager, nil))
+	if len(suggestions) != 1 || suggestions[0].OnOrder != 1000 || suggestions[0].Quantity != 200 {
+		t.Fatalf("what is on order should be left out, got %+v", suggestions)
+	}
+}
+
+func TestGetSuppliers(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	for _, name := range []string{"Fresh Farms", "Spice Route", "Dairy Direct"} {
+		s.createSupplier(manager, name)
+	}
+
+	recorder := s.do(http.MethodGet, "/suppliers?limit=2", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if suppliers := decode[[]models.Supplier](t, recorder); len(suppliers) != 2 {
+		t.Fatalf("expected 2 suppliers, got %d", len(suppliers))
+	}
+	expectStatus(t, s.do(http.MethodGet, "/suppliers", s.login(models.RoleWaiter), nil), http.StatusForbidden)
+}
+
+func TestGetSupplier(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	id := s.createSupplier(manager, "Fresh Farms")
+
+	recorder := s.do(http.MethodGet, "/suppliers/"+id.Hex(), manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if supplier := decode[models.Supplier](t, recorder); supplier.ID != id || supplier.Name != "Fresh Farms" {
+		t.Fatalf("unexpected supplier %+v", supplier)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/suppliers/"+primitive.NewObjectID().Hex(), manager, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/suppliers/not-an-id", manager, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/suppliers/"+id.Hex(), s.login(models.RoleChef), nil), http.StatusForbidden)
+}
+
+func TestUpdateSupplier(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	id := s.createSupplier(manager, "Fresh Farms")
+	path := "/suppliers/" + id.Hex()
+
+	body := models.Supplier{Name: "Fresh Farms Co", Phone: "9800000000", Email: "orders@freshfarms.test"}
+	expectStatus(t, s.do(http.MethodPatch, path, manager, body), http.StatusOK)
+	updated, _ := s.repos.Suppliers.Get(context.Background(), id)
+	if updated.Name != "Fresh Farms Co" || updated.Phone != "9800000000" || updated.Email != "orders@freshfarms.test" || updated.CreatedOn.IsZero() {
+		t.Fatalf("unexpected supplier %+v", updated)
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, path, manager, models.Supplier{Name: "Fresh Farms", Email: "not-an-email"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/suppliers/"+primitive.NewObjectID().Hex(), manager, body), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, "/suppliers/not-an-id", manager, body), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, path, s.login(models.RoleWaiter), body), http.StatusForbidden)
+}
+
+func TestGetPurchaseOrders(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	supplier := s.createSupplier(manager, "Fresh Farms")
+	chicken := s.createIngredient(manager, "Chicken", "g", 2000, 1500)
+	for i := 0; i < 3; i++ {
+		body := models.PurchaseOrder{SupplierID: supplier, Lines: []models.PurchaseOrderLine{{IngredientID: chicken, Quantity: 1000, UnitCost: 0.3}}}
+		expectStatus(t, s.do(http.MethodPost, "/purchaseOrders", manager, body), http.StatusCreated)
+	}
+
+	recorder := s.do(http.MethodGet, "/purchaseOrders?limit=2", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if purchaseOrders := decode[[]models.PurchaseOrder](t, recorder); len(purchaseOrders) != 2 {
+		t.Fatalf("expected 2 purchase orders, got %d", len(purchaseOrders))
+	}
+}
+
+func TestGetPurchaseOrder(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	supplier := s.createSupplier(manager, "Fresh Farms")
+	chicken := s.createIngredient(manager, "Chicken", "g", 2000, 1500)
+	body := models.PurchaseOrder{SupplierID: supplier, Lines: []models.PurchaseOrderLine{{IngredientID: chicken, Quantity: 1000, UnitCost: 0.3}}}
+	recorder := s.do(http.MethodPost, "/purchaseOrders", manager, body)
+	expectStatus(t, recorder, http.StatusCreated)
+	id := insertedID(t, recorder)
+
+	recorder = s.do(http.MethodGet, "/purchaseOrders/"+id.Hex(), manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if purchaseOrder := decode[models.PurchaseOrder](t, recorder); purchaseOrder.ID != id || purchaseOrder.SupplierID != supplier || purchaseOrder.Total != 300 || len(purchaseOrder.Lines) != 1 {
+		t.Fatalf("unexpected purchase order %+v", purchaseOrder)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/purchaseOrders/"+primitive.NewObjectID().Hex(), manager, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/purchaseOrders/not-an-id", manager, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/purchaseOrders/"+id.Hex(), s.login(models.RoleWaiter), nil), http.StatusForbidden)
+}
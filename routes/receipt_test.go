@@ -0,0 +1,127 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// receiptInvoice stores a paid invoice with fixed contents so its receipts
+// can be compared byte for byte
+func (s *testServer) receiptInvoice() models.Invoice {
+	s.t.Helper()
+	s.t.Setenv("RESTAURANT_NAME", "Spice Route")
+	s.t.Setenv("RESTAURANT_ADDRESS", "12 MG Road, Indiranagar, Bengaluru 560038")
+	s.t.Setenv("RESTAURANT_PHONE", "080 4123 4567")
+	s.t.Setenv("RESTAURANT_GSTIN", "29ABCDE1234F1Z5")
+
+	tax := func(amount float64) []models.InvoiceTax {
+		return []models.InvoiceTax{{Name: "CGST", Rate: 2.5, Amount: amount}, {Name: "SGST", Rate: 2.5, Amount: amount}}
+	}
+	invoice := models.Invoice{
+		Number:      "BLR1/2026-27/000123",
+		OrderID:     primitive.NewObjectID(),
+		TableNumber: 7,
+		Lines: []models.InvoiceLine{
+			{FoodName: "Paneer Tikka", UnitPrice: 250, Quantity: 1, Amount: 250, NetAmount: 250, Taxes: tax(6.25)},
+			{FoodName: "Butter Garlic Naan with Extra Butter (Tandoor)", UnitPrice: 40, Quantity: 3, Amount: 120, NetAmount: 120, Taxes: tax(3)},
+		},
+		SubTotal:      370,
+		Taxes:         tax(9.25),
+		ServiceCharge: 18.5,
+		GrandTotal:    407,
+		PaymentMethod: "card, cash",
+		PaymentStatus: models.PaymentStatusPaid,
+		AmountPaid:    407,
+		CreatedOn:     time.Date(2026, time.October, 18, 20, 45, 0, 0, time.Local),
+	}
+	if err := s.repos.Invoices.Create(context.Background(), &invoice); err != nil {
+		s.t.Fatal(err)
+	}
+	for _, payment := range []models.Payment{
+		{InvoiceID: invoice.ID, Method: models.PaymentMethodCard, Amount: 300, Reference: "XX4242"},
+		{InvoiceID: invoice.ID, Method: models.PaymentMethodCash, Amount: 200, ChangeGiven: 93},
+	} {
+		if err := s.repos.Payments.Create(context.Background(), &payment); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	return invoice
+}
+
+func expectGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s does not match, run go test ./routes -update to see the difference:\n%s", name, got)
+	}
+}
+
+func TestInvoiceReceipt(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	invoice := s.receiptInvoice()
+	path := "/invoices/" + invoice.ID.Hex() + "/receipt"
+
+	for _, golden := range []struct {
+		query       string
+		file        string
+		contentType string
+	}{
+		{"?format=text", "receipt_80mm.txt", "text/plain; charset=utf-8"},
+		{"?format=text&paper=58mm", "receipt_58mm.txt", "text/plain; charset=utf-8"},
+		{"?format=escpos", "receipt_80mm.escpos", "application/octet-stream"},
+		{"?format=escpos&paper=58mm", "receipt_58mm.escpos", "application/octet-stream"},
+		{"?format=pdf", "receipt.pdf", "application/pdf"},
+	} {
+		recorder := s.do(http.MethodGet, path+golden.query, token, nil)
+		expectStatus(t, recorder, http.StatusOK)
+		if contentType := recorder.Header().Get("Content-Type"); contentType != golden.contentType {
+			t.Fatalf("expected %s for %s, got %s", golden.contentType, golden.query, contentType)
+		}
+		expectGolden(t, golden.file, recorder.Body.Bytes())
+	}
+
+	expectStatus(t, s.do(http.MethodGet, path+"?format=html", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, path+"?format=text&paper=110mm", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/invoices/"+primitive.NewObjectID().Hex()+"/receipt", token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, path, s.login(models.RoleChef), nil), http.StatusForbidden)
+}
+
+func TestReceiptWrapsLongHeader(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleCashier)
+	invoice := s.receiptInvoice()
+	t.Setenv("RESTAURANT_PHONE", "080 4123 4567, 080 4123 4568, +91 98450 12345")
+	t.Setenv("RESTAURANT_GSTIN", "29ABCDE1234F1Z5-29ABCDE1234F1Z6-29ABCDE1234F1Z7")
+
+	recorder := s.do(http.MethodGet, "/invoices/"+invoice.ID.Hex()+"/receipt?format=text&paper=58mm", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	for _, line := range bytes.Split(recorder.Body.Bytes(), []byte("\n")) {
+		if len(line) > 32 {
+			t.Fatalf("line %q is wider than the paper", line)
+		}
+	}
+	if !bytes.Contains(recorder.Body.Bytes(), []byte("+91 98450 12345")) {
+		t.Fatalf("expected every phone number printed, got:\n%s", recorder.Body.Bytes())
+	}
+}
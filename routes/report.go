@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func ReportRoutes(router *mux.Router) {
+	reportGroup := router.PathPrefix("/reports").Subrouter()
+	reportGroup.Use(middleware.Authentication)
+	reportGroup.HandleFunc("/margins", middleware.Authorize(helpers.PermissionReadReports, controllers.GetMarginReport)).Methods("GET")
+	reportGroup.HandleFunc("/sellers", middleware.Authorize(helpers.PermissionReadReports, controllers.GetSellerReport)).Methods("GET")
+	reportGroup.HandleFunc("/categories", middleware.Authorize(helpers.PermissionReadReports, controllers.GetCategoryMix)).Methods("GET")
+	reportGroup.HandleFunc("/heatmap", middleware.Authorize(helpers.PermissionReadReports, controllers.GetHeatmap)).Methods("GET")
+	reportGroup.HandleFunc("/sales", middleware.Authorize(helpers.PermissionReadReports, controllers.GetSalesSummary)).Methods("GET")
+	reportGroup.HandleFunc("/z-reports", middleware.Authorize(helpers.PermissionReadReports, controllers.GetZReports)).Methods("GET")
+	reportGroup.HandleFunc("/z-reports/{date}", middleware.Authorize(helpers.PermissionReadReports, controllers.GetZReport)).Methods("GET")
+	reportGroup.HandleFunc("/z-reports", middleware.Authorize(helpers.PermissionCloseDay, controllers.CloseDay)).Methods("POST")
+}
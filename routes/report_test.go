@@ -0,0 +1,264 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMarginReport(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	waiter := s.login(models.RoleWaiter)
+	chicken := s.createIngredient(manager, "Chicken", "g", 5000, 0)
+	paneer := s.createIngredient(manager, "Paneer", "g", 5000, 0)
+	s.repos.Ingredients.SetCost(context.Background(), chicken, 0.3, primitive.NilObjectID)
+	s.repos.Ingredients.SetCost(context.Background(), paneer, 1.2, primitive.NilObjectID)
+
+	biryani := s.createFood("Biryani", 300)
+	tikka := s.createFood("Paneer Tikka", 200)
+	tea := s.createFood("Tea", 50)
+	for food, recipe := range map[primitive.ObjectID]models.RecipeIngredient{
+		biryani.ID: {IngredientID: chicken, Quantity: 250},
+		tikka.ID:   {IngredientID: paneer, Quantity: 100},
+	} {
+		body := models.Recipe{FoodID: food, Ingredients: []models.RecipeIngredient{recipe}}
+		expectStatus(t, s.do(http.MethodPost, "/inventory/recipes", manager, body), http.StatusCreated)
+	}
+
+	table := s.createTable(1, 4)
+	s.placeOrder(waiter, table.ID,
+		models.OrderItem{FoodID: biryani.ID, Quantity: 4},
+		models.OrderItem{FoodID: tikka.ID, Quantity: 2},
+		models.OrderItem{FoodID: tea.ID, Quantity: 10},
+	)
+	// Cancelled orders were never sold
+	s.createOrderItem(s.createOrder(table.ID, models.OrderStatusCancelled).ID, biryani, 5)
+
+	expectStatus(t, s.do(http.MethodGet, "/reports/margins", waiter, nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodGet, "/reports/margins?from=yesterday", manager, nil), http.StatusBadRequest)
+	recorder := s.do(http.MethodGet, "/reports/margins", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	report := decode[controllers.MarginReport](t, recorder)
+	if report.Revenue != 2100 || report.Cost != 540 || report.Margin != 1560 || len(report.Dishes) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	first, second, third := report.Dishes[0], report.Dishes[1], report.Dishes[2]
+	if first.FoodID != biryani.ID || first.PlateCost != 75 || first.UnitMargin != 225 || first.MarginPercent != 75 || first.Quantity != 4 || first.Margin != 900 {
+		t.Fatalf("unexpected biryani margin %+v", first)
+	}
+	if second.FoodID != tea.ID || second.Costed || second.Margin != 500 {
+		t.Fatalf("tea has no recipe, got %+v", second)
+	}
+	if third.FoodID != tikka.ID || third.PlateCost != 120 || third.Revenue != 400 || third.Cost != 240 || third.Margin != 160 {
+		t.Fatalf("unexpected paneer tikka margin %+v", third)
+	}
+
+	// Revenue is what was left after promotions
+	s.createPromotion(manager, models.Promotion{Name: "Tikka Tuesday", Type: models.PromotionTypePercentage, Value: 10, FoodIDs: []primitive.ObjectID{tikka.ID}})
+	order := s.createOrder(table.ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, tikka, 1)
+	s.createInvoice(s.login(models.RoleCashier), order.ID)
+	report = decode[controllers.MarginReport](t, s.do(http.MethodGet, "/reports/margins", manager, nil))
+	if report.Discounts != 20 || report.Revenue != 2280 || report.Cost != 660 || report.Margin != 1620 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if tikkaMargin := report.Dishes[2]; tikkaMargin.FoodID != tikka.ID || tikkaMargin.Quantity != 3 || tikkaMargin.Discounts != 20 || tikkaMargin.Revenue != 580 || tikkaMargin.Margin != 220 {
+		t.Fatalf("unexpected paneer tikka margin %+v", tikkaMargin)
+	}
+
+	// Nothing was sold a year ago
+	from := time.Now().AddDate(-1, 0, 0).Format(time.RFC3339)
+	to := time.Now().AddDate(-1, 1, 0).Format(time.RFC3339)
+	report = decode[controllers.MarginReport](t, s.do(http.MethodGet, "/reports/margins?from="+from+"&to="+to, manager, nil))
+	if report.Revenue != 0 || report.Dishes[0].FoodID != biryani.ID || report.Dishes[0].Quantity != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	expectStatus(t, s.do(http.MethodGet, "/reports/margins?from="+to+"&to="+from, manager, nil), http.StatusBadRequest)
+}
+
+func TestDailySalesAndZReport(t *testing.T) {
+	s := newTestServer(t)
+	cashier := s.login(models.RoleCashier)
+	manager := s.login(models.RoleManager)
+	s.createTaxRate(models.TaxCategoryDefault, false, models.TaxComponent{Name: "CGST", Rate: 2.5}, models.TaxComponent{Name: "SGST", Rate: 2.5})
+	paneer := s.createFood("Paneer Tikka", 250)
+	naan := s.createFood("Naan", 40)
+	seat := func(number int, guests int) models.Order {
+		order := models.Order{TableID: s.createTable(number, 4).ID, Guests: guests, Status: models.OrderStatusServed, CreatedOn: time.Now()}
+		if err := s.repos.Orders.Create(context.Background(), &order); err != nil {
+			t.Fatal(err)
+		}
+		return order
+	}
+
+	family := seat(1, 3)
+	s.createOrderItem(family.ID, paneer, 1)
+	naans := s.createOrderItem(family.ID, naan, 3)
+	familyInvoice := s.createInvoice(cashier, family.ID)
+	s.pay(cashier, familyInvoice.ID, models.PaymentMethodCard, 200)
+	s.pay(cashier, familyInvoice.ID, models.PaymentMethodCash, 200)
+	couple := seat(2, 2)
+	s.createOrderItem(couple.ID, naan, 1)
+	s.pay(cashier, s.createInvoice(cashier, couple.ID).ID, models.PaymentMethodUPI, 42)
+	refund := s.raiseCreditNote(cashier, models.CreditNote{
+		InvoiceID:    familyInvoice.ID,
+		Lines:        []models.CreditNoteLine{{OrderItemID: naans.ID, Quantity: 1}},
+		RefundMethod: models.PaymentMethodCash,
+	})
+	s.approveCreditNote(manager, refund.ID)
+
+	expectStatus(t, s.do(http.MethodGet, "/reports/sales", cashier, nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodGet, "/reports/sales?date=18-10-2026", manager, nil), http.StatusBadRequest)
+	recorder := s.do(http.MethodGet, "/reports/sales", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	summary := decode[models.SalesSummary](t, recorder)
+	if summary.Closed || summary.Checks != 2 || summary.Covers != 5 || summary.GrossSales != 410 || summary.NetSales != 410 || summary.TaxTotal != 20.5 || summary.Total != 430.5 {
+		t.Fatalf("unexpected sales %+v", summary)
+	}
+	if len(summary.Taxes) != 2 || summary.Taxes[0].Amount != 10.25 || summary.AverageCheck != 215.25 || summary.AveragePerCover != 86.1 {
+		t.Fatalf("unexpected taxes or averages %+v", summary)
+	}
+	payments := summary.Payments
+	if len(payments) != 3 || payments[0].Method != models.PaymentMethodCard || payments[1].Amount != 188.5 || payments[2].Amount != 42 || summary.Collected != 430.5 {
+		t.Fatalf("unexpected payments %+v", payments)
+	}
+	if summary.CreditNotes != 1 || summary.Refunded != 42 || summary.Refunds[0].Method != models.PaymentMethodCash || summary.NetCollected != 388.5 {
+		t.Fatalf("unexpected refunds %+v", summary)
+	}
+
+	// A day is closed once it has ended, so nothing sold later that day is lost
+	today := time.Now().Format("2006-01-02")
+	expectStatus(t, s.do(http.MethodPost, "/reports/z-reports", cashier, controllers.DayClose{Date: today}), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodPost, "/reports/z-reports", manager, controllers.DayClose{Date: today}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/reports/z-reports", manager, controllers.DayClose{Date: time.Now().AddDate(0, 0, 1).Format("2006-01-02")}), http.StatusBadRequest)
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	billYesterday := func(total float64) {
+		invoice := models.Invoice{OrderID: seat(9, 2).ID, SubTotal: total, GrandTotal: total, CreatedOn: yesterday}
+		if err := s.repos.Invoices.Create(context.Background(), &invoice); err != nil {
+			t.Fatal(err)
+		}
+	}
+	billYesterday(100)
+	date := yesterday.Format("2006-01-02")
+	expectStatus(t, s.do(http.MethodGet, "/reports/z-reports/"+date, manager, nil), http.StatusNotFound)
+	recorder = s.do(http.MethodPost, "/reports/z-reports", manager, controllers.DayClose{Date: date})
+	expectStatus(t, recorder, http.StatusCreated)
+	zReport := decode[models.ZReport](t, recorder)
+	if zReport.Number != 1 || !zReport.Closed || zReport.Total != 100 || zReport.Covers != 2 || zReport.ClosedBy.IsZero() {
+		t.Fatalf("unexpected Z-report %+v", zReport)
+	}
+	expectStatus(t, s.do(http.MethodPost, "/reports/z-reports", manager, controllers.DayClose{Date: date}), http.StatusConflict)
+
+	// The closed day no longer changes
+	billYesterday(50)
+	summary = decode[models.SalesSummary](t, s.do(http.MethodGet, "/reports/sales?date="+date, manager, nil))
+	if !summary.Closed || summary.Total != 100 || summary.Checks != 1 {
+		t.Fatalf("a closed day must not change, got %+v", summary)
+	}
+	zReports := decode[[]models.ZReport](t, s.do(http.MethodGet, "/reports/z-reports", manager, nil))
+	if len(zReports) != 1 || zReports[0].Date != date {
+		t.Fatalf("unexpected Z-reports %+v", zReports)
+	}
+
+	// A sale paid after yesterday was closed counts today
+	late := seat(3, 1)
+	s.createOrderItem(late.ID, paneer, 1)
+	s.pay(cashier, s.createInvoice(cashier, late.ID).ID, models.PaymentMethodCard, 262.5)
+	summary = decode[models.SalesSummary](t, s.do(http.MethodGet, "/reports/sales", manager, nil))
+	if summary.Closed || summary.Total != 693 || summary.Checks != 3 || summary.Payments[0].Amount != 462.5 {
+		t.Fatalf("the late sale must count today, got %+v", summary)
+	}
+}
+
+func TestSalesAnalytics(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	biryani := s.createFood("Biryani", 300)
+	naan := s.createFood("Naan", 40)
+	soup := s.createFood("Soup", 120)
+	desserts := models.Menu{Name: "Desserts", Category: "Dessert"}
+	s.repos.Menus.Create(context.Background(), &desserts)
+	kulfi := models.Food{Name: "Kulfi", Price: 80, FoodImage: "kulfi.png", MenuID: desserts.ID}
+	s.repos.Foods.Create(context.Background(), &kulfi)
+
+	table := s.createTable(1, 4)
+	friday := func(hour int, minute int) time.Time {
+		return time.Date(2026, time.October, 16, hour, minute, 0, 0, time.Local)
+	}
+	order := func(status string, items ...models.OrderItem) {
+		orderID := s.createOrder(table.ID, status).ID
+		for i := range items {
+			items[i].OrderID = orderID
+		}
+		if _, err := s.repos.OrderItems.CreateMany(context.Background(), items); err != nil {
+			t.Fatal(err)
+		}
+	}
+	order(models.OrderStatusClosed,
+		models.OrderItem{FoodID: biryani.ID, Quantity: 2, UnitPrice: 300, CreatedOn: friday(20, 10)},
+		models.OrderItem{FoodID: naan.ID, Quantity: 4, UnitPrice: 40, CreatedOn: friday(20, 15)},
+		models.OrderItem{FoodID: kulfi.ID, Quantity: 1, UnitPrice: 80, CreatedOn: friday(21, 5)},
+	)
+	order(models.OrderStatusClosed,
+		models.OrderItem{FoodID: naan.ID, Quantity: 6, UnitPrice: 40, CreatedOn: friday(20, 30)},
+		models.OrderItem{FoodID: kulfi.ID, Quantity: 3, UnitPrice: 80, CreatedOn: friday(20, 30).AddDate(0, 0, 2)},
+	)
+	order(models.OrderStatusCancelled, models.OrderItem{FoodID: biryani.ID, Quantity: 10, UnitPrice: 300, CreatedOn: friday(20, 0)})
+
+	period := "?from=" + url.QueryEscape(friday(0, 0).AddDate(0, 0, -4).Format(time.RFC3339)) + "&to=" + url.QueryEscape(friday(0, 0).AddDate(0, 0, 3).Format(time.RFC3339))
+	expectStatus(t, s.do(http.MethodGet, "/reports/sellers"+period, s.login(models.RoleWaiter), nil), http.StatusForbidden)
+	recorder := s.do(http.MethodGet, "/reports/sellers"+period+"&limit=2", manager, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	sellers := decode[controllers.SellerReport](t, recorder)
+	names := func(ranks []controllers.SellerRank) string {
+		names := []string{}
+		for _, rank := range ranks {
+			names = append(names, rank.FoodName)
+		}
+		return strings.Join(names, ", ")
+	}
+	if best := names(sellers.BestByQuantity); best != "Naan, Kulfi" {
+		t.Fatalf("unexpected best sellers by quantity %s", best)
+	}
+	if best := names(sellers.BestByRevenue); best != "Biryani, Naan" {
+		t.Fatalf("unexpected best sellers by revenue %s", best)
+	}
+	// Foods that did not sell at all are the worst sellers
+	if worst := names(sellers.WorstByQuantity); worst != "Soup, Biryani" {
+		t.Fatalf("unexpected worst sellers by quantity %s", worst)
+	}
+	if worst := names(sellers.WorstByRevenue); worst != "Soup, Kulfi" {
+		t.Fatalf("unexpected worst sellers by revenue %s", worst)
+	}
+	if top := sellers.BestByRevenue[0]; top.Quantity != 2 || top.Revenue != 600 || top.RevenueShare != 45.45 || top.Category != "Main" {
+		t.Fatalf("unexpected best seller %+v", top)
+	}
+	if sellers.WorstByQuantity[0].FoodID != soup.ID || sellers.WorstByQuantity[0].Quantity != 0 {
+		t.Fatalf("unexpected worst seller %+v", sellers.WorstByQuantity[0])
+	}
+
+	mix := decode[controllers.CategoryMix](t, s.do(http.MethodGet, "/reports/categories"+period, manager, nil))
+	if mix.Quantity != 16 || mix.Revenue != 1320 || len(mix.Categories) != 2 {
+		t.Fatalf("unexpected category mix %+v", mix)
+	}
+	if main := mix.Categories[0]; main.Category != "Main" || main.Quantity != 12 || main.Revenue != 1000 || main.Orders != 2 || main.QuantityShare != 75 || main.RevenueShare != 75.76 {
+		t.Fatalf("unexpected main course sales %+v", main)
+	}
+
+	heatmap := decode[controllers.Heatmap](t, s.do(http.MethodGet, "/reports/heatmap"+period, manager, nil))
+	if heatmap.Orders[time.Friday][20] != 2 || heatmap.Items[time.Friday][20] != 12 || heatmap.Orders[time.Friday][21] != 1 || heatmap.Items[time.Sunday][20] != 3 {
+		t.Fatalf("unexpected heatmap %+v", heatmap)
+	}
+	if heatmap.Peak.Weekday != int(time.Friday) || heatmap.Peak.Hour != 20 || heatmap.Peak.Orders != 2 {
+		t.Fatalf("unexpected peak %+v", heatmap.Peak)
+	}
+}
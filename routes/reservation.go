@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func ReservationRoutes(router *mux.Router) {
+	reservationGroup := router.PathPrefix("/reservations").Subrouter()
+	reservationGroup.Use(middleware.Authentication)
+	reservationGroup.HandleFunc("", controllers.GetReservations).Methods("GET")
+	reservationGroup.HandleFunc("/{id}", controllers.GetReservation).Methods("GET")
+	reservationGroup.HandleFunc("", middleware.Authorize(helpers.PermissionReservations, controllers.CreateReservation)).Methods("POST")
+	reservationGroup.HandleFunc("/{id}/cancel", middleware.Authorize(helpers.PermissionReservations, controllers.CancelReservation)).Methods("POST")
+}
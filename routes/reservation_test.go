@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *testServer) reserve(token string, tableID primitive.ObjectID, partySize int, at time.Time, duration int) *httptest.ResponseRecorder {
+	s.t.Helper()
+	body := models.Reservation{TableID: tableID, CustomerName: "Asha", CustomerPhone: "9876543210", PartySize: partySize, At: at, Duration: duration}
+	return s.do(http.MethodPost, "/reservations", token, body)
+}
+
+func availability(partySize string, at time.Time) string {
+	query := url.Values{}
+	query.Set("partySize", partySize)
+	query.Set("at", at.Format(time.RFC3339))
+	return "/tables/availability?" + query.Encode()
+}
+
+func TestCreateReservation(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	at := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	recorder := s.reserve(token, table.ID, 4, at, 0)
+	expectStatus(t, recorder, http.StatusCreated)
+	reservation := decode[models.Reservation](t, recorder)
+	if reservation.Status != models.ReservationStatusBooked || !reservation.EndsAt.Equal(at.Add(models.ReservationDefaultDuration*time.Minute)) {
+		t.Fatalf("unexpected reservation %+v", reservation)
+	}
+
+	expectStatus(t, s.reserve(token, table.ID, 5, at.Add(4*time.Hour), 60), http.StatusBadRequest)
+	expectStatus(t, s.reserve(token, table.ID, 2, time.Now().Add(-time.Hour), 60), http.StatusBadRequest)
+	expectStatus(t, s.reserve(token, primitive.NewObjectID(), 2, at, 60), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/reservations", token, models.Reservation{TableID: table.ID}), http.StatusBadRequest)
+	expectStatus(t, s.reserve(s.login(models.RoleChef), table.ID, 2, at.Add(4*time.Hour), 60), http.StatusForbidden)
+}
+
+func TestReservationDoubleBooking(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	at := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	expectStatus(t, s.reserve(token, table.ID, 2, at, 90), http.StatusCreated)
+	expectStatus(t, s.reserve(token, table.ID, 2, at.Add(60*time.Minute), 60), http.StatusConflict)
+	expectStatus(t, s.reserve(token, table.ID, 2, at.Add(-30*time.Minute), 45), http.StatusConflict)
+	// Back to back bookings do not overlap
+	expectStatus(t, s.reserve(token, table.ID, 2, at.Add(90*time.Minute), 60), http.StatusCreated)
+	expectStatus(t, s.reserve(token, table.ID, 2, at.Add(-60*time.Minute), 60), http.StatusCreated)
+	// Other tables are not affected
+	expectStatus(t, s.reserve(token, s.createTable(2, 4).ID, 2, at, 90), http.StatusCreated)
+}
+
+func TestCancelReservation(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	at := time.Now().Add(24 * time.Hour)
+	reservation := decode[models.Reservation](t, s.reserve(token, table.ID, 2, at, 90))
+
+	expectStatus(t, s.do(http.MethodPost, "/reservations/"+reservation.ID.Hex()+"/cancel", token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/reservations/"+reservation.ID.Hex()+"/cancel", token, nil), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/reservations/"+primitive.NewObjectID().Hex()+"/cancel", token, nil), http.StatusNotFound)
+
+	// The slot is free again
+	expectStatus(t, s.reserve(token, table.ID, 2, at, 90), http.StatusCreated)
+}
+
+func TestGetReservations(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	at := time.Now().Add(24 * time.Hour)
+	reservation := decode[models.Reservation](t, s.reserve(token, table.ID, 2, at, 60))
+	s.reserve(token, table.ID, 2, at.Add(2*time.Hour), 60)
+
+	recorder := s.do(http.MethodGet, "/reservations", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if reservations := decode[[]models.Reservation](t, recorder); len(reservations) != 2 {
+		t.Fatalf("expected 2 reservations, got %d", len(reservations))
+	}
+
+	recorder = s.do(http.MethodGet, "/reservations/"+reservation.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.Reservation](t, recorder); got.CustomerName != "Asha" || got.PartySize != 2 {
+		t.Fatalf("unexpected reservation %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/reservations/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/reservations/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestTableAvailability(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	large := s.createTable(1, 8)
+	small := s.createTable(2, 2)
+	medium := s.createTable(3, 4)
+	booked := s.createTable(4, 4)
+	at := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	expectStatus(t, s.reserve(token, booked.ID, 4, at.Add(30*time.Minute), 90), http.StatusCreated)
+
+	recorder := s.do(http.MethodGet, availability("4", at), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	tables := decode[[]models.Table](t, recorder)
+	if len(tables) != 2 || tables[0].ID != medium.ID || tables[1].ID != large.ID {
+		t.Fatalf("expected the medium then large table, got %+v", tables)
+	}
+
+	// Once the booking is over the table is free again
+	tables = decode[[]models.Table](t, s.do(http.MethodGet, availability("4", at.Add(2*time.Hour)), token, nil))
+	if len(tables) != 3 || tables[0].ID != medium.ID || tables[1].ID != booked.ID {
+		t.Fatalf("expected the booked table to be free later, got %+v", tables)
+	}
+
+	tables = decode[[]models.Table](t, s.do(http.MethodGet, availability("2", at.Add(2*time.Hour)), token, nil))
+	if len(tables) != 4 || tables[0].ID != small.ID {
+		t.Fatalf("expected the smallest table first, got %+v", tables)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, availability("0", at), token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/tables/availability?partySize=2&at=tonight", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, availability("2", at)+"&duration=-5", token, nil), http.StatusBadRequest)
+}
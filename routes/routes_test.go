@@ -0,0 +1,198 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository"
+	"github.com/MayankSaxena03/Restaurant-Management-System/repository/memory"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMain(m *testing.M) {
+	helpers.SECRETKEY = "test-secret"
+	os.Exit(m.Run())
+}
+
+type testServer struct {
+	t      *testing.T
+	repos  *repository.Repositories
+	router *mux.Router
+}
+
+// newTestServer wires every route to fresh in-memory repositories
+func newTestServer(t *testing.T) *testServer {
+	repos := memory.NewRepositories()
+	controllers.SetRepositories(repos)
+	middleware.SetRepositories(repos)
+
+	router := mux.NewRouter()
+	UserAuthRoutes(router)
+	CreditNoteRoutes(router)
+	FoodRoutes(router)
+	InventoryRoutes(router)
+	InvoiceRoutes(router)
+	KitchenRoutes(router)
+	MenuRoutes(router)
+	OrderRoutes(router)
+	OrderItemRoutes(router)
+	PromotionRoutes(router)
+	PurchaseOrderRoutes(router)
+	ReportRoutes(router)
+	ReservationRoutes(router)
+	SupplierRoutes(router)
+	TableRoutes(router)
+	TaxRoutes(router)
+	UserRoutes(router)
+
+	return &testServer{t: t, repos: repos, router: router}
+}
+
+// do sends body as JSON with the given token and returns the recorded response
+func (s *testServer) do(method string, path string, token string, body interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	request := httptest.NewRequest(method, path, &payload)
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("token", token)
+	}
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// login stores a user with the given role and returns an access token for them
+func (s *testServer) login(role string) string {
+	s.t.Helper()
+	return s.tokenFor(s.createUser(role))
+}
+
+// tokenFor returns an access token for an existing user
+func (s *testServer) tokenFor(user models.User) string {
+	s.t.Helper()
+	token, _, err := helpers.GenerateAllTokens(user.ID, user.Email, user.Username, user.Role, user.TokenVersion)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return token
+}
+
+func (s *testServer) createUser(role string) models.User {
+	s.t.Helper()
+	id := primitive.NewObjectID()
+	user := models.User{
+		ID:        id,
+		Username:  role + "-" + id.Hex()[18:],
+		Password:  helpers.HashPassword("secret"),
+		Email:     id.Hex() + "@example.com",
+		Phone:     "9" + id.Hex()[15:],
+		Role:      role,
+		CreatedOn: time.Now(),
+		UpdatedOn: time.Now(),
+	}
+	if err := s.repos.Users.Create(context.Background(), &user); err != nil {
+		s.t.Fatal(err)
+	}
+	return user
+}
+
+func (s *testServer) createMenu() models.Menu {
+	s.t.Helper()
+	menu := models.Menu{Name: "Dinner", Category: "Main", CreatedOn: time.Now(), UpdatedOn: time.Now()}
+	if err := s.repos.Menus.Create(context.Background(), &menu); err != nil {
+		s.t.Fatal(err)
+	}
+	return menu
+}
+
+func (s *testServer) createFood(name string, price float64) models.Food {
+	s.t.Helper()
+	menu := s.createMenu()
+	food := models.Food{Name: name, Price: price, FoodImage: "food.png", MenuID: menu.ID, CreatedOn: time.Now(), UpdatedOn: time.Now()}
+	if err := s.repos.Foods.Create(context.Background(), &food); err != nil {
+		s.t.Fatal(err)
+	}
+	return food
+}
+
+func (s *testServer) createTable(number int, guests int) models.Table {
+	s.t.Helper()
+	table := models.Table{Number: number, Guests: guests, CreatedOn: time.Now(), UpdatedOn: time.Now()}
+	if err := s.repos.Tables.Create(context.Background(), &table); err != nil {
+		s.t.Fatal(err)
+	}
+	return table
+}
+
+func (s *testServer) createOrder(tableID primitive.ObjectID, status string) models.Order {
+	s.t.Helper()
+	order := models.Order{TableID: tableID, Status: status, CreatedOn: time.Now(), UpdatedOn: time.Now()}
+	if err := s.repos.Orders.Create(context.Background(), &order); err != nil {
+		s.t.Fatal(err)
+	}
+	return order
+}
+
+func (s *testServer) createOrderItem(orderID primitive.ObjectID, food models.Food, quantity int) models.OrderItem {
+	s.t.Helper()
+	orderItem := models.OrderItem{OrderID: orderID, FoodID: food.ID, Quantity: quantity, UnitPrice: food.Price, CreatedOn: time.Now(), UpdatedOn: time.Now()}
+	ids, err := s.repos.OrderItems.CreateMany(context.Background(), []models.OrderItem{orderItem})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	orderItem.ID = ids[0]
+	return orderItem
+}
+
+func decode[T any](t *testing.T, recorder *httptest.ResponseRecorder) T {
+	t.Helper()
+	var value T
+	if err := json.NewDecoder(recorder.Body).Decode(&value); err != nil {
+		t.Fatalf("decoding %q: %v", recorder.Body.String(), err)
+	}
+	return value
+}
+
+// insertedID pulls the hex ID out of messages like "Inserted ID: <id>"
+func insertedID(t *testing.T, recorder *httptest.ResponseRecorder) primitive.ObjectID {
+	t.Helper()
+	message := decode[string](t, recorder)
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(message[strings.LastIndex(message, ":")+1:]))
+	if err != nil {
+		t.Fatalf("no ID in %q", message)
+	}
+	return id
+}
+
+func expectStatus(t *testing.T, recorder *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if recorder.Code != status {
+		t.Fatalf("expected status %d, got %d: %s", status, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	s := newTestServer(t)
+	for _, path := range []string{"/foods", "/menus", "/tables", "/orders", "/orderItems", "/invoices", "/taxes", "/users", "/kitchen/tickets", "/reservations", "/creditNotes", "/promotions", "/inventory/ingredients", "/inventory/low-stock", "/suppliers", "/purchaseOrders", "/reports/margins", "/reports/sales", "/reports/z-reports", "/reports/sellers", "/reports/categories", "/reports/heatmap"} {
+		expectStatus(t, s.do(http.MethodGet, path, "", nil), http.StatusUnauthorized)
+		expectStatus(t, s.do(http.MethodGet, path, "not-a-token", nil), http.StatusUnauthorized)
+	}
+}
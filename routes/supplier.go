@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func SupplierRoutes(router *mux.Router) {
+	supplierGroup := router.PathPrefix("/suppliers").Subrouter()
+	supplierGroup.Use(middleware.Authentication)
+	supplierGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetSuppliers)).Methods("GET")
+	supplierGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.GetSupplier)).Methods("GET")
+	supplierGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageInventory, controllers.CreateSupplier)).Methods("POST")
+	supplierGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageInventory, controllers.UpdateSupplier)).Methods("PATCH")
+}
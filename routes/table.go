@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -10,7 +11,10 @@ func TableRoutes(router *mux.Router) {
 	tableGroup := router.PathPrefix("/tables").Subrouter()
 	tableGroup.Use(middleware.Authentication)
 	tableGroup.HandleFunc("", controllers.GetTables).Methods("GET")
+	tableGroup.HandleFunc("/availability", controllers.GetTableAvailability).Methods("GET")
+	tableGroup.HandleFunc("/status", controllers.GetTableStatuses).Methods("GET")
 	tableGroup.HandleFunc("/{id}", controllers.GetTable).Methods("GET")
-	tableGroup.HandleFunc("", controllers.CreateTable).Methods("POST")
-	tableGroup.HandleFunc("/{id}", controllers.UpdateTable).Methods("PATCH")
+	tableGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageTables, controllers.CreateTable)).Methods("POST")
+	tableGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageTables, controllers.UpdateTable)).Methods("PATCH")
+	tableGroup.HandleFunc("/{id}/status", middleware.Authorize(helpers.PermissionSeatGuests, controllers.UpdateTableStatus)).Methods("POST")
 }
@@ -0,0 +1,194 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetTables(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	s.createTable(1, 4)
+	s.createTable(2, 2)
+
+	recorder := s.do(http.MethodGet, "/tables", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if tables := decode[[]models.Table](t, recorder); len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(tables))
+	}
+}
+
+func TestGetTable(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(7, 4)
+
+	recorder := s.do(http.MethodGet, "/tables/"+table.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.Table](t, recorder); got.Number != 7 {
+		t.Fatalf("unexpected table %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/tables/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/tables/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestCreateTable(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+
+	recorder := s.do(http.MethodPost, "/tables", token, models.Table{Number: 3, Guests: 6})
+	expectStatus(t, recorder, http.StatusCreated)
+	if _, err := s.repos.Tables.Get(context.Background(), insertedID(t, recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/tables", token, models.Table{Number: 3}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/tables", s.login(models.RoleWaiter), models.Table{Number: 3, Guests: 6}), http.StatusForbidden)
+}
+
+func TestUpdateTable(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+	table := s.createTable(3, 4)
+
+	expectStatus(t, s.do(http.MethodPatch, "/tables/"+table.ID.Hex(), token, models.Table{Number: 3, Guests: 8}), http.StatusOK)
+	updated, _ := s.repos.Tables.Get(context.Background(), table.ID)
+	if updated.Guests != 8 {
+		t.Fatalf("expected 8 guests, got %d", updated.Guests)
+	}
+
+	expectStatus(t, s.do(http.MethodPatch, "/tables/"+primitive.NewObjectID().Hex(), token, models.Table{Number: 3, Guests: 8}), http.StatusNotFound)
+}
+
+func (s *testServer) tableStatus(tableID primitive.ObjectID) string {
+	s.t.Helper()
+	table, err := s.repos.Tables.Get(context.Background(), tableID)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return table.Status
+}
+
+func TestTableStatusFollowsOrderAndInvoice(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	cashier := s.login(models.RoleCashier)
+	table := s.createTable(1, 4)
+	food := s.createFood("Naan", 40)
+
+	ids := s.placeOrder(waiter, table.ID, models.OrderItem{FoodID: food.ID, Quantity: 2, UnitPrice: 40})
+	if status := s.tableStatus(table.ID); status != models.TableStatusOrdering {
+		t.Fatalf("expected ordering, got %q", status)
+	}
+
+	orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+	invoice := s.createInvoice(cashier, orderItem.OrderID)
+	if status := s.tableStatus(table.ID); status != models.TableStatusAwaitingBill {
+		t.Fatalf("expected awaiting-bill, got %q", status)
+	}
+
+	s.pay(cashier, invoice.ID, models.PaymentMethodCard, 50)
+	if status := s.tableStatus(table.ID); status != models.TableStatusAwaitingBill {
+		t.Fatalf("a partly paid invoice must not free the table, got %q", status)
+	}
+
+	s.pay(cashier, invoice.ID, models.PaymentMethodCash, 30)
+	if status := s.tableStatus(table.ID); status != models.TableStatusFree {
+		t.Fatalf("expected free, got %q", status)
+	}
+}
+
+func TestCancellingLastOpenOrderFreesTable(t *testing.T) {
+	s := newTestServer(t)
+	waiter := s.login(models.RoleWaiter)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	table := s.createTable(1, 4)
+	food := s.createFood("Naan", 40)
+	order := func() primitive.ObjectID {
+		ids := s.placeOrder(waiter, table.ID, models.OrderItem{FoodID: food.ID, Quantity: 1})
+		orderItem, _ := s.repos.OrderItems.Get(context.Background(), ids[0])
+		return orderItem.OrderID
+	}
+
+	first, second := order(), order()
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+first.Hex()+"/cancel", waiter, nil), http.StatusOK)
+	if status := s.tableStatus(table.ID); status != models.TableStatusOrdering {
+		t.Fatalf("the table still has an order, got %q", status)
+	}
+
+	// An order waiting to be paid keeps the table too
+	invoice := s.createInvoice(cashier, second)
+	third := order()
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+third.Hex()+"/kitchen", waiter, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+third.Hex()+"/void", manager, nil), http.StatusOK)
+	if status := s.tableStatus(table.ID); status == models.TableStatusFree {
+		t.Fatal("a table with a bill to pay must not be freed")
+	}
+
+	s.pay(cashier, invoice.ID, models.PaymentMethodCard, 40)
+	fourth := order()
+	expectStatus(t, s.do(http.MethodPost, "/orders/"+fourth.Hex()+"/cancel", waiter, nil), http.StatusOK)
+	if status := s.tableStatus(table.ID); status != models.TableStatusFree {
+		t.Fatalf("expected free, got %q", status)
+	}
+}
+
+func TestCreateOrderSeatsTable(t *testing.T) {
+	s := newTestServer(t)
+	table := s.createTable(1, 4)
+
+	expectStatus(t, s.do(http.MethodPost, "/orders", s.login(models.RoleWaiter), models.Order{TableID: table.ID}), http.StatusCreated)
+	if status := s.tableStatus(table.ID); status != models.TableStatusSeated {
+		t.Fatalf("expected seated, got %q", status)
+	}
+}
+
+func TestGetTableStatuses(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	second := s.createTable(2, 4)
+	first := s.createTable(1, 2)
+	s.repos.Tables.UpdateStatus(context.Background(), second.ID, models.TableStatusSeated)
+
+	recorder := s.do(http.MethodGet, "/tables/status", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	tables := decode[[]models.Table](t, recorder)
+	if len(tables) != 2 || tables[0].ID != first.ID || tables[0].Status != models.TableStatusFree || tables[1].Status != models.TableStatusSeated {
+		t.Fatalf("unexpected floor %+v", tables)
+	}
+
+	tables = decode[[]models.Table](t, s.do(http.MethodGet, "/tables/status?status=seated", token, nil))
+	if len(tables) != 1 || tables[0].ID != second.ID {
+		t.Fatalf("expected only the seated table, got %+v", tables)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/tables/status?status=dirty", token, nil), http.StatusBadRequest)
+}
+
+func TestUpdateTableStatus(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	table := s.createTable(1, 4)
+	path := "/tables/" + table.ID.Hex() + "/status"
+
+	expectStatus(t, s.do(http.MethodPost, path, token, models.Table{Status: models.TableStatusNeedsCleaning}), http.StatusOK)
+	if status := s.tableStatus(table.ID); status != models.TableStatusNeedsCleaning {
+		t.Fatalf("expected needs-cleaning, got %q", status)
+	}
+
+	// Editing the table does not touch its status
+	expectStatus(t, s.do(http.MethodPatch, "/tables/"+table.ID.Hex(), s.login(models.RoleManager), models.Table{Number: 1, Guests: 6, Status: models.TableStatusFree}), http.StatusOK)
+	if status := s.tableStatus(table.ID); status != models.TableStatusNeedsCleaning {
+		t.Fatalf("expected needs-cleaning, got %q", status)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, path, token, models.Table{Status: "dirty"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/tables/"+primitive.NewObjectID().Hex()+"/status", token, models.Table{Status: models.TableStatusFree}), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPost, path, s.login(models.RoleChef), models.Table{Status: models.TableStatusFree}), http.StatusForbidden)
+}
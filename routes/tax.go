@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
+	"github.com/gorilla/mux"
+)
+
+func TaxRoutes(router *mux.Router) {
+	taxGroup := router.PathPrefix("/taxes").Subrouter()
+	taxGroup.Use(middleware.Authentication)
+	taxGroup.HandleFunc("", controllers.GetTaxRates).Methods("GET")
+	taxGroup.HandleFunc("/{id}", controllers.GetTaxRate).Methods("GET")
+	taxGroup.HandleFunc("", middleware.Authorize(helpers.PermissionManageMenu, controllers.CreateTaxRate)).Methods("POST")
+	taxGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionManageMenu, controllers.UpdateTaxRate)).Methods("PATCH")
+}
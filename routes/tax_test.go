@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetTaxRates(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleWaiter)
+	taxRate := s.createTaxRate("food", false, models.TaxComponent{Name: "GST", Rate: 5})
+	s.createTaxRate("beverage", true, models.TaxComponent{Name: "GST", Rate: 18})
+
+	recorder := s.do(http.MethodGet, "/taxes", token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if taxRates := decode[[]models.TaxRate](t, recorder); len(taxRates) != 2 {
+		t.Fatalf("expected 2 tax rates, got %d", len(taxRates))
+	}
+
+	recorder = s.do(http.MethodGet, "/taxes/"+taxRate.ID.Hex(), token, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if got := decode[models.TaxRate](t, recorder); got.Category != "food" {
+		t.Fatalf("unexpected tax rate %+v", got)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/taxes/"+primitive.NewObjectID().Hex(), token, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/taxes/not-an-id", token, nil), http.StatusBadRequest)
+}
+
+func TestCreateTaxRate(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+	body := models.TaxRate{Name: "GST 5%", Category: "food", Components: []models.TaxComponent{{Name: "CGST", Rate: 2.5}, {Name: "SGST", Rate: 2.5}}}
+
+	expectStatus(t, s.do(http.MethodPost, "/taxes", token, body), http.StatusCreated)
+	expectStatus(t, s.do(http.MethodPost, "/taxes", token, body), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPost, "/taxes", token, models.TaxRate{Name: "Empty", Category: "empty"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/taxes", token, models.TaxRate{Name: "Bad", Category: "bad", Components: []models.TaxComponent{{Name: "X", Rate: 120}}}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/taxes", s.login(models.RoleWaiter), body), http.StatusForbidden)
+}
+
+func TestUpdateTaxRate(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(models.RoleManager)
+	taxRate := s.createTaxRate("food", false, models.TaxComponent{Name: "GST", Rate: 5})
+	s.createTaxRate("beverage", false, models.TaxComponent{Name: "GST", Rate: 18})
+
+	body := models.TaxRate{Name: "GST 12%", Category: "food", Components: []models.TaxComponent{{Name: "GST", Rate: 12}}}
+	expectStatus(t, s.do(http.MethodPatch, "/taxes/"+taxRate.ID.Hex(), token, body), http.StatusOK)
+	updated, _ := s.repos.TaxRates.Get(context.Background(), taxRate.ID)
+	if updated.Components[0].Rate != 12 {
+		t.Fatalf("expected rate 12, got %+v", updated.Components)
+	}
+
+	body.Category = "beverage"
+	expectStatus(t, s.do(http.MethodPatch, "/taxes/"+taxRate.ID.Hex(), token, body), http.StatusConflict)
+	body.Category = "dessert"
+	expectStatus(t, s.do(http.MethodPatch, "/taxes/"+primitive.NewObjectID().Hex(), token, body), http.StatusNotFound)
+}
+
+func TestUnknownTaxCategory(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	cashier := s.login(models.RoleCashier)
+	taxRate := s.createTaxRate("beverage", false, models.TaxComponent{Name: "GST", Rate: 18})
+
+	// A food cannot name a tax category without a rate
+	body := models.Food{Name: "Cold Coffee", Price: 120, FoodImage: "coffee.png", MenuID: s.createMenu().ID, TaxCategory: "bevrage"}
+	expectStatus(t, s.do(http.MethodPost, "/foods", manager, body), http.StatusBadRequest)
+	body.TaxCategory = "beverage"
+	recorder := s.do(http.MethodPost, "/foods", manager, body)
+	expectStatus(t, recorder, http.StatusCreated)
+	coffee, _ := s.repos.Foods.Get(context.Background(), insertedID(t, recorder))
+
+	// Nor is a food billed untaxed once its category's rate has moved on
+	rate := models.TaxRate{Name: "GST 18%", Category: "drinks", Components: taxRate.Components}
+	expectStatus(t, s.do(http.MethodPatch, "/taxes/"+taxRate.ID.Hex(), manager, rate), http.StatusOK)
+	order := s.createOrder(s.createTable(1, 2).ID, models.OrderStatusServed)
+	s.createOrderItem(order.ID, coffee, 1)
+	recorder = s.do(http.MethodPost, "/invoices", cashier, models.Invoice{OrderID: order.ID})
+	expectStatus(t, recorder, http.StatusConflict)
+	if message := decode[string](t, recorder); message != "No tax rate is set up for the beverage tax category of Cold Coffee" {
+		t.Fatalf("unexpected message %q", message)
+	}
+	if invoices, _ := s.repos.Invoices.ListByOrder(context.Background(), order.ID); len(invoices) != 0 {
+		t.Fatalf("expected no invoice, got %d", len(invoices))
+	}
+}
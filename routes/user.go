@@ -1,7 +1,10 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
 	"github.com/MayankSaxena03/Restaurant-Management-System/middleware"
 	"github.com/gorilla/mux"
 )
@@ -9,11 +12,15 @@ import (
 func UserAuthRoutes(router *mux.Router) {
 	router.HandleFunc("/login", controllers.Login).Methods("POST")
 	router.HandleFunc("/signup", controllers.SignUp).Methods("POST")
+	router.HandleFunc("/token/refresh", controllers.RefreshToken).Methods("POST")
+	router.Handle("/logout", middleware.Authentication(http.HandlerFunc(controllers.Logout))).Methods("POST")
 }
 
 func UserRoutes(router *mux.Router) {
 	userGroup := router.PathPrefix("/users").Subrouter()
 	userGroup.Use(middleware.Authentication)
-	userGroup.HandleFunc("", controllers.GetUsers).Methods("GET")
-	userGroup.HandleFunc("/{id}", controllers.GetUser).Methods("GET")
+	userGroup.HandleFunc("", middleware.Authorize(helpers.PermissionReadUsers, controllers.GetUsers)).Methods("GET")
+	userGroup.HandleFunc("/{id}", middleware.Authorize(helpers.PermissionReadUsers, controllers.GetUser)).Methods("GET")
+	userGroup.HandleFunc("/{id}/role", middleware.Authorize(helpers.PermissionManageUsers, controllers.UpdateUserRole)).Methods("PATCH")
+	userGroup.HandleFunc("/{id}/logout", middleware.Authorize(helpers.PermissionRevokeUsers, controllers.ForceLogout)).Methods("POST")
 }
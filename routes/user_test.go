@@ -0,0 +1,236 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/MayankSaxena03/Restaurant-Management-System/controllers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/helpers"
+	"github.com/MayankSaxena03/Restaurant-Management-System/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSignUp(t *testing.T) {
+	s := newTestServer(t)
+	first := models.User{Username: "owner", Password: "secret", Email: "owner@example.com", Phone: "9000000001"}
+
+	recorder := s.do(http.MethodPost, "/signup", "", first)
+	expectStatus(t, recorder, http.StatusCreated)
+	owner := decode[models.User](t, recorder)
+	if owner.Role != models.RoleAdmin || owner.Token == "" {
+		t.Fatalf("expected the first user to be an admin with a token, got %+v", owner)
+	}
+
+	second := models.User{Username: "waiter", Password: "secret", Email: "waiter@example.com", Phone: "9000000002"}
+	recorder = s.do(http.MethodPost, "/signup", "", second)
+	expectStatus(t, recorder, http.StatusCreated)
+	if user := decode[models.User](t, recorder); user.Role != models.RoleWaiter {
+		t.Fatalf("expected later users to be waiters, got %s", user.Role)
+	}
+
+	// Signing up never makes an admin once there are users, even without one
+	if err := s.repos.Users.UpdateRole(context.Background(), owner.ID, models.RoleManager); err != nil {
+		t.Fatal(err)
+	}
+	third := models.User{Username: "intruder", Password: "secret", Email: "intruder@example.com", Phone: "9000000003"}
+	recorder = s.do(http.MethodPost, "/signup", "", third)
+	expectStatus(t, recorder, http.StatusCreated)
+	if user := decode[models.User](t, recorder); user.Role != models.RoleWaiter {
+		t.Fatalf("expected a waiter, got %s", user.Role)
+	}
+
+	expectStatus(t, s.do(http.MethodPost, "/signup", "", first), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/signup", "", models.User{Username: "x"}), http.StatusBadRequest)
+}
+
+func TestSignUpConfiguredAdmin(t *testing.T) {
+	t.Setenv("ADMIN_EMAIL", "owner@example.com")
+	s := newTestServer(t)
+	s.createUser(models.RoleWaiter)
+
+	// The configured admin email takes over an install that has no admin
+	owner := models.User{Username: "owner", Password: "secret", Email: "owner@example.com", Phone: "9000000001"}
+	recorder := s.do(http.MethodPost, "/signup", "", owner)
+	expectStatus(t, recorder, http.StatusCreated)
+	if user := decode[models.User](t, recorder); user.Role != models.RoleAdmin {
+		t.Fatalf("expected the configured admin email to become the admin, got %s", user.Role)
+	}
+}
+
+func TestMigrateRoles(t *testing.T) {
+	t.Setenv("ADMIN_EMAIL", "owner@example.com")
+	s := newTestServer(t)
+	// Users who signed up before roles existed have none
+	staff := s.createUser("")
+	owner := models.User{Username: "owner", Password: helpers.HashPassword("secret"), Email: "owner@example.com", Phone: "9000000001"}
+	if err := s.repos.Users.Create(context.Background(), &owner); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := controllers.MigrateRoles(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := s.repos.Users.Get(context.Background(), staff.ID); user.Role != models.RoleWaiter {
+		t.Fatalf("expected a waiter, got %q", user.Role)
+	}
+	recorder := s.do(http.MethodPost, "/login", "", models.User{Email: owner.Email, Password: "secret"})
+	expectStatus(t, recorder, http.StatusOK)
+	admin := decode[models.User](t, recorder)
+	if admin.Role != models.RoleAdmin {
+		t.Fatalf("expected the configured admin email to become the admin, got %q", admin.Role)
+	}
+	expectStatus(t, s.do(http.MethodPatch, "/users/"+staff.ID.Hex()+"/role", admin.Token, models.User{Role: models.RoleManager}), http.StatusOK)
+
+	// Once there is an admin the configured email is not promoted again
+	if err := s.repos.Users.UpdateRole(context.Background(), owner.ID, models.RoleWaiter); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.repos.Users.UpdateRole(context.Background(), staff.ID, models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if err := controllers.MigrateRoles(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := s.repos.Users.Get(context.Background(), owner.ID); user.Role != models.RoleWaiter {
+		t.Fatalf("expected the existing admin to be kept, got %q", user.Role)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser(models.RoleWaiter)
+
+	recorder := s.do(http.MethodPost, "/login", "", models.User{Email: user.Email, Password: "secret"})
+	expectStatus(t, recorder, http.StatusOK)
+	loggedIn := decode[models.User](t, recorder)
+	expectStatus(t, s.do(http.MethodGet, "/foods", loggedIn.Token, nil), http.StatusOK)
+
+	expectStatus(t, s.do(http.MethodPost, "/login", "", models.User{Email: user.Email, Password: "wrong"}), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/login", "", models.User{Email: "nobody@example.com", Password: "secret"}), http.StatusNotFound)
+}
+
+func TestGetUsers(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(models.RoleAdmin)
+	waiter := s.createUser(models.RoleWaiter)
+
+	// Nobody can read another user's password or tokens
+	expectStatus(t, s.do(http.MethodPost, "/login", "", models.User{Email: waiter.Email, Password: "secret"}), http.StatusOK)
+	recorder := s.do(http.MethodGet, "/users", admin, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	users := decode[[]models.User](t, recorder)
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	for _, user := range users {
+		if user.Password != "" || user.Token != "" || user.RefreshToken != "" {
+			t.Fatalf("expected no secrets, got %+v", user)
+		}
+	}
+
+	recorder = s.do(http.MethodGet, "/users/"+waiter.ID.Hex(), admin, nil)
+	expectStatus(t, recorder, http.StatusOK)
+	if user := decode[models.User](t, recorder); user.Email != waiter.Email || user.Password != "" || user.Token != "" || user.RefreshToken != "" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/users/"+primitive.NewObjectID().Hex(), admin, nil), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodGet, "/users/not-an-id", admin, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/users", s.login(models.RoleManager), nil), http.StatusForbidden)
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(models.RoleAdmin)
+	user := s.createUser(models.RoleWaiter)
+	token := s.login(models.RoleWaiter)
+	userToken := s.tokenFor(user)
+
+	expectStatus(t, s.do(http.MethodPatch, "/users/"+user.ID.Hex()+"/role", admin, models.User{Role: models.RoleCashier}), http.StatusOK)
+	updated, _ := s.repos.Users.Get(context.Background(), user.ID)
+	if updated.Role != models.RoleCashier {
+		t.Fatalf("expected cashier, got %s", updated.Role)
+	}
+	// Tokens carrying the old role stop working
+	expectStatus(t, s.do(http.MethodGet, "/foods", userToken, nil), http.StatusUnauthorized)
+
+	expectStatus(t, s.do(http.MethodPatch, "/users/"+user.ID.Hex()+"/role", admin, models.User{Role: "owner"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPatch, "/users/"+primitive.NewObjectID().Hex()+"/role", admin, models.User{Role: models.RoleChef}), http.StatusNotFound)
+	expectStatus(t, s.do(http.MethodPatch, "/users/"+user.ID.Hex()+"/role", token, models.User{Role: models.RoleAdmin}), http.StatusForbidden)
+}
+
+func TestRefreshToken(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser(models.RoleWaiter)
+	recorder := s.do(http.MethodPost, "/login", "", models.User{Email: user.Email, Password: "secret"})
+	expectStatus(t, recorder, http.StatusOK)
+	loggedIn := decode[models.User](t, recorder)
+
+	// Refresh tokens cannot be used as access tokens
+	expectStatus(t, s.do(http.MethodGet, "/foods", loggedIn.RefreshToken, nil), http.StatusUnauthorized)
+
+	recorder = s.do(http.MethodPost, "/token/refresh", "", models.User{RefreshToken: loggedIn.RefreshToken})
+	expectStatus(t, recorder, http.StatusOK)
+	refreshed := decode[models.User](t, recorder)
+	if refreshed.Token == "" || refreshed.RefreshToken == "" {
+		t.Fatalf("expected new tokens, got %+v", refreshed)
+	}
+
+	// A refresh token can only be used once
+	expectStatus(t, s.do(http.MethodPost, "/token/refresh", "", models.User{RefreshToken: loggedIn.RefreshToken}), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/token/refresh", "", models.User{RefreshToken: loggedIn.Token}), http.StatusUnauthorized)
+
+	// Of several refreshes racing with the same token only one gets through
+	statuses := make(chan int, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(statuses); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses <- s.do(http.MethodPost, "/token/refresh", "", models.User{RefreshToken: refreshed.RefreshToken}).Code
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+	succeeded := 0
+	for status := range statuses {
+		if status == http.StatusOK {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected one refresh to succeed, got %d", succeeded)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser(models.RoleWaiter)
+	token := s.tokenFor(user)
+
+	expectStatus(t, s.do(http.MethodPost, "/logout", token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, "/foods", token, nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/logout", "", nil), http.StatusUnauthorized)
+}
+
+func TestForceLogout(t *testing.T) {
+	s := newTestServer(t)
+	manager := s.login(models.RoleManager)
+	user := s.createUser(models.RoleWaiter)
+	token := s.tokenFor(user)
+
+	expectStatus(t, s.do(http.MethodPost, "/users/"+user.ID.Hex()+"/logout", s.login(models.RoleWaiter), nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodPost, "/users/"+user.ID.Hex()+"/logout", manager, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, "/foods", token, nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodPost, "/users/"+primitive.NewObjectID().Hex()+"/logout", manager, nil), http.StatusNotFound)
+
+	// Managers cannot log out anyone above them, admins log out anyone
+	admin := s.createUser(models.RoleAdmin)
+	adminToken := s.tokenFor(admin)
+	expectStatus(t, s.do(http.MethodPost, "/users/"+admin.ID.Hex()+"/logout", manager, nil), http.StatusForbidden)
+	expectStatus(t, s.do(http.MethodGet, "/foods", adminToken, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/users/"+s.createUser(models.RoleManager).ID.Hex()+"/logout", manager, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/users/"+admin.ID.Hex()+"/logout", s.login(models.RoleAdmin), nil), http.StatusOK)
+}